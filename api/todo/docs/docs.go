@@ -15,9 +15,29 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/todos": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "以 JWKS (RFC 7517) 格式返回访问令牌的校验公钥,供其他服务按令牌头部的 kid 校验令牌。\n响应不使用统一的响应结构,以便 JWT 库直接读取;使用 HS256 签名时返回空集合",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "查询校验公钥",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.JSONWebKeySet"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys": {
             "get": {
-                "description": "查询所有匹配条件的待办事项",
+                "description": "查询当前用户的所有 API Key,包括已吊销的,不返回明文",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,34 +45,108 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Todos"
+                    "API Keys"
                 ],
-                "summary": "查询Todo列表",
+                "summary": "查询API Key列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-array_auth_ApiKeyDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "为当前用户创建个人 API Key,供脚本与 CI 调用接口。请求时放在 X-API-Key 请求头中,\n身份为当前用户,每次请求按当前用户最新的角色授权,用户被移除后 API Key 随之失效;read 只能发起查询请求,write 可以修改。明文仅在创建时返回",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "创建API Key",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "任务标题",
-                        "name": "title",
-                        "in": "query"
+                        "description": "请求参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.CreateApiKeyCommand"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-auth_ApiKeyDTO"
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys/{id}": {
+            "delete": {
+                "description": "吊销当前用户的 API Key,吊销后立即失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "吊销API Key",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
+                        "type": "string",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/webapi.Response-array_todo_TodoDTO"
+                            "$ref": "#/definitions/webapi.Response-auth_ApiKeyDTO"
                         }
                     },
                     "400": {
@@ -61,6 +155,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/webapi.Response-any"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -68,19 +174,22 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/revoke": {
             "post": {
-                "description": "创建新的待办事项",
+                "description": "吊销刷新令牌及同一次登录签发的所有刷新令牌,令牌无效时同样返回成功",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Todos"
+                    "Auth"
                 ],
-                "summary": "创建Todo",
+                "summary": "吊销刷新令牌",
                 "parameters": [
                     {
                         "description": "请求参数",
@@ -88,7 +197,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/todo.CreateTodoCommand"
+                            "$ref": "#/definitions/auth.RevokeTokenCommand"
                         }
                     }
                 ],
@@ -96,7 +205,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/webapi.Response-todo_CreateTodoResult"
+                            "$ref": "#/definitions/webapi.Response-bool"
                         }
                     },
                     "400": {
@@ -114,19 +223,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/todos/completed": {
+        "/auth/token": {
             "post": {
-                "description": "将指定的任务标记为完成",
+                "description": "支持 password、client_credentials 与 refresh_token 三种授权类型,请求体可为 JSON 或表单。\n客户端凭据也可通过 Basic 认证传递。刷新令牌每次使用后轮换,旧令牌再次使用时吊销同一次登录签发的所有令牌",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Todos"
+                    "Auth"
                 ],
-                "summary": "标记任务为完成",
+                "summary": "签发令牌",
                 "parameters": [
                     {
                         "description": "请求参数",
@@ -134,7 +244,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/todo.MarkAsCompletedCommand"
+                            "$ref": "#/definitions/auth.TokenCommand"
                         }
                     }
                 ],
@@ -142,7 +252,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/webapi.Response-bool"
+                            "$ref": "#/definitions/webapi.Response-auth_TokenDTO"
                         }
                     },
                     "400": {
@@ -151,6 +261,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/webapi.Response-any"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -160,9 +276,95 @@ const docTemplate = `{
                 }
             }
         },
-        "/todos/task": {
+        "/todos": {
+            "get": {
+                "description": "查询当前用户创建或被共享的、所有匹配条件的待办事项",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Todos"
+                ],
+                "summary": "查询Todo列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "标题或描述关键词",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码,默认1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页大小,默认10,最大100",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "offset",
+                            "cursor"
+                        ],
+                        "type": "string",
+                        "description": "分页模式",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "游标,取自上一次响应的 nextCursor 或 prevCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "overdue",
+                            "today",
+                            "week"
+                        ],
+                        "type": "string",
+                        "description": "截止时间筛选,只返回未完成的数据",
+                        "name": "due",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "计算今天与本周所用的 IANA 时区,默认 UTC",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-todo_PagedResult-todo_TodoDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "为指定的待办事项添加任务",
+                "description": "创建新的待办事项",
                 "consumes": [
                     "application/json"
                 ],
@@ -172,7 +374,7 @@ const docTemplate = `{
                 "tags": [
                     "Todos"
                 ],
-                "summary": "添加任务",
+                "summary": "创建Todo",
                 "parameters": [
                     {
                         "description": "请求参数",
@@ -180,7 +382,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/todo.AddTodoTaskCommand"
+                            "$ref": "#/definitions/todo.CreateTodoCommand"
                         }
                     }
                 ],
@@ -188,7 +390,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/webapi.Response-bool"
+                            "$ref": "#/definitions/webapi.Response-todo_CreateTodoResult"
                         }
                     },
                     "400": {
@@ -197,6 +399,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/webapi.Response-any"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -206,9 +414,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/todos/{id}": {
-            "get": {
-                "description": "查询指定ID的待办事项",
+        "/todos/completed": {
+            "post": {
+                "description": "将指定的任务标记为完成",
                 "consumes": [
                     "application/json"
                 ],
@@ -218,21 +426,29 @@ const docTemplate = `{
                 "tags": [
                     "Todos"
                 ],
-                "summary": "查询Todo",
+                "summary": "标记任务为完成",
                 "parameters": [
+                    {
+                        "description": "请求参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo.MarkAsCompletedCommand"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "待办事项ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "期望的版本号,取自查询返回的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/webapi.Response-todo_TodoDTO"
+                            "$ref": "#/definitions/webapi.Response-bool"
                         }
                     },
                     "400": {
@@ -241,6 +457,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/webapi.Response-any"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -249,117 +483,1945 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "todo.AddTodoTaskCommand": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string",
-                    "example": "From supermarket"
-                },
-                "title": {
-                    "type": "string",
-                    "example": "Buy milk"
-                },
-                "todoId": {
+        },
+        "/todos/events": {
+            "get": {
+                "description": "以 Server-Sent Events 推送当前用户的待办事项与任务的变更,事件名为领域事件名称(如 todo.created),数据为事件内容。\n断线重连时通过 Last-Event-ID 续传,无法续传时推送 reset 事件,客户端应重新加载数据",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Todos"
+                ],
+                "summary": "订阅Todo变更",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "只接收指定待办事项的事件",
+                        "name": "todoId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "最后收到的事件ID",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "最后收到的事件ID,用于无法设置请求头的客户端",
+                        "name": "lastEventId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "事件流",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/todos/task": {
+            "post": {
+                "description": "为指定的待办事项添加任务",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Todos"
+                ],
+                "summary": "添加任务",
+                "parameters": [
+                    {
+                        "description": "请求参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo.AddTodoTaskCommand"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "期望的版本号,取自查询返回的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/todos/{id}": {
+            "get": {
+                "description": "查询指定ID的待办事项",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Todos"
+                ],
+                "summary": "查询Todo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "待办事项ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-todo_TodoDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "版本号,修改时通过 If-Match 携带"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "更新指定ID的待办事项的标题、描述或完成状态",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Todos"
+                ],
+                "summary": "更新Todo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "待办事项ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "请求参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo.UpdateTodoCommand"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "期望的版本号,取自查询返回的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "删除指定ID的待办事项及其所有任务,需要 owner 角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Todos"
+                ],
+                "summary": "删除Todo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "待办事项ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "期望的版本号,取自查询返回的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/todos/{id}/collaborate": {
+            "get": {
+                "description": "升级为 WebSocket 连接,加入指定待办事项的协作房间。\n连接后推送 snapshot(当前状态),之后推送 presence(在线用户)与 event(领域事件,包括通过 HTTP 接口产生的变更)。\n客户端发送 todo.CollaborationOperation,服务端以 ack 或 rejected 回应,与当前状态冲突或角色不足(viewer 只能查看)的操作被拒绝。\n浏览器无法为 WebSocket 设置 Authorization 请求头,握手时通过子协议传递访问令牌:new WebSocket(url, [\"bearer\", accessToken]),\n即请求头 Sec-WebSocket-Protocol: bearer, \u003caccess_token\u003e,服务端回应 bearer 子协议。跨域握手的 Origin 必须在 web.allowed_origins 中",
+                "tags": [
+                    "Todos"
+                ],
+                "summary": "协作编辑Todo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "待办事项ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "bearer, \u003caccess_token\u003e,用于无法设置 Authorization 请求头的浏览器",
+                        "name": "Sec-WebSocket-Protocol",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "切换为 WebSocket",
+                        "schema": {
+                            "$ref": "#/definitions/todo.CollaborationMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Origin 不在允许的来源中",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/todos/{id}/members": {
+            "get": {
+                "description": "返回创建者与共享成员,创建者排在最前。viewer 及以上角色可以查询",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "查询Todo成员",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "待办事项ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-array_todo_MemberDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "将待办事项共享给同一租户内的用户。viewer 可以查看,editor 可以修改待办事项与任务,owner 还可以删除待办事项与管理成员。\n只有 owner 可以邀请",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "邀请成员",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "待办事项ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "请求参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo.InviteMemberCommand"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "期望的版本号,取自查询返回的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/todos/{id}/members/{userId}": {
+            "put": {
+                "description": "修改共享成员的角色,创建者的角色不能修改。只有 owner 可以修改",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "修改成员角色",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "待办事项ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "成员的用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "请求参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo.ChangeMemberRoleCommand"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "期望的版本号,取自查询返回的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "取消对该用户的共享,创建者不能被移除。只有 owner 可以移除",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "移除成员",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "待办事项ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "成员的用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "期望的版本号,取自查询返回的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/todos/{id}/tasks": {
+            "delete": {
+                "description": "批量删除指定待办事项中的任务",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Todos"
+                ],
+                "summary": "批量删除任务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "待办事项ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "请求参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo.RemoveTodoTasksCommand"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "期望的版本号,取自查询返回的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/todos/{id}/tasks/{taskId}": {
+            "delete": {
+                "description": "删除指定待办事项中的单个任务",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Todos"
+                ],
+                "summary": "删除任务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "待办事项ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "任务ID",
+                        "name": "taskId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "期望的版本号,取自查询返回的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "查询所有 Webhook 订阅",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "查询Webhook列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-array_webhook_WebhookDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "订阅当前用户待办事项与任务的生命周期事件,事件发生后向目标地址 POST 签名的 JSON。\n签名为 X-Webhook-Signature-256: sha256=\u003cHMAC-SHA256(secret, body)\u003e,密钥仅在创建时返回",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "创建Webhook",
+                "parameters": [
+                    {
+                        "description": "请求参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhook.CreateWebhookCommand"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-webhook_WebhookDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "description": "查询指定ID的 Webhook 订阅",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "查询Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-webhook_WebhookDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "修改 Webhook 的目标地址、订阅事件或启用状态",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "更新Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "请求参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhook.UpdateWebhookCommand"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-webhook_WebhookDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "删除 Webhook 订阅及其投递记录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "删除Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "按创建时间倒序查询 Webhook 的投递记录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "查询投递记录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "返回条数,默认20,最大100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-array_webhook_DeliveryDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryId}/redeliver": {
+            "post": {
+                "description": "以原请求体创建新的投递,由后台按正常流程发送",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "重新投递",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "投递记录ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-webhook_DeliveryDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webapi.Response-any"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.ApiKeyDTO": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                },
+                "key": {
+                    "description": "明文,仅创建时返回,请求时放在 X-API-Key 请求头中",
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ci"
+                },
+                "prefix": {
+                    "description": "明文的前几位,用于辨认",
+                    "type": "string",
+                    "example": "tdk_Ab12Cd"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scope": {
+                    "type": "string",
+                    "enum": [
+                        "read",
+                        "write"
+                    ],
+                    "example": "write"
+                }
+            }
+        },
+        "auth.CreateApiKeyCommand": {
+            "type": "object",
+            "required": [
+                "name",
+                "scope"
+            ],
+            "properties": {
+                "expiresAt": {
+                    "description": "过期时间,为空表示不过期",
+                    "type": "string",
+                    "example": "2027-01-01T00:00:00Z"
+                },
+                "name": {
+                    "description": "名称,用于辨认用途",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "scope": {
+                    "description": "权限范围: read 只能查看, write 可以修改",
+                    "type": "string",
+                    "enum": [
+                        "read",
+                        "write"
+                    ]
+                }
+            }
+        },
+        "auth.JSONWebKey": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "description": "RS256 或 ES256",
+                    "type": "string"
+                },
+                "crv": {
+                    "description": "EC 曲线",
+                    "type": "string"
+                },
+                "e": {
+                    "description": "RSA 指数",
+                    "type": "string"
+                },
+                "kid": {
+                    "description": "与访问令牌头部的 kid 对应",
+                    "type": "string"
+                },
+                "kty": {
+                    "description": "RSA 或 EC",
+                    "type": "string"
+                },
+                "n": {
+                    "description": "RSA 模数",
+                    "type": "string"
+                },
+                "use": {
+                    "description": "固定为 sig",
+                    "type": "string"
+                },
+                "x": {
+                    "description": "EC 公钥 x 坐标",
+                    "type": "string"
+                },
+                "y": {
+                    "description": "EC 公钥 y 坐标",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.JSONWebKeySet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.JSONWebKey"
+                    }
+                }
+            }
+        },
+        "auth.RevokeTokenCommand": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "description": "刷新令牌",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.TokenCommand": {
+            "type": "object",
+            "required": [
+                "grant_type"
+            ],
+            "properties": {
+                "client_id": {
+                    "description": "客户端凭据授权的客户端 ID,也可通过 Basic 认证传递",
+                    "type": "string"
+                },
+                "client_secret": {
+                    "description": "客户端凭据授权的客户端密钥",
+                    "type": "string"
+                },
+                "grant_type": {
+                    "description": "授权类型",
+                    "type": "string",
+                    "enum": [
+                        "password",
+                        "client_credentials",
+                        "refresh_token"
+                    ]
+                },
+                "password": {
+                    "description": "密码授权的密码",
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "description": "刷新授权的刷新令牌",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "密码授权的用户名",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.TokenDTO": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "访问令牌有效期(秒)",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "description": "客户端凭据授权不签发刷新令牌",
+                    "type": "string"
+                },
+                "token_type": {
+                    "description": "固定为 Bearer",
+                    "type": "string"
+                }
+            }
+        },
+        "todo.AddTodoTaskCommand": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "From supermarket"
+                },
+                "dueAt": {
+                    "description": "截止时间,不能晚于待办事项的截止时间",
+                    "type": "string",
+                    "example": "2025-01-01T18:00:00+08:00"
+                },
+                "timeZone": {
+                    "description": "截止时间所属的 IANA 时区,默认与待办事项相同",
+                    "type": "string",
+                    "example": "Asia/Shanghai"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Buy milk"
+                },
+                "todoId": {
+                    "type": "string",
+                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                }
+            }
+        },
+        "todo.ChangeMemberRoleCommand": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "description": "角色",
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor",
+                        "owner"
+                    ],
+                    "example": "viewer"
+                }
+            }
+        },
+        "todo.CollaborationMessage": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "错误码,仅 rejected",
+                    "type": "string"
+                },
+                "data": {
+                    "description": "事件内容,仅 event"
+                },
+                "event": {
+                    "description": "事件名称,仅 event",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "错误消息,仅 rejected",
+                    "type": "string"
+                },
+                "requestId": {
+                    "description": "对应操作的请求ID,仅 ack 与 rejected",
+                    "type": "string"
+                },
+                "todo": {
+                    "description": "仅 snapshot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/todo.TodoDTO"
+                        }
+                    ]
+                },
+                "type": {
+                    "type": "string"
+                },
+                "viewers": {
+                    "description": "仅 presence",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/todo.Viewer"
+                    }
+                }
+            }
+        },
+        "todo.CreateTodoCommand": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "description": "描述",
+                    "type": "string"
+                },
+                "dueAt": {
+                    "description": "截止时间",
+                    "type": "string",
+                    "example": "2025-01-01T18:00:00+08:00"
+                },
+                "recurrence": {
+                    "description": "重复规则,daily、weekly、monthly、yearly 或 RRULE,需要设置截止时间",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO"
+                },
+                "timeZone": {
+                    "description": "截止时间所属的 IANA 时区,默认 UTC",
+                    "type": "string",
+                    "example": "Asia/Shanghai"
+                },
+                "title": {
+                    "description": "标题",
+                    "type": "string"
+                }
+            }
+        },
+        "todo.CreateTodoResult": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "description": "是否成功",
+                    "type": "boolean"
+                }
+            }
+        },
+        "todo.InviteMemberCommand": {
+            "type": "object",
+            "required": [
+                "role",
+                "userId"
+            ],
+            "properties": {
+                "role": {
+                    "description": "角色",
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor",
+                        "owner"
+                    ],
+                    "example": "editor"
+                },
+                "userId": {
+                    "description": "被邀请的用户ID",
+                    "type": "string",
+                    "example": "bob"
+                }
+            }
+        },
+        "todo.MarkAsCompletedCommand": {
+            "type": "object",
+            "properties": {
+                "taskId": {
+                    "type": "string",
+                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                },
+                "todoId": {
+                    "type": "string",
+                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                }
+            }
+        },
+        "todo.MemberDTO": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "加入时间",
+                    "type": "string"
+                },
+                "creator": {
+                    "description": "是否为创建者,创建者的角色不能修改",
+                    "type": "boolean",
+                    "example": false
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor",
+                        "owner"
+                    ],
+                    "example": "editor"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "bob"
+                }
+            }
+        },
+        "todo.PagedResult-todo_TodoDTO": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "当前页数据",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/todo.TodoDTO"
+                    }
+                },
+                "nextCursor": {
+                    "description": "下一页游标,仅游标分页返回",
+                    "type": "string",
+                    "example": "eyJ0Ijoi"
+                },
+                "page": {
+                    "description": "页码,仅偏移分页返回",
+                    "type": "integer",
+                    "example": 1
+                },
+                "prevCursor": {
+                    "description": "上一页游标,仅游标分页返回",
+                    "type": "string",
+                    "example": "eyJ0Ijoi"
+                },
+                "size": {
+                    "description": "每页条数",
+                    "type": "integer",
+                    "example": 10
+                },
+                "total": {
+                    "description": "总条数",
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "todo.RemoveTodoTasksCommand": {
+            "type": "object",
+            "required": [
+                "taskIds"
+            ],
+            "properties": {
+                "taskIds": {
+                    "description": "任务ID列表",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                    ]
+                }
+            }
+        },
+        "todo.TaskDTO": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "description": {
+                    "type": "string",
+                    "example": "From supermarket"
+                },
+                "dueAt": {
+                    "description": "截止时间,按 DueTimeZone 表示",
+                    "type": "string",
+                    "example": "2025-01-01T18:00:00+08:00"
+                },
+                "dueTimeZone": {
+                    "description": "截止时间所属的 IANA 时区",
+                    "type": "string",
+                    "example": "Asia/Shanghai"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                },
+                "overdue": {
+                    "description": "未完成且已过截止时间",
+                    "type": "boolean",
+                    "example": false
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Buy milk"
+                },
+                "todoId": {
+                    "type": "string",
+                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                }
+            }
+        },
+        "todo.TodoDTO": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "description": {
+                    "type": "string",
+                    "example": "From supermarket"
+                },
+                "dueAt": {
+                    "description": "截止时间,按 DueTimeZone 表示",
+                    "type": "string",
+                    "example": "2025-01-01T18:00:00+08:00"
+                },
+                "dueTimeZone": {
+                    "description": "截止时间所属的 IANA 时区",
+                    "type": "string",
+                    "example": "Asia/Shanghai"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                },
+                "nextOccurrenceId": {
+                    "description": "已生成的下一次重复",
+                    "type": "string",
+                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                },
+                "occurrence": {
+                    "description": "在重复系列中的序号",
+                    "type": "integer",
+                    "example": 1
+                },
+                "overdue": {
+                    "description": "未完成且已过截止时间",
+                    "type": "boolean",
+                    "example": false
+                },
+                "ownerId": {
+                    "description": "所有者",
+                    "type": "string",
+                    "example": "user-1"
+                },
+                "recurrence": {
+                    "description": "重复规则",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO"
+                },
+                "role": {
+                    "description": "当前用户的角色",
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor",
+                        "owner"
+                    ],
+                    "example": "owner"
+                },
+                "seriesId": {
+                    "description": "重复系列ID",
                     "type": "string",
                     "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/todo.TaskDTO"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Buy milk"
+                },
+                "version": {
+                    "description": "版本号,修改时可通过 If-Match 请求头携带",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "todo.UpdateTodoCommand": {
+            "type": "object",
+            "properties": {
+                "clearDue": {
+                    "description": "清除截止时间",
+                    "type": "boolean",
+                    "example": false
+                },
+                "completed": {
+                    "description": "是否完成",
+                    "type": "boolean",
+                    "example": false
+                },
+                "description": {
+                    "description": "描述",
+                    "type": "string",
+                    "example": "From supermarket"
+                },
+                "dueAt": {
+                    "description": "截止时间",
+                    "type": "string",
+                    "example": "2025-01-01T18:00:00+08:00"
+                },
+                "recurrence": {
+                    "description": "重复规则,daily、weekly、monthly、yearly 或 RRULE,空字符串表示取消重复",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO"
+                },
+                "timeZone": {
+                    "description": "截止时间所属的 IANA 时区,未指定时保持不变",
+                    "type": "string",
+                    "example": "Asia/Shanghai"
+                },
+                "title": {
+                    "description": "标题",
+                    "type": "string",
+                    "example": "Buy milk"
+                }
+            }
+        },
+        "todo.Viewer": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "webapi.Response-any": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "响应码",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "响应数据"
+                },
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "响应消息",
+                    "type": "string"
+                }
+            }
+        },
+        "webapi.Response-array_auth_ApiKeyDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "响应码",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "响应数据",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.ApiKeyDTO"
+                    }
+                },
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "响应消息",
+                    "type": "string"
+                }
+            }
+        },
+        "webapi.Response-array_todo_MemberDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "响应码",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "响应数据",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/todo.MemberDTO"
+                    }
+                },
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "响应消息",
+                    "type": "string"
                 }
             }
         },
-        "todo.CreateTodoCommand": {
+        "webapi.Response-array_webhook_DeliveryDTO": {
             "type": "object",
-            "required": [
-                "title"
-            ],
             "properties": {
-                "description": {
-                    "description": "描述",
+                "code": {
+                    "description": "响应码",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "响应数据",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/webhook.DeliveryDTO"
+                    }
+                },
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
                     "type": "string"
                 },
-                "title": {
-                    "description": "标题",
+                "message": {
+                    "description": "响应消息",
                     "type": "string"
                 }
             }
         },
-        "todo.CreateTodoResult": {
+        "webapi.Response-array_webhook_WebhookDTO": {
             "type": "object",
             "properties": {
-                "success": {
-                    "description": "是否成功",
-                    "type": "boolean"
+                "code": {
+                    "description": "响应码",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "响应数据",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/webhook.WebhookDTO"
+                    }
+                },
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "响应消息",
+                    "type": "string"
                 }
             }
         },
-        "todo.MarkAsCompletedCommand": {
+        "webapi.Response-auth_ApiKeyDTO": {
             "type": "object",
             "properties": {
-                "taskId": {
-                    "type": "string",
-                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                "code": {
+                    "description": "响应码",
+                    "type": "integer"
                 },
-                "todoId": {
-                    "type": "string",
-                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                "data": {
+                    "description": "响应数据",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/auth.ApiKeyDTO"
+                        }
+                    ]
+                },
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "响应消息",
+                    "type": "string"
                 }
             }
         },
-        "todo.TaskDTO": {
+        "webapi.Response-auth_TokenDTO": {
             "type": "object",
             "properties": {
-                "completed": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "description": {
-                    "type": "string",
-                    "example": "From supermarket"
+                "code": {
+                    "description": "响应码",
+                    "type": "integer"
                 },
-                "id": {
-                    "type": "string",
-                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                "data": {
+                    "description": "响应数据",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/auth.TokenDTO"
+                        }
+                    ]
                 },
-                "title": {
-                    "type": "string",
-                    "example": "Buy milk"
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
+                    "type": "string"
                 },
-                "todoId": {
-                    "type": "string",
-                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                "message": {
+                    "description": "响应消息",
+                    "type": "string"
                 }
             }
         },
-        "todo.TodoDTO": {
+        "webapi.Response-bool": {
             "type": "object",
             "properties": {
-                "completed": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "description": {
-                    "type": "string",
-                    "example": "From supermarket"
+                "code": {
+                    "description": "响应码",
+                    "type": "integer"
                 },
-                "id": {
-                    "type": "string",
-                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                "data": {
+                    "description": "响应数据",
+                    "type": "boolean"
                 },
-                "tasks": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/todo.TaskDTO"
-                    }
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
+                    "type": "string"
                 },
-                "title": {
-                    "type": "string",
-                    "example": "Buy milk"
+                "message": {
+                    "description": "响应消息",
+                    "type": "string"
                 }
             }
         },
-        "webapi.Response-any": {
+        "webapi.Response-todo_CreateTodoResult": {
             "type": "object",
             "properties": {
                 "code": {
@@ -367,7 +2429,16 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "响应数据"
+                    "description": "响应数据",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/todo.CreateTodoResult"
+                        }
+                    ]
+                },
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
+                    "type": "string"
                 },
                 "message": {
                     "description": "响应消息",
@@ -375,7 +2446,7 @@ const docTemplate = `{
                 }
             }
         },
-        "webapi.Response-array_todo_TodoDTO": {
+        "webapi.Response-todo_PagedResult-todo_TodoDTO": {
             "type": "object",
             "properties": {
                 "code": {
@@ -384,10 +2455,15 @@ const docTemplate = `{
                 },
                 "data": {
                     "description": "响应数据",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/todo.TodoDTO"
-                    }
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/todo.PagedResult-todo_TodoDTO"
+                        }
+                    ]
+                },
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
+                    "type": "string"
                 },
                 "message": {
                     "description": "响应消息",
@@ -395,7 +2471,7 @@ const docTemplate = `{
                 }
             }
         },
-        "webapi.Response-bool": {
+        "webapi.Response-todo_TodoDTO": {
             "type": "object",
             "properties": {
                 "code": {
@@ -404,7 +2480,15 @@ const docTemplate = `{
                 },
                 "data": {
                     "description": "响应数据",
-                    "type": "boolean"
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/todo.TodoDTO"
+                        }
+                    ]
+                },
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
+                    "type": "string"
                 },
                 "message": {
                     "description": "响应消息",
@@ -412,7 +2496,7 @@ const docTemplate = `{
                 }
             }
         },
-        "webapi.Response-todo_CreateTodoResult": {
+        "webapi.Response-webhook_DeliveryDTO": {
             "type": "object",
             "properties": {
                 "code": {
@@ -423,17 +2507,21 @@ const docTemplate = `{
                     "description": "响应数据",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/todo.CreateTodoResult"
+                            "$ref": "#/definitions/webhook.DeliveryDTO"
                         }
                     ]
                 },
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
+                    "type": "string"
+                },
                 "message": {
                     "description": "响应消息",
                     "type": "string"
                 }
             }
         },
-        "webapi.Response-todo_TodoDTO": {
+        "webapi.Response-webhook_WebhookDTO": {
             "type": "object",
             "properties": {
                 "code": {
@@ -444,15 +2532,158 @@ const docTemplate = `{
                     "description": "响应数据",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/todo.TodoDTO"
+                            "$ref": "#/definitions/webhook.WebhookDTO"
                         }
                     ]
                 },
+                "errorCode": {
+                    "description": "稳定的错误码,仅失败时返回",
+                    "type": "string"
+                },
                 "message": {
                     "description": "响应消息",
                     "type": "string"
                 }
             }
+        },
+        "webhook.CreateWebhookCommand": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "description": "订阅的事件,为空表示全部事件",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "签名密钥,为空时自动生成",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "目标地址",
+                    "type": "string"
+                }
+            }
+        },
+        "webhook.DeliveryDTO": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deliveredAt": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string",
+                    "example": "todo.created"
+                },
+                "eventId": {
+                    "type": "string",
+                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastStatusCode": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "nextAttemptAt": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "请求体",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "succeeded",
+                        "failed"
+                    ],
+                    "example": "succeeded"
+                },
+                "subscriptionId": {
+                    "type": "string",
+                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                }
+            }
+        },
+        "webhook.UpdateWebhookCommand": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "active": {
+                    "description": "是否启用,为空时保持不变",
+                    "type": "boolean"
+                },
+                "events": {
+                    "description": "订阅的事件,为空表示全部事件",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "description": "目标地址",
+                    "type": "string"
+                }
+            }
+        },
+        "webhook.WebhookDTO": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "订阅的事件,为空表示全部事件",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "todo.created",
+                        "todo.completed"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"
+                },
+                "secret": {
+                    "description": "签名密钥,仅创建时返回",
+                    "type": "string",
+                    "example": "3f1c..."
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/todo"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"workit-sample/internal/todo/infrastructure/auth"
+)
+
+// runHashPassword 从标准输入读取密码并输出 bcrypt 摘要,用于填写 auth.users 与 auth.clients,
+// 避免密码出现在命令行历史中
+func runHashPassword() error {
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+
+	if err != nil && line == "" {
+		return fmt.Errorf("read password: %w", err)
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+
+	if password == "" {
+		return errors.New("usage: echo <password> | todo hash-password")
+	}
+
+	hash, err := auth.HashSecret(password)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(hash)
+	return nil
+}
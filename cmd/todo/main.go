@@ -1,19 +1,43 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	_ "time/tzdata" // 内置时区数据库,截止时间的时区校验不依赖系统时区文件
+
 	"workit-sample/internal/todo/application"
 	"workit-sample/internal/todo/domain"
+	"workit-sample/internal/todo/infrastructure"
+	"workit-sample/internal/todo/infrastructure/auth"
 	"workit-sample/internal/todo/webapi"
 
 	_ "workit-sample/api/todo/docs" // swagger 一定要有这行
 
 	"github.com/gin-contrib/cors"
-	"github.com/xiaohangshuhub/go-workit/pkg/database"
 	"github.com/xiaohangshuhub/go-workit/pkg/workit"
+	"go.uber.org/fx"
 )
 
 func main() {
 
+	// 数据库迁移子命令: todo migrate [up | down [steps] | status]
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 生成密码摘要子命令: echo <password> | todo hash-password
+	if len(os.Args) > 1 && os.Args[1] == "hash-password" {
+		if err := runHashPassword(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 创建服务主机构建器
 	builder := workit.NewWebAppBuilder()
 
@@ -22,37 +46,69 @@ func main() {
 		build.AddYamlFile("./application.yaml")
 	})
 
-	// 配置依赖注入
-	builder.AddServices(database.MysqlModule())
+	config := builder.ApplicationBuilder.Config()
+
+	// 基础设施层注入,根据 database.provider 选择存储(mysql, postgres, memory)
+	builder.AddServices(infrastructure.DependencyInjection(config.GetString("database.provider"))...)
+
+	// 启动时执行数据库迁移
+	if config.GetBool("database.auto_migrate") {
+		builder.AddServices(infrastructure.AutoMigrate(config.GetString("database.provider"))...)
+	}
 
 	// 领域层注入
 	builder.AddServices(domain.DependencyInjection()...)
 
 	builder.AddServices(application.DependencyInjection()...)
 
-	builder.AddAuthentication(func(options *workit.AuthenticationOptions) {
+	// 令牌签发与校验共用 auth 节点的签发者、受众与签名密钥
+	authOptions, err := auth.NewOptions(config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-		options.DefaultScheme = "jwt"
+	// 签名密钥环,非对称签名时从密钥目录加载 PEM 私钥并按计划轮换
+	keys, err := auth.NewKeyRing(authOptions)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	}).AddJwtBearer("jwt", func(options *workit.JwtBearerOptions) {
+	bearer := auth.NewBearerHandler(keys, authOptions)
+	apiKeys := auth.NewApiKeyHandler()
 
-		options.TokenValidationParameters = workit.TokenValidationParameters{
-			ValidateIssuer:           true,
-			ValidateAudience:         true,
-			ValidateLifetime:         true,
-			ValidateIssuerSigningKey: true,
-			SigningKey:               []byte("secret"),
-			ValidIssuer:              "sample",
-			ValidAudience:            "sample",
-			RequireExpiration:        true,
-		}
-	})
+	builder.AddServices(fx.Supply(keys, apiKeys))
+
+	// 内置的 JwtBearer 方案只支持单个密钥,按 kid 从密钥环选择公钥需要自定义方案。
+	// 默认方案按请求头选择:携带 X-API-Key 时使用 API Key,否则使用 Bearer 令牌
+	builder.AddAuthentication(func(options *workit.AuthenticationOptions) {
+
+		options.DefaultScheme = "jwt_or_api_key"
+
+	}).
+		AddScheme("jwt", bearer).
+		AddScheme("api_key", apiKeys).
+		AddScheme("jwt_or_api_key", auth.NewSchemeSelector(bearer, apiKeys))
 
 	builder.AddAuthorization(func(options *workit.AuthorizationOptions) {
 		options.DefaultPolicy = ""
 	}).
 		RequireRole("admin_role_policy", "Admin")
 
+	// 令牌接口允许匿名访问
+	builder.AddRouter(func(options *workit.RouterOptions) {
+		options.UseSettings(workit.RouteConfigOptions{Routes: webapi.AnonymousRoutes, AllowAnonymous: true})
+	})
+
+	// 管理员可绕过待办事项的共享权限,用于技术支持
+	builder.AddServices(fx.Supply(webapi.SupportPolicy(builder.Policy("admin_role_policy"))))
+
+	// 允许跨域访问的前端来源,同时用于 WebSocket 握手的 Origin 校验
+	origins := config.GetStringSlice("web.allowed_origins")
+
+	builder.AddServices(fx.Supply(webapi.AllowedOrigins(origins)))
+
 	//构建应用
 	app := builder.Build()
 
@@ -62,7 +118,11 @@ func main() {
 
 	// 配置跨域
 	app.UseCORS(func(c *cors.Config) {
-		c.AllowAllOrigins = true
+		if len(origins) == 0 {
+			c.AllowAllOrigins = true
+		} else {
+			c.AllowOrigins = origins
+		}
 		c.AllowCredentials = true
 	})
 
@@ -72,8 +132,17 @@ func main() {
 	// 配置授权
 	app.UseAuthorization()
 
+	// 解析当前用户,需在鉴权之后
+	app.Use(webapi.NewPrincipalMiddleware)
+
 	// 配置路由
+	app.MapRouter(webapi.RegisterAuthRoutes)
+	app.MapRouter(webapi.RegisterApiKeyRoutes)
 	app.MapRouter(webapi.RegisterTodoRoutes)
+	app.MapRouter(webapi.RegisterMemberRoutes)
+	app.MapRouter(webapi.RegisterTodoEventRoutes)
+	app.MapRouter(webapi.RegisterCollaborationRoutes)
+	app.MapRouter(webapi.RegisterWebhookRoutes)
 
 	// 运行应用
 	app.Run()
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"workit-sample/internal/todo/infrastructure"
+	"workit-sample/internal/todo/infrastructure/migration"
+
+	"github.com/xiaohangshuhub/go-workit/pkg/workit"
+	"go.uber.org/fx"
+)
+
+const migrateUsage = "usage: todo migrate [up | down [steps] | status]"
+
+// runMigrate 执行数据库迁移子命令
+func runMigrate(args []string) error {
+
+	action := "up"
+
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	steps := 1
+
+	switch action {
+	case "up", "status":
+	case "down":
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid steps %q\n%s", args[1], migrateUsage)
+			}
+
+			steps = n
+		}
+	default:
+		return fmt.Errorf("unknown migrate action %q\n%s", action, migrateUsage)
+	}
+
+	builder := workit.NewAppBuilder()
+
+	builder.AddConfig(func(build workit.ConfigBuilder) {
+		build.AddYamlFile("./application.yaml")
+	})
+
+	provider := builder.Config().GetString("database.provider")
+
+	if provider == infrastructure.ProviderMemory {
+		fmt.Println("memory provider does not need migration")
+		return nil
+	}
+
+	host := builder.Build()
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(host.Config()),
+		fx.Supply(host.Logger()),
+		fx.Options(infrastructure.MigrationInjection(provider)...),
+		fx.Invoke(func(migrator *migration.Migrator) error {
+			switch action {
+			case "down":
+				return migrator.Down(steps)
+			case "status":
+				return printStatus(migrator)
+			default:
+				return migrator.Up()
+			}
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	// 启停一次以便释放数据库连接
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := app.Start(ctx); err != nil {
+		return err
+	}
+
+	return app.Stop(ctx)
+}
+
+func printStatus(migrator *migration.Migrator) error {
+
+	statuses, err := migrator.Status()
+
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+
+		appliedAt := "pending"
+
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+
+		fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"time"
+
+	"workit-sample/internal/todo/domain/identity"
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type CreateApiKeyCommand struct {
+	Name      string         `json:"name" binding:"required,max=100"`           // 名称,用于辨认用途
+	Scope     string         `json:"scope" binding:"required,oneof=read write"` // 权限范围: read 只能查看, write 可以修改
+	ExpiresAt *time.Time     `json:"expiresAt" example:"2027-01-01T00:00:00Z"`  // 过期时间,为空表示不过期
+	Principal todo.Principal `json:"-"`                                         // 当前用户,由接口层根据身份信息设置
+}
+
+type RevokeApiKeyCommand struct {
+	ID        string         `uri:"id" binding:"required,uuid"` // API Key ID
+	Principal todo.Principal `json:"-"`                         // 当前用户,由接口层根据身份信息设置
+}
+
+// ApiKeyCommandHandler 创建与吊销当前用户的 API Key
+type ApiKeyCommandHandler struct {
+	repo identity.ApiKeyRepository
+	log  *zap.Logger
+}
+
+func NewApiKeyCommandHandler(repo identity.ApiKeyRepository, log *zap.Logger) *ApiKeyCommandHandler {
+	return &ApiKeyCommandHandler{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (h *ApiKeyCommandHandler) Create(cmd CreateApiKeyCommand) (*ApiKeyDTO, error) {
+
+	key, raw, err := identity.NewApiKey(uuid.New(), cmd.Principal, cmd.Name, cmd.Scope, cmd.ExpiresAt)
+
+	if err != nil {
+		h.log.Error("failed to create api key", zap.Error(err))
+		return nil, err
+	}
+
+	if err := h.repo.Save(key); err != nil {
+		h.log.Error("failed to save api key", zap.Error(err))
+		return nil, err
+	}
+
+	// 明文只在创建时返回一次
+	dto := toApiKeyDTO(key)
+	dto.Key = raw
+
+	return &dto, nil
+}
+
+func (h *ApiKeyCommandHandler) Revoke(cmd RevokeApiKeyCommand) (*ApiKeyDTO, error) {
+
+	id, err := uuid.Parse(cmd.ID)
+
+	if err != nil {
+		h.log.Error("invalid api key id", zap.Error(err))
+		return nil, err
+	}
+
+	key, err := h.repo.Get(id)
+
+	// 其他用户的 API Key 与不存在一样处理,不暴露是否存在
+	if err == nil && !key.Owner().Is(cmd.Principal) {
+		err = identity.ErrApiKeyNotFound
+	}
+
+	if err != nil {
+		h.log.Error("failed to query api key", zap.Error(err))
+		return nil, err
+	}
+
+	key.Revoke(time.Now())
+
+	if err := h.repo.Save(key); err != nil {
+		h.log.Error("failed to revoke api key", zap.Error(err))
+		return nil, err
+	}
+
+	dto := toApiKeyDTO(key)
+
+	return &dto, nil
+}
+
+type ApiKeyListQueryHandler struct {
+	repo identity.ApiKeyRepository
+	log  *zap.Logger
+}
+
+func NewApiKeyListQueryHandler(repo identity.ApiKeyRepository, log *zap.Logger) *ApiKeyListQueryHandler {
+	return &ApiKeyListQueryHandler{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (h *ApiKeyListQueryHandler) Handle(principal todo.Principal) ([]ApiKeyDTO, error) {
+
+	keys, err := h.repo.List(principal)
+
+	if err != nil {
+		h.log.Error("failed to query api keys", zap.Error(err))
+		return nil, err
+	}
+
+	dtos := make([]ApiKeyDTO, 0, len(keys))
+
+	for i := range keys {
+		dtos = append(dtos, toApiKeyDTO(&keys[i]))
+	}
+
+	return dtos, nil
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"time"
+
+	"workit-sample/internal/todo/domain/identity"
+
+	"github.com/google/uuid"
+)
+
+// TokenDTO 令牌响应,字段命名遵循 OAuth 2.0 (RFC 6749)
+type TokenDTO struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`              // 固定为 Bearer
+	ExpiresIn    int64  `json:"expires_in"`              // 访问令牌有效期(秒)
+	RefreshToken string `json:"refresh_token,omitempty"` // 客户端凭据授权不签发刷新令牌
+}
+
+// JSONWebKey 公钥的 JWK 表示 (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`           // RSA 或 EC
+	Use string `json:"use"`           // 固定为 sig
+	Alg string `json:"alg"`           // RS256 或 ES256
+	Kid string `json:"kid"`           // 与访问令牌头部的 kid 对应
+	N   string `json:"n,omitempty"`   // RSA 模数
+	E   string `json:"e,omitempty"`   // RSA 指数
+	Crv string `json:"crv,omitempty"` // EC 曲线
+	X   string `json:"x,omitempty"`   // EC 公钥 x 坐标
+	Y   string `json:"y,omitempty"`   // EC 公钥 y 坐标
+}
+
+// JSONWebKeySet JWKS 文档
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// ApiKeyDTO API Key
+type ApiKeyDTO struct {
+	ID         uuid.UUID  `json:"id" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
+	Name       string     `json:"name" example:"ci"`
+	Prefix     string     `json:"prefix" example:"tdk_Ab12Cd"` // 明文的前几位,用于辨认
+	Scope      string     `json:"scope" example:"write" enums:"read,write"`
+	Key        string     `json:"key,omitempty"` // 明文,仅创建时返回,请求时放在 X-API-Key 请求头中
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func toApiKeyDTO(k *identity.ApiKey) ApiKeyDTO {
+	return ApiKeyDTO{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scope:      k.Scope,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+
+	"workit-sample/internal/todo/domain/identity"
+)
+
+// JwksQueryHandler 查询访问令牌的校验公钥,供其他服务校验本服务签发的令牌
+type JwksQueryHandler struct {
+	keys identity.KeySet
+}
+
+func NewJwksQueryHandler(keys identity.KeySet) *JwksQueryHandler {
+	return &JwksQueryHandler{
+		keys: keys,
+	}
+}
+
+func (h *JwksQueryHandler) Handle() JSONWebKeySet {
+
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+
+	for _, key := range h.keys.PublicKeys() {
+
+		jwk := JSONWebKey{Use: "sig", Alg: key.Algorithm, Kid: key.ID}
+
+		switch public := key.Key.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = encode(public.N.Bytes())
+			jwk.E = encode(big.NewInt(int64(public.E)).Bytes())
+		case *ecdsa.PublicKey:
+			ecdh, err := public.ECDH()
+			if err != nil {
+				continue
+			}
+			// 未压缩格式: 0x04 || X || Y
+			point := ecdh.Bytes()[1:]
+			jwk.Kty = "EC"
+			jwk.Crv = public.Curve.Params().Name
+			jwk.X = encode(point[:len(point)/2])
+			jwk.Y = encode(point[len(point)/2:])
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"workit-sample/internal/todo/domain/identity"
+
+	"go.uber.org/zap"
+)
+
+// RevokeTokenCommand 吊销刷新令牌,同一次登录签发的令牌一并吊销
+type RevokeTokenCommand struct {
+	Token string `json:"token" form:"token" binding:"required"` // 刷新令牌
+}
+
+type RevokeTokenCommandHandler struct {
+	tokens identity.RefreshTokenRepository
+	log    *zap.Logger
+}
+
+func NewRevokeTokenCommandHandler(tokens identity.RefreshTokenRepository, log *zap.Logger) *RevokeTokenCommandHandler {
+	return &RevokeTokenCommandHandler{
+		tokens: tokens,
+		log:    log,
+	}
+}
+
+// Handle 令牌不存在时同样视为成功,避免泄露令牌是否有效 (RFC 7009)
+func (h *RevokeTokenCommandHandler) Handle(cmd RevokeTokenCommand) (bool, error) {
+
+	token, err := h.tokens.GetByHash(identity.HashToken(cmd.Token))
+
+	if errors.Is(err, identity.ErrInvalidGrant) {
+		return true, nil
+	}
+
+	if err != nil {
+		h.log.Error("failed to query refresh token", zap.Error(err))
+		return false, err
+	}
+
+	if err := h.tokens.RevokeFamily(token.FamilyID, time.Now()); err != nil {
+		h.log.Error("failed to revoke refresh token family", zap.Error(err))
+		return false, err
+	}
+
+	return true, nil
+}
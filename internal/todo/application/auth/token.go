@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"workit-sample/internal/todo/domain/identity"
+
+	"go.uber.org/zap"
+)
+
+// 授权类型
+const (
+	GrantPassword          = "password"
+	GrantClientCredentials = "client_credentials"
+	GrantRefreshToken      = "refresh_token"
+)
+
+// TokenCommand 令牌请求,支持 JSON 与表单提交
+type TokenCommand struct {
+	GrantType    string `json:"grant_type" form:"grant_type" binding:"required,oneof=password client_credentials refresh_token"` // 授权类型
+	Username     string `json:"username" form:"username"`                                                                        // 密码授权的用户名
+	Password     string `json:"password" form:"password"`                                                                        // 密码授权的密码
+	ClientID     string `json:"client_id" form:"client_id"`                                                                      // 客户端凭据授权的客户端 ID,也可通过 Basic 认证传递
+	ClientSecret string `json:"client_secret" form:"client_secret"`                                                              // 客户端凭据授权的客户端密钥
+	RefreshToken string `json:"refresh_token" form:"refresh_token"`                                                              // 刷新授权的刷新令牌
+}
+
+type TokenCommandHandler struct {
+	users   identity.UserStore
+	clients identity.ClientStore
+	tokens  identity.RefreshTokenRepository
+	issuer  identity.TokenIssuer
+	log     *zap.Logger
+}
+
+func NewTokenCommandHandler(users identity.UserStore, clients identity.ClientStore, tokens identity.RefreshTokenRepository, issuer identity.TokenIssuer, log *zap.Logger) *TokenCommandHandler {
+	return &TokenCommandHandler{
+		users:   users,
+		clients: clients,
+		tokens:  tokens,
+		issuer:  issuer,
+		log:     log,
+	}
+}
+
+func (h *TokenCommandHandler) Handle(cmd TokenCommand) (*TokenDTO, error) {
+
+	switch cmd.GrantType {
+	case GrantPassword:
+		return h.password(cmd)
+	case GrantClientCredentials:
+		return h.clientCredentials(cmd)
+	default:
+		return h.refresh(cmd)
+	}
+}
+
+// password 校验用户名和密码,签发访问令牌与新家族的刷新令牌
+func (h *TokenCommandHandler) password(cmd TokenCommand) (*TokenDTO, error) {
+
+	user, err := h.users.FindUser(cmd.Username)
+
+	if errors.Is(err, identity.ErrUserNotFound) {
+		identity.VerifyDummy(cmd.Password)
+	}
+
+	if errors.Is(err, identity.ErrUserNotFound) || (err == nil && !user.VerifyPassword(cmd.Password)) {
+		h.log.Warn("invalid credentials", zap.String("username", cmd.Username))
+		return nil, identity.ErrInvalidCredentials
+	}
+
+	if err != nil {
+		h.log.Error("failed to find user", zap.Error(err))
+		return nil, err
+	}
+
+	refresh, raw := identity.NewRefreshToken(user.Username, h.issuer.RefreshTokenTTL())
+
+	if err := h.tokens.Save(refresh); err != nil {
+		h.log.Error("failed to save refresh token", zap.Error(err))
+		return nil, err
+	}
+
+	return h.issue(user.Identity(), raw)
+}
+
+// clientCredentials 校验客户端凭据,只签发访问令牌
+func (h *TokenCommandHandler) clientCredentials(cmd TokenCommand) (*TokenDTO, error) {
+
+	client, err := h.clients.FindClient(cmd.ClientID)
+
+	if errors.Is(err, identity.ErrClientNotFound) {
+		identity.VerifyDummy(cmd.ClientSecret)
+	}
+
+	if errors.Is(err, identity.ErrClientNotFound) || (err == nil && !client.VerifySecret(cmd.ClientSecret)) {
+		h.log.Warn("invalid client", zap.String("client_id", cmd.ClientID))
+		return nil, identity.ErrInvalidClient
+	}
+
+	if err != nil {
+		h.log.Error("failed to find client", zap.Error(err))
+		return nil, err
+	}
+
+	return h.issue(client.Identity(), "")
+}
+
+// refresh 轮换刷新令牌并按用户当前的角色与租户签发访问令牌,
+// 已轮换的令牌再次使用时吊销整个家族
+func (h *TokenCommandHandler) refresh(cmd TokenCommand) (*TokenDTO, error) {
+
+	current, err := h.tokens.GetByHash(identity.HashToken(cmd.RefreshToken))
+
+	if err != nil {
+		h.log.Warn("failed to query refresh token", zap.Error(err))
+		return nil, err
+	}
+
+	now := time.Now()
+
+	next, raw, err := current.Rotate(now, h.issuer.RefreshTokenTTL())
+
+	if err == nil {
+		err = h.tokens.Rotate(current, next)
+	}
+
+	if errors.Is(err, identity.ErrRefreshTokenReused) {
+		h.log.Warn("refresh token reused, revoking family", zap.String("family_id", current.FamilyID.String()), zap.String("subject", current.Subject))
+		return nil, h.revoke(current, now)
+	}
+
+	if err != nil {
+		h.log.Warn("failed to rotate refresh token", zap.Error(err))
+		return nil, err
+	}
+
+	user, err := h.users.FindUser(current.Subject)
+
+	if errors.Is(err, identity.ErrUserNotFound) {
+		h.log.Warn("refresh token subject no longer exists", zap.String("subject", current.Subject))
+		return nil, h.revoke(current, now)
+	}
+
+	if err != nil {
+		h.log.Error("failed to find user", zap.Error(err))
+		return nil, err
+	}
+
+	return h.issue(user.Identity(), raw)
+}
+
+// revoke 吊销令牌所在的家族,并返回 ErrInvalidGrant
+func (h *TokenCommandHandler) revoke(token *identity.RefreshToken, now time.Time) error {
+
+	if err := h.tokens.RevokeFamily(token.FamilyID, now); err != nil {
+		h.log.Error("failed to revoke refresh token family", zap.Error(err))
+		return err
+	}
+
+	return identity.ErrInvalidGrant
+}
+
+func (h *TokenCommandHandler) issue(subject identity.Identity, refreshToken string) (*TokenDTO, error) {
+
+	token, ttl, err := h.issuer.Issue(subject)
+
+	if err != nil {
+		h.log.Error("failed to issue access token", zap.Error(err))
+		return nil, err
+	}
+
+	return &TokenDTO{
+		AccessToken:  token,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(ttl / time.Second),
+		RefreshToken: refreshToken,
+	}, nil
+}
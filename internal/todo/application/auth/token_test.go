@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"workit-sample/internal/todo/domain/identity"
+	"workit-sample/internal/todo/infrastructure/persistence"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// store 测试用的用户与客户端存储
+type store struct {
+	users   map[string]identity.User
+	clients map[string]identity.Client
+}
+
+func (s *store) FindUser(username string) (*identity.User, error) {
+	if user, ok := s.users[username]; ok {
+		return &user, nil
+	}
+	return nil, identity.ErrUserNotFound
+}
+
+func (s *store) FindClient(clientID string) (*identity.Client, error) {
+	if client, ok := s.clients[clientID]; ok {
+		return &client, nil
+	}
+	return nil, identity.ErrClientNotFound
+}
+
+// issuer 以主体作为访问令牌,便于断言
+type issuer struct{}
+
+func (issuer) Issue(subject identity.Identity) (string, time.Duration, error) {
+	return subject.Subject, time.Minute, nil
+}
+
+func (issuer) RefreshTokenTTL() time.Duration {
+	return time.Hour
+}
+
+func newTokenHandler(t *testing.T) (*TokenCommandHandler, *persistence.MemoryRefreshTokenRepository) {
+	t.Helper()
+
+	hash := func(secret string) string {
+		h, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.MinCost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(h)
+	}
+
+	users := &store{
+		users:   map[string]identity.User{"alice": {Username: "alice", PasswordHash: hash("pw123"), TenantID: "acme"}},
+		clients: map[string]identity.Client{"ci": {ID: "ci", SecretHash: hash("s3cret")}},
+	}
+
+	tokens := persistence.NewMemoryRefreshTokenRepository()
+
+	return NewTokenCommandHandler(users, users, tokens, issuer{}, zap.NewNop()), tokens
+}
+
+func TestTokenGrants(t *testing.T) {
+
+	handler, _ := newTokenHandler(t)
+
+	tests := []struct {
+		name    string
+		cmd     TokenCommand
+		subject string // 为空表示应失败
+		refresh bool
+		err     error
+	}{
+		{"password", TokenCommand{GrantType: GrantPassword, Username: "alice", Password: "pw123"}, "alice", true, nil},
+		{"wrong password", TokenCommand{GrantType: GrantPassword, Username: "alice", Password: "wrong"}, "", false, identity.ErrInvalidCredentials},
+		{"unknown user", TokenCommand{GrantType: GrantPassword, Username: "mallory", Password: "pw123"}, "", false, identity.ErrInvalidCredentials},
+		{"client credentials", TokenCommand{GrantType: GrantClientCredentials, ClientID: "ci", ClientSecret: "s3cret"}, "ci", false, nil},
+		{"wrong secret", TokenCommand{GrantType: GrantClientCredentials, ClientID: "ci", ClientSecret: "wrong"}, "", false, identity.ErrInvalidClient},
+		{"unknown client", TokenCommand{GrantType: GrantClientCredentials, ClientID: "other", ClientSecret: "s3cret"}, "", false, identity.ErrInvalidClient},
+		{"unknown refresh token", TokenCommand{GrantType: GrantRefreshToken, RefreshToken: "unknown"}, "", false, identity.ErrInvalidGrant},
+	}
+
+	for _, tt := range tests {
+
+		token, err := handler.Handle(tt.cmd)
+
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+
+		if token.AccessToken != tt.subject || token.TokenType != "Bearer" || (token.RefreshToken != "") != tt.refresh {
+			t.Errorf("%s: unexpected token %+v", tt.name, token)
+		}
+	}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+
+	handler, _ := newTokenHandler(t)
+
+	login, err := handler.Handle(TokenCommand{GrantType: GrantPassword, Username: "alice", Password: "pw123"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err := handler.Handle(TokenCommand{GrantType: GrantRefreshToken, RefreshToken: login.RefreshToken})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rotated.AccessToken != "alice" || rotated.RefreshToken == "" || rotated.RefreshToken == login.RefreshToken {
+		t.Fatalf("expected a new refresh token, got %+v", rotated)
+	}
+
+	// 新令牌可以继续轮换
+	if _, err := handler.Handle(TokenCommand{GrantType: GrantRefreshToken, RefreshToken: rotated.RefreshToken}); err != nil {
+		t.Fatalf("rotate again: %v", err)
+	}
+}
+
+// 重放已轮换的令牌视为泄露,同一家族的所有令牌都被吊销,其他登录不受影响
+func TestReusedRefreshTokenRevokesFamily(t *testing.T) {
+
+	handler, tokens := newTokenHandler(t)
+
+	login, err := handler.Handle(TokenCommand{GrantType: GrantPassword, Username: "alice", Password: "pw123"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := handler.Handle(TokenCommand{GrantType: GrantPassword, Username: "alice", Password: "pw123"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err := handler.Handle(TokenCommand{GrantType: GrantRefreshToken, RefreshToken: login.RefreshToken})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := handler.Handle(TokenCommand{GrantType: GrantRefreshToken, RefreshToken: login.RefreshToken}); !errors.Is(err, identity.ErrInvalidGrant) {
+		t.Fatalf("expected replay to fail with ErrInvalidGrant, got %v", err)
+	}
+
+	for _, raw := range []string{login.RefreshToken, rotated.RefreshToken} {
+
+		token, err := tokens.GetByHash(identity.HashToken(raw))
+
+		if err != nil || token.RevokedAt == nil {
+			t.Errorf("expected token of the reused family to be revoked, got %+v, %v", token, err)
+		}
+	}
+
+	if _, err := handler.Handle(TokenCommand{GrantType: GrantRefreshToken, RefreshToken: rotated.RefreshToken}); !errors.Is(err, identity.ErrInvalidGrant) {
+		t.Errorf("expected latest token of the family to be rejected, got %v", err)
+	}
+
+	if _, err := handler.Handle(TokenCommand{GrantType: GrantRefreshToken, RefreshToken: other.RefreshToken}); err != nil {
+		t.Errorf("expected another family to stay valid, got %v", err)
+	}
+}
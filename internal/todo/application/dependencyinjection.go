@@ -1,7 +1,10 @@
 package application
 
 import (
+	"workit-sample/internal/todo/application/auth"
 	todo "workit-sample/internal/todo/application/todo"
+	"workit-sample/internal/todo/application/webhook"
+	"workit-sample/internal/todo/domain"
 
 	"go.uber.org/fx"
 )
@@ -14,6 +17,35 @@ func DependencyInjection() []fx.Option {
 		fx.Provide(todo.NewAddTodoTaskCommandHandler),
 		fx.Provide(todo.NewTodoQueryHandler),
 		fx.Provide(todo.NewMarkAsCompletedCommandHandler),
+		fx.Provide(todo.NewUpdateTodoCommandHandler),
+		fx.Provide(todo.NewDeleteTodoCommandHandler),
+		fx.Provide(todo.NewRemoveTodoTaskCommandHandler),
+		fx.Provide(todo.NewRemoveTodoTasksCommandHandler),
+		fx.Provide(todo.NewMemberCommandHandler),
+		fx.Provide(todo.NewMemberListQueryHandler),
+		fx.Provide(todo.NewEventStream),
+		fx.Provide(todo.NewCollaborationHub),
+		fx.Provide(todo.NewCollaborationCommandHandler),
+
+		fx.Provide(webhook.NewCreateWebhookCommandHandler),
+		fx.Provide(webhook.NewWebhookListQueryHandler),
+		fx.Provide(webhook.NewWebhookQueryHandler),
+		fx.Provide(webhook.NewUpdateWebhookCommandHandler),
+		fx.Provide(webhook.NewDeleteWebhookCommandHandler),
+		fx.Provide(webhook.NewDeliveryListQueryHandler),
+		fx.Provide(webhook.NewRedeliverCommandHandler),
+
+		fx.Provide(auth.NewTokenCommandHandler),
+		fx.Provide(auth.NewRevokeTokenCommandHandler),
+		fx.Provide(auth.NewJwksQueryHandler),
+		fx.Provide(auth.NewApiKeyCommandHandler),
+		fx.Provide(auth.NewApiKeyListQueryHandler),
+
+		// 领域事件处理器
+		domain.AsEventHandler(todo.NewEventLogHandler),
+		// 事件流与协作房间同时供接口层使用,注册为处理器时复用同一实例
+		domain.AsEventHandler(func(stream *todo.EventStream) *todo.EventStream { return stream }),
+		domain.AsEventHandler(func(hub *todo.CollaborationHub) *todo.CollaborationHub { return hub }),
 	}
 
 }
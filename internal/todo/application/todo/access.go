@@ -0,0 +1,24 @@
+package todo
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+)
+
+// getAuthorized 加载待办事项并校验当前用户至少拥有 role 角色,
+// 无权访问时与不存在一样返回 ErrTodoNotFound,不暴露数据是否存在
+func getAuthorized(repo todo.TodoRepository, id uuid.UUID, principal todo.Principal, role string) (*todo.Todo, error) {
+
+	entity, err := repo.Get(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := entity.Authorize(principal, role); err != nil {
+		return nil, err
+	}
+
+	return entity, nil
+}
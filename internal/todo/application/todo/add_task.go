@@ -1,55 +1,75 @@
 package todo
 
 import (
+	"time"
+
 	"workit-sample/internal/todo/domain/todo"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 type AddTodoTaskCommand struct {
-	TodoID      uuid.UUID `json:"todoId" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
-	Title       string    `json:"title" example:"Buy milk"`
-	Description *string   `json:"description" example:"From supermarket"`
+	TodoID          uuid.UUID      `json:"todoId" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
+	Title           string         `json:"title" example:"Buy milk"`
+	Description     *string        `json:"description" example:"From supermarket"`
+	DueAt           *time.Time     `json:"dueAt" example:"2025-01-01T18:00:00+08:00"` // 截止时间,不能晚于待办事项的截止时间
+	TimeZone        string         `json:"timeZone" example:"Asia/Shanghai"`          // 截止时间所属的 IANA 时区,默认与待办事项相同
+	ExpectedVersion *int64         `json:"-"`                                         // 期望的版本号,取自 If-Match 请求头
+	Principal       todo.Principal `json:"-"`                                         // 当前用户,由接口层根据身份信息设置
 }
 
 type AddTodoTaskCommandHandler struct {
-	db  *gorm.DB
+	uow todo.UnitOfWork
 	log *zap.Logger
 }
 
-func NewAddTodoTaskCommandHandler(db *gorm.DB, log *zap.Logger) *AddTodoTaskCommandHandler {
+func NewAddTodoTaskCommandHandler(uow todo.UnitOfWork, log *zap.Logger) *AddTodoTaskCommandHandler {
 	return &AddTodoTaskCommandHandler{
-		db:  db,
+		uow: uow,
 		log: log,
 	}
 }
 
 func (h *AddTodoTaskCommandHandler) Handle(cmd AddTodoTaskCommand) (bool, error) {
 
-	todo := todo.Todo{}
+	err := h.uow.Execute(func(repo todo.TodoRepository) error {
 
-	// 使用 Preload 加载关联的 Tasks
-	result := h.db.Preload("Tasks").First(&todo, "id = ?", cmd.TodoID)
+		todo, err := getAuthorized(repo, cmd.TodoID, cmd.Principal, todo.RoleEditor)
 
-	if result.Error != nil {
-		h.log.Error("failed to query todoList", zap.Error(result.Error))
-		return false, result.Error
-	}
+		if err != nil {
+			h.log.Error("failed to query todoList", zap.Error(err))
+			return err
+		}
 
-	err := todo.AddTask(uuid.New(), cmd.Title, cmd.Description)
+		if err := todo.CheckVersion(cmd.ExpectedVersion); err != nil {
+			h.log.Error("todo version conflict", zap.Error(err))
+			return err
+		}
 
-	if err != nil {
-		h.log.Error("failed to add task", zap.Error(err))
-		return false, err
-	}
+		timeZone := cmd.TimeZone
+
+		if timeZone == "" {
+			timeZone = todo.DueTimeZone
+		}
 
-	tx := h.db.Session(&gorm.Session{FullSaveAssociations: true}).Save(&todo)
+		err = todo.AddTask(uuid.New(), cmd.Title, cmd.Description, cmd.DueAt, timeZone)
 
-	if tx.Error != nil {
-		h.log.Error("failed to save task", zap.Error(tx.Error))
-		return false, tx.Error
+		if err != nil {
+			h.log.Error("failed to add task", zap.Error(err))
+			return err
+		}
+
+		if err := repo.Save(todo); err != nil {
+			h.log.Error("failed to save task", zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return false, err
 	}
 
 	return true, nil
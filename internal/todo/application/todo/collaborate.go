@@ -0,0 +1,147 @@
+package todo
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 协作操作类型
+const (
+	OperationSnapshot     = "snapshot"      // 重新获取快照
+	OperationAddTask      = "add_task"      // 添加任务
+	OperationCompleteTask = "complete_task" // 完成任务
+	OperationRemoveTask   = "remove_task"   // 删除任务
+)
+
+// collaborationRetries 操作因并发修改导致版本冲突时的重试次数,重试时按最新状态重新判断
+const collaborationRetries = 3
+
+// CollaborationOperation 客户端通过协作通道发送的操作
+type CollaborationOperation struct {
+	Type        string         `json:"type" binding:"required,oneof=snapshot add_task complete_task remove_task"`
+	RequestID   string         `json:"requestId"`                       // 客户端生成的请求ID,原样返回
+	TaskID      string         `json:"taskId" binding:"omitempty,uuid"` // complete_task, remove_task
+	Title       string         `json:"title"`                           // add_task
+	Description *string        `json:"description"`                     // add_task
+	DueAt       *time.Time     `json:"dueAt"`                           // add_task
+	TimeZone    string         `json:"timeZone"`                        // add_task
+	Principal   todo.Principal `json:"-"`                               // 当前用户,由接口层根据身份信息设置
+}
+
+// CollaborationCommandHandler 执行协作操作。操作复用现有命令处理器,变更通过领域事件广播到房间;
+// 与聚合当前状态冲突的操作(任务已删除、已完成、标题重复等)被拒绝
+type CollaborationCommandHandler struct {
+	repo            todo.TodoRepository
+	addTask         *AddTodoTaskCommandHandler
+	markAsCompleted *MarkAsCompletedCommandHandler
+	removeTask      *RemoveTodoTaskCommandHandler
+	log             *zap.Logger
+}
+
+func NewCollaborationCommandHandler(
+	repo todo.TodoRepository,
+	addTask *AddTodoTaskCommandHandler,
+	markAsCompleted *MarkAsCompletedCommandHandler,
+	removeTask *RemoveTodoTaskCommandHandler,
+	log *zap.Logger,
+) *CollaborationCommandHandler {
+	return &CollaborationCommandHandler{
+		repo:            repo,
+		addTask:         addTask,
+		markAsCompleted: markAsCompleted,
+		removeTask:      removeTask,
+		log:             log,
+	}
+}
+
+func (h *CollaborationCommandHandler) Handle(todoID uuid.UUID, op CollaborationOperation) error {
+
+	for attempt := 1; ; attempt++ {
+
+		err := h.apply(todoID, op)
+
+		if errors.Is(err, todo.ErrTodoVersionConflict) && attempt < collaborationRetries {
+			continue
+		}
+
+		return err
+	}
+}
+
+func (h *CollaborationCommandHandler) apply(todoID uuid.UUID, op CollaborationOperation) error {
+
+	switch op.Type {
+	case OperationAddTask:
+		_, err := h.addTask.Handle(AddTodoTaskCommand{
+			TodoID:      todoID,
+			Principal:   op.Principal,
+			Title:       op.Title,
+			Description: op.Description,
+			DueAt:       op.DueAt,
+			TimeZone:    op.TimeZone,
+		})
+		return err
+
+	case OperationRemoveTask:
+		if op.TaskID == "" {
+			return todo.ErrTaskNotFound
+		}
+
+		_, err := h.removeTask.Handle(RemoveTodoTaskCommand{
+			TodoID:    todoID.String(),
+			TaskID:    op.TaskID,
+			Principal: op.Principal,
+		})
+		return err
+
+	case OperationCompleteTask:
+		return h.completeTask(todoID, op)
+	}
+
+	return nil
+}
+
+// completeTask 完成任务本身是幂等的,协作时需要拒绝已完成的任务,
+// 因此先按当前状态判断,再以读取时的版本执行,保证判断与写入之间没有其他修改
+func (h *CollaborationCommandHandler) completeTask(todoID uuid.UUID, op CollaborationOperation) error {
+
+	taskID, err := uuid.Parse(op.TaskID)
+
+	if err != nil {
+		return todo.ErrTaskNotFound
+	}
+
+	current, err := getAuthorized(h.repo, todoID, op.Principal, todo.RoleEditor)
+
+	if err != nil {
+		h.log.Error("failed to query todo", zap.Error(err))
+		return err
+	}
+
+	index := slices.IndexFunc(current.Tasks, func(task todo.Task) bool {
+		return task.ID == taskID
+	})
+
+	if index < 0 {
+		return todo.ErrTaskNotFound
+	}
+
+	if current.Tasks[index].Completed {
+		return todo.ErrTaskAlreadyCompleted
+	}
+
+	_, err = h.markAsCompleted.Handle(MarkAsCompletedCommand{
+		TodoID:          todoID,
+		TaskID:          taskID,
+		ExpectedVersion: &current.Version,
+		Principal:       op.Principal,
+	})
+
+	return err
+}
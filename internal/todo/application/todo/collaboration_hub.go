@@ -0,0 +1,250 @@
+package todo
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 协作通道消息类型
+const (
+	MessageSnapshot = "snapshot" // 待办事项当前状态
+	MessagePresence = "presence" // 正在查看的用户
+	MessageEvent    = "event"    // 领域事件
+	MessageAck      = "ack"      // 操作成功
+	MessageRejected = "rejected" // 操作被拒绝
+)
+
+// participantBuffer 单个连接未发送消息上限,超过后断开该连接,由客户端重连获取快照
+const participantBuffer = 32
+
+// Viewer 正在查看待办事项的用户
+type Viewer struct {
+	UserID string `json:"userId"`
+	Name   string `json:"name"`
+}
+
+// CollaborationMessage 协作通道推送给客户端的消息
+type CollaborationMessage struct {
+	Type      string   `json:"type"`
+	RequestID string   `json:"requestId,omitempty"` // 对应操作的请求ID,仅 ack 与 rejected
+	Todo      *TodoDTO `json:"todo,omitempty"`      // 仅 snapshot
+	Viewers   []Viewer `json:"viewers,omitempty"`   // 仅 presence
+	Event     string   `json:"event,omitempty"`     // 事件名称,仅 event
+	Data      any      `json:"data,omitempty"`      // 事件内容,仅 event
+	Code      string   `json:"code,omitempty"`      // 错误码,仅 rejected
+	Message   string   `json:"message,omitempty"`   // 错误消息,仅 rejected
+}
+
+// Participant 协作房间中的一个连接
+type Participant struct {
+	Viewer
+	messages chan CollaborationMessage
+	room     *collaborationRoom
+}
+
+// Messages 返回待发送的消息,连接被移出房间时通道关闭
+func (p *Participant) Messages() <-chan CollaborationMessage {
+	return p.messages
+}
+
+// Send 只发送给当前连接
+func (p *Participant) Send(message CollaborationMessage) {
+	p.room.send(p, message)
+}
+
+// collaborationRoom 同一待办事项的协作房间
+type collaborationRoom struct {
+	todoID       uuid.UUID
+	participants map[*Participant]struct{}
+	closed       bool // 房间已空并从 hub 中移除,加入方需重新获取房间
+	log          *zap.Logger
+	mu           sync.Mutex
+}
+
+func (r *collaborationRoom) send(p *Participant, message CollaborationMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.participants[p]; ok {
+		r.deliver(p, message)
+	}
+}
+
+func (r *collaborationRoom) broadcast(message CollaborationMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for p := range r.participants {
+		r.deliver(p, message)
+	}
+}
+
+// deliver 非阻塞发送,连接积压过多时将其移出房间
+func (r *collaborationRoom) deliver(p *Participant, message CollaborationMessage) {
+	select {
+	case p.messages <- message:
+	default:
+		r.log.Warn("collaboration participant is too slow, disconnecting",
+			zap.Stringer("todo_id", r.todoID),
+			zap.String("user_id", p.UserID))
+		delete(r.participants, p)
+		close(p.messages)
+	}
+}
+
+// viewers 按用户去重,同一用户的多个连接只显示一次
+func (r *collaborationRoom) viewers() []Viewer {
+
+	viewers := make([]Viewer, 0, len(r.participants))
+
+	for p := range r.participants {
+		if !slices.ContainsFunc(viewers, func(v Viewer) bool { return v.UserID == p.UserID }) {
+			viewers = append(viewers, p.Viewer)
+		}
+	}
+
+	slices.SortFunc(viewers, func(a, b Viewer) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	return viewers
+}
+
+// CollaborationHub 管理所有待办事项的协作房间,房间在首个连接加入时创建、最后一个连接离开时移除。
+// hub 只在查找和增删房间时加锁,房间内的广播只锁定该房间
+type CollaborationHub struct {
+	rooms map[uuid.UUID]*collaborationRoom
+	log   *zap.Logger
+	mu    sync.RWMutex
+}
+
+func NewCollaborationHub(log *zap.Logger) *CollaborationHub {
+	return &CollaborationHub{
+		rooms: make(map[uuid.UUID]*collaborationRoom),
+		log:   log,
+	}
+}
+
+// Join 加入待办事项的协作房间,先向新连接发送快照,再向房间广播在线用户
+func (h *CollaborationHub) Join(snapshot *TodoDTO, viewer Viewer) *Participant {
+
+	for {
+		room := h.room(snapshot.ID)
+
+		room.mu.Lock()
+
+		// 房间在获取后被最后一个连接关闭,重新获取
+		if room.closed {
+			room.mu.Unlock()
+			continue
+		}
+
+		p := &Participant{
+			Viewer:   viewer,
+			messages: make(chan CollaborationMessage, participantBuffer),
+			room:     room,
+		}
+
+		room.participants[p] = struct{}{}
+		room.deliver(p, CollaborationMessage{Type: MessageSnapshot, Todo: snapshot})
+
+		presence := CollaborationMessage{Type: MessagePresence, Viewers: room.viewers()}
+
+		for other := range room.participants {
+			room.deliver(other, presence)
+		}
+
+		room.mu.Unlock()
+
+		return p
+	}
+}
+
+// Leave 离开协作房间,房间为空时移除
+func (h *CollaborationHub) Leave(p *Participant) {
+
+	room := p.room
+
+	room.mu.Lock()
+
+	if _, ok := room.participants[p]; ok {
+		delete(room.participants, p)
+		close(p.messages)
+	}
+
+	empty := len(room.participants) == 0
+
+	if empty {
+		room.closed = true
+	} else {
+		presence := CollaborationMessage{Type: MessagePresence, Viewers: room.viewers()}
+
+		for other := range room.participants {
+			room.deliver(other, presence)
+		}
+	}
+
+	room.mu.Unlock()
+
+	if empty {
+		h.mu.Lock()
+		if h.rooms[room.todoID] == room {
+			delete(h.rooms, room.todoID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Handle 将待办事项的领域事件广播到对应房间,没有连接的待办事项直接忽略
+func (h *CollaborationHub) Handle(event todo.Event) error {
+
+	h.mu.RLock()
+	room, ok := h.rooms[event.AggregateID()]
+	h.mu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	room.broadcast(CollaborationMessage{
+		Type:  MessageEvent,
+		Event: event.Metadata().EventName,
+		Data:  event,
+	})
+
+	return nil
+}
+
+func (h *CollaborationHub) room(todoID uuid.UUID) *collaborationRoom {
+
+	h.mu.RLock()
+	room, ok := h.rooms[todoID]
+	h.mu.RUnlock()
+
+	if ok {
+		return room
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if room, ok := h.rooms[todoID]; ok {
+		return room
+	}
+
+	room = &collaborationRoom{
+		todoID:       todoID,
+		participants: make(map[*Participant]struct{}),
+		log:          h.log,
+	}
+
+	h.rooms[todoID] = room
+
+	return room
+}
@@ -0,0 +1,198 @@
+package todo
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// presence 读取下一条消息,要求为在线用户列表并返回其中的用户ID
+func presence(t *testing.T, p *Participant) []string {
+	t.Helper()
+
+	message, ok := <-p.Messages()
+
+	if !ok || message.Type != MessagePresence {
+		t.Fatalf("expected presence message, got %+v", message)
+	}
+
+	users := make([]string, len(message.Viewers))
+
+	for i, viewer := range message.Viewers {
+		users[i] = viewer.UserID
+	}
+
+	return users
+}
+
+// join 加入房间,要求新连接先收到快照
+func join(t *testing.T, hub *CollaborationHub, todoID uuid.UUID, user string) *Participant {
+	t.Helper()
+
+	p := hub.Join(&TodoDTO{ID: todoID}, Viewer{UserID: user})
+
+	if message := <-p.Messages(); message.Type != MessageSnapshot || message.Todo.ID != todoID {
+		t.Fatalf("expected snapshot first, got %+v", message)
+	}
+
+	return p
+}
+
+func TestCollaborationHubPresence(t *testing.T) {
+
+	hub := NewCollaborationHub(zap.NewNop())
+	todoID := uuid.New()
+
+	a := join(t, hub, todoID, "alice")
+
+	if users := presence(t, a); !slices.Equal(users, []string{"alice"}) {
+		t.Fatalf("expected alice, got %v", users)
+	}
+
+	b := join(t, hub, todoID, "bob")
+
+	for _, p := range []*Participant{a, b} {
+		if users := presence(t, p); !slices.Equal(users, []string{"alice", "bob"}) {
+			t.Fatalf("%s: expected alice and bob, got %v", p.UserID, users)
+		}
+	}
+
+	// 同一用户的第二个连接只显示一次
+	again := join(t, hub, todoID, "alice")
+
+	for _, p := range []*Participant{a, b, again} {
+		if users := presence(t, p); !slices.Equal(users, []string{"alice", "bob"}) {
+			t.Fatalf("%s: expected alice and bob, got %v", p.UserID, users)
+		}
+	}
+
+	hub.Leave(b)
+
+	if _, open := <-b.Messages(); open {
+		t.Fatal("expected messages of a leaving participant to be closed")
+	}
+
+	for _, p := range []*Participant{a, again} {
+		if users := presence(t, p); !slices.Equal(users, []string{"alice"}) {
+			t.Fatalf("expected alice only, got %v", users)
+		}
+	}
+
+	hub.Leave(a)
+	presence(t, again)
+	hub.Leave(again)
+
+	// 最后一个连接离开后移除房间,重复离开不会出错
+	hub.Leave(again)
+
+	if len(hub.rooms) != 0 {
+		t.Fatalf("expected empty room to be removed, got %d rooms", len(hub.rooms))
+	}
+
+	// 房间移除后可以重新加入
+	if users := presence(t, join(t, hub, todoID, "bob")); !slices.Equal(users, []string{"bob"}) {
+		t.Fatalf("expected bob, got %v", users)
+	}
+}
+
+func TestCollaborationHubBroadcastsEventsToRoom(t *testing.T) {
+
+	hub := NewCollaborationHub(zap.NewNop())
+
+	entity, err := todo.NewTodo(uuid.New(), alice, "Groceries")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := entity.ClearEvents()[0]
+
+	member := join(t, hub, entity.ID, "alice")
+	other := join(t, hub, uuid.New(), "alice")
+
+	presence(t, member)
+	presence(t, other)
+
+	if err := hub.Handle(event); err != nil {
+		t.Fatal(err)
+	}
+
+	// 没有连接的待办事项直接忽略
+	if err := hub.Handle(created(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	hub.Leave(member)
+	hub.Leave(other)
+
+	if message := <-member.Messages(); message.Type != MessageEvent || message.Event != todo.EventTodoCreated {
+		t.Fatalf("expected %s event, got %+v", todo.EventTodoCreated, message)
+	}
+
+	if message, open := <-other.Messages(); open {
+		t.Fatalf("expected no event for another todo, got %+v", message)
+	}
+}
+
+// 协作操作按待办事项逐个校验角色: 非成员视为不存在, viewer 只能查看
+func TestCollaborationRequiresEditorRole(t *testing.T) {
+
+	f := newFixture(t)
+	created := f.create(t, "Groceries")
+
+	members := NewMemberCommandHandler(f.uow, f.log)
+
+	if _, err := members.Invite(InviteMemberCommand{TodoID: created.ID.String(), UserID: "bob", Role: todo.RoleViewer, Principal: alice}); err != nil {
+		t.Fatal(err)
+	}
+
+	collaborate := NewCollaborationCommandHandler(
+		f.repo,
+		NewAddTodoTaskCommandHandler(f.uow, f.log),
+		NewMarkAsCompletedCommandHandler(f.uow, f.log),
+		NewRemoveTodoTaskCommandHandler(f.uow, f.log),
+		f.log,
+	)
+
+	cases := []struct {
+		user string
+		err  error
+	}{
+		{"carol", todo.ErrTodoNotFound},
+		{"bob", todo.ErrTodoForbidden},
+		{"alice", nil},
+	}
+
+	for _, tt := range cases {
+
+		principal := todo.Principal{TenantID: todo.DefaultTenant, UserID: tt.user}
+
+		err := collaborate.Handle(created.ID, CollaborationOperation{Type: OperationAddTask, Title: "Milk from " + tt.user, Principal: principal})
+
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v, got %v", tt.user, tt.err, err)
+		}
+	}
+
+	saved := f.get(t, created.ID)
+
+	if len(saved.Tasks) != 1 {
+		t.Fatalf("expected only the editor's task, got %d tasks", len(saved.Tasks))
+	}
+
+	// 完成任务同样需要 editor
+	err := collaborate.Handle(created.ID, CollaborationOperation{
+		Type:      OperationCompleteTask,
+		TaskID:    saved.Tasks[0].ID.String(),
+		Principal: todo.Principal{TenantID: todo.DefaultTenant, UserID: "bob"},
+	})
+
+	if !errors.Is(err, todo.ErrTodoForbidden) {
+		t.Fatalf("expected ErrTodoForbidden, got %v", err)
+	}
+}
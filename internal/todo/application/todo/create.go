@@ -1,15 +1,20 @@
 package todo
 
 import (
+	"time"
+
 	"workit-sample/internal/todo/domain/todo"
 
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 type CreateTodoCommand struct {
-	Title       string  `json:"title" validate:"required"` // 标题
-	Description *string `json:"description"`               // 描述
+	Title       string         `json:"title" validate:"required"`                 // 标题
+	Description *string        `json:"description"`                               // 描述
+	DueAt       *time.Time     `json:"dueAt" example:"2025-01-01T18:00:00+08:00"` // 截止时间
+	TimeZone    string         `json:"timeZone" example:"Asia/Shanghai"`          // 截止时间所属的 IANA 时区,默认 UTC
+	Recurrence  string         `json:"recurrence" example:"FREQ=WEEKLY;BYDAY=MO"` // 重复规则,daily、weekly、monthly、yearly 或 RRULE,需要设置截止时间
+	Principal   todo.Principal `json:"-"`                                         // 当前用户,由接口层根据身份信息设置
 }
 
 type CreateTodoResult struct {
@@ -17,14 +22,14 @@ type CreateTodoResult struct {
 }
 
 type CreateTodoCommandHandler struct {
-	db      *gorm.DB
+	uow     todo.UnitOfWork
 	log     *zap.Logger
 	manager *todo.TodoManager
 }
 
-func NewCreateTodoCommandHandler(db *gorm.DB, log *zap.Logger, todoManager *todo.TodoManager) *CreateTodoCommandHandler {
+func NewCreateTodoCommandHandler(uow todo.UnitOfWork, log *zap.Logger, todoManager *todo.TodoManager) *CreateTodoCommandHandler {
 	return &CreateTodoCommandHandler{
-		db:      db,
+		uow:     uow,
 		log:     log,
 		manager: todoManager,
 	}
@@ -32,18 +37,36 @@ func NewCreateTodoCommandHandler(db *gorm.DB, log *zap.Logger, todoManager *todo
 
 func (h *CreateTodoCommandHandler) Handle(cmd CreateTodoCommand) (*CreateTodoResult, error) {
 
-	todo, err := h.manager.CreateTodo(cmd.Title, cmd.Description)
+	// 标题唯一性检查与写入在同一事务中完成
+	err := h.uow.Execute(func(repo todo.TodoRepository) error {
 
-	if err != nil {
-		h.log.Error("failed to create todo", zap.Error(err))
-		return nil, err
-	}
+		todo, err := h.manager.WithRepository(repo).CreateTodo(cmd.Principal, cmd.Title, cmd.Description)
+
+		if err != nil {
+			h.log.Error("failed to create todo", zap.Error(err))
+			return err
+		}
 
-	tx := h.db.Create(&todo)
+		if err := todo.SetDue(cmd.DueAt, cmd.TimeZone); err != nil {
+			h.log.Error("failed to set due date", zap.Error(err))
+			return err
+		}
 
-	if tx.Error != nil {
-		h.log.Error("failed to save todo", zap.Error(tx.Error))
-		return nil, tx.Error
+		if err := todo.SetRecurrence(cmd.Recurrence); err != nil {
+			h.log.Error("failed to set recurrence", zap.Error(err))
+			return err
+		}
+
+		if err := repo.Save(todo); err != nil {
+			h.log.Error("failed to save todo", zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
 	return &CreateTodoResult{
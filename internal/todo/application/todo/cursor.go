@@ -0,0 +1,57 @@
+package todo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor 游标格式错误
+var ErrInvalidCursor = todo.TodoError{Code: "INVALID_CURSOR", Kind: todo.KindValidation, Message: "游标格式错误"}
+
+const (
+	cursorNext = "next" // 向后翻页(更早的数据)
+	cursorPrev = "prev" // 向前翻页(更新的数据)
+)
+
+// cursor 游标分页的排序键,对外以不透明字符串传递
+type cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"i"`
+	Direction string    `json:"d"`
+}
+
+// encodeCursor 将排序键编码为不透明游标
+func encodeCursor(createdAt time.Time, id uuid.UUID, direction string) string {
+	data, _ := json.Marshal(cursor{
+		CreatedAt: createdAt,
+		ID:        id,
+		Direction: direction,
+	})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor 解析不透明游标
+func decodeCursor(s string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c cursor
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if c.Direction != cursorNext && c.Direction != cursorPrev {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,47 @@
+package todo
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+
+	createdAt := time.Date(2025, 3, 5, 9, 30, 0, 123000000, time.UTC)
+	id := uuid.New()
+
+	for _, direction := range []string{cursorNext, cursorPrev} {
+
+		c, err := decodeCursor(encodeCursor(createdAt, id, direction))
+
+		if err != nil {
+			t.Fatalf("%s: %v", direction, err)
+		}
+
+		if !c.CreatedAt.Equal(createdAt) || c.ID != id || c.Direction != direction {
+			t.Fatalf("%s: unexpected cursor %+v", direction, c)
+		}
+	}
+}
+
+func TestDecodeCursorRejectsInvalidInput(t *testing.T) {
+
+	valid := encodeCursor(time.Now(), uuid.New(), cursorNext)
+
+	cursors := map[string]string{
+		"not base64":        "not a cursor!",
+		"truncated":         valid[:len(valid)-3],
+		"not json":          base64.RawURLEncoding.EncodeToString([]byte("garbage")),
+		"unknown direction": encodeCursor(time.Now(), uuid.New(), "sideways"),
+	}
+
+	for name, cursor := range cursors {
+		if _, err := decodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: expected ErrInvalidCursor, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package todo
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type DeleteTodoCommand struct {
+	ID              string         `uri:"id" binding:"required,uuid"` // 待办事项ID
+	ExpectedVersion *int64         `json:"-"`                         // 期望的版本号,取自 If-Match 请求头
+	Principal       todo.Principal `json:"-"`                         // 当前用户,由接口层根据身份信息设置
+}
+
+type DeleteTodoCommandHandler struct {
+	uow todo.UnitOfWork
+	log *zap.Logger
+}
+
+func NewDeleteTodoCommandHandler(uow todo.UnitOfWork, log *zap.Logger) *DeleteTodoCommandHandler {
+	return &DeleteTodoCommandHandler{
+		uow: uow,
+		log: log,
+	}
+}
+
+func (h *DeleteTodoCommandHandler) Handle(cmd DeleteTodoCommand) (bool, error) {
+
+	id, err := uuid.Parse(cmd.ID)
+
+	if err != nil {
+		h.log.Error("invalid todo id", zap.Error(err))
+		return false, err
+	}
+
+	err = h.uow.Execute(func(repo todo.TodoRepository) error {
+
+		todo, err := getAuthorized(repo, id, cmd.Principal, todo.RoleOwner)
+
+		if err != nil {
+			h.log.Error("failed to query todo", zap.Error(err))
+			return err
+		}
+
+		if err := todo.CheckVersion(cmd.ExpectedVersion); err != nil {
+			h.log.Error("todo version conflict", zap.Error(err))
+			return err
+		}
+
+		todo.Delete()
+
+		if err := repo.Delete(todo); err != nil {
+			h.log.Error("failed to delete todo", zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
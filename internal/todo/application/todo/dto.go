@@ -1,20 +1,62 @@
 package todo
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+)
 
 // TodoItemDTO 是用于 Swagger 展示的简化结构
 type TodoDTO struct {
-	ID          uuid.UUID `json:"id" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
-	Title       string    `json:"title" example:"Buy milk"`
-	Description *string   `json:"description" example:"From supermarket"`
-	Completed   bool      `json:"completed" example:"false"`
-	Tasks       []TaskDTO `json:"tasks"`
+	ID               uuid.UUID  `json:"id" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
+	Title            string     `json:"title" example:"Buy milk"`
+	Description      *string    `json:"description" example:"From supermarket"`
+	Completed        bool       `json:"completed" example:"false"`
+	Version          int64      `json:"version" example:"1"`                                                       // 版本号,修改时可通过 If-Match 请求头携带
+	OwnerID          string     `json:"ownerId" example:"user-1"`                                                  // 所有者
+	Role             string     `json:"role,omitempty" example:"owner" enums:"viewer,editor,owner"`                // 当前用户的角色
+	DueAt            *time.Time `json:"dueAt" example:"2025-01-01T18:00:00+08:00"`                                 // 截止时间,按 DueTimeZone 表示
+	DueTimeZone      string     `json:"dueTimeZone,omitempty" example:"Asia/Shanghai"`                             // 截止时间所属的 IANA 时区
+	Overdue          bool       `json:"overdue" example:"false"`                                                   // 未完成且已过截止时间
+	Recurrence       string     `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`                       // 重复规则
+	SeriesID         *uuid.UUID `json:"seriesId,omitempty" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`         // 重复系列ID
+	Occurrence       int        `json:"occurrence,omitempty" example:"1"`                                          // 在重复系列中的序号
+	NextOccurrenceID *uuid.UUID `json:"nextOccurrenceId,omitempty" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"` // 已生成的下一次重复
+	Tasks            []TaskDTO  `json:"tasks"`
 }
 
 type TaskDTO struct {
-	ID          uuid.UUID `json:"id" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
-	TodoID      uuid.UUID `json:"todoId" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
-	Title       string    `json:"title" example:"Buy milk"`
-	Description *string   `json:"description" example:"From supermarket"`
-	Completed   bool      `json:"completed" example:"false"`
+	ID          uuid.UUID  `json:"id" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
+	TodoID      uuid.UUID  `json:"todoId" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
+	Title       string     `json:"title" example:"Buy milk"`
+	Description *string    `json:"description" example:"From supermarket"`
+	Completed   bool       `json:"completed" example:"false"`
+	DueAt       *time.Time `json:"dueAt" example:"2025-01-01T18:00:00+08:00"`     // 截止时间,按 DueTimeZone 表示
+	DueTimeZone string     `json:"dueTimeZone,omitempty" example:"Asia/Shanghai"` // 截止时间所属的 IANA 时区
+	Overdue     bool       `json:"overdue" example:"false"`                       // 未完成且已过截止时间
+}
+
+// MemberDTO 待办事项的成员
+type MemberDTO struct {
+	UserID    string    `json:"userId" example:"bob"`
+	Role      string    `json:"role" example:"editor" enums:"viewer,editor,owner"`
+	Creator   bool      `json:"creator" example:"false"` // 是否为创建者,创建者的角色不能修改
+	CreatedAt time.Time `json:"createdAt"`               // 加入时间
+}
+
+// dueOf 返回按所属时区表示的截止时间,以及在 now 时是否已逾期
+func dueOf(at *time.Time, timeZone string, completed bool, now time.Time) (*time.Time, bool) {
+	return todo.LocalDue(at, timeZone), !completed && at != nil && at.Before(now)
+}
+
+// PagedResult 分页查询结果
+type PagedResult[T any] struct {
+	Items      []T    `json:"items"`                                   // 当前页数据
+	Total      int64  `json:"total" example:"100"`                     // 总条数
+	Page       int    `json:"page,omitempty" example:"1"`              // 页码,仅偏移分页返回
+	Size       int    `json:"size" example:"10"`                       // 每页条数
+	NextCursor string `json:"nextCursor,omitempty" example:"eyJ0Ijoi"` // 下一页游标,仅游标分页返回
+	PrevCursor string `json:"prevCursor,omitempty" example:"eyJ0Ijoi"` // 上一页游标,仅游标分页返回
 }
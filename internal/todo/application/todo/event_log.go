@@ -0,0 +1,30 @@
+package todo
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+
+	"go.uber.org/zap"
+)
+
+// EventLogHandler 记录所有领域事件
+type EventLogHandler struct {
+	log *zap.Logger
+}
+
+func NewEventLogHandler(log *zap.Logger) *EventLogHandler {
+	return &EventLogHandler{
+		log: log,
+	}
+}
+
+func (h *EventLogHandler) Handle(event todo.Event) error {
+
+	meta := event.Metadata()
+
+	h.log.Info("domain event",
+		zap.String("event", meta.EventName),
+		zap.Stringer("event_id", meta.EventId),
+		zap.Stringer("todo_id", event.AggregateID()))
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package todo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// eventStreamBufferSize 可供断线续传的最近事件数
+	eventStreamBufferSize = 1024
+	// eventStreamSubscriberBuffer 单个订阅者未读事件上限,超过后断开该订阅者,由客户端重连续传
+	eventStreamSubscriberBuffer = 64
+)
+
+// StreamEvent 推送给订阅者的事件, ID 由启动标识与序号组成,用于断线续传
+type StreamEvent struct {
+	ID    string
+	Event todo.Event
+}
+
+// EventSubscription 事件流订阅
+type EventSubscription struct {
+	events chan StreamEvent
+	owner  todo.Principal
+	todoID uuid.UUID
+	stream *EventStream
+}
+
+// Events 返回实时事件,订阅被关闭时通道关闭
+func (s *EventSubscription) Events() <-chan StreamEvent {
+	return s.events
+}
+
+// Close 取消订阅
+func (s *EventSubscription) Close() {
+	s.stream.unsubscribe(s)
+}
+
+func (s *EventSubscription) matches(event todo.Event) bool {
+	return event.VisibleTo(s.owner) && (s.todoID == uuid.Nil || s.todoID == event.AggregateID())
+}
+
+// EventStream 进程内的待办事项事件流,保存最近的事件供断线续传,
+// 多实例部署时每个实例只能看到本实例产生的事件
+type EventStream struct {
+	epoch       string
+	buffer      []StreamEvent // 环形缓冲区,按序号取模存放
+	next        uint64        // 下一个事件的序号,从 1 开始
+	subscribers map[*EventSubscription]struct{}
+	closed      bool
+	log         *zap.Logger
+	mu          sync.Mutex
+}
+
+func NewEventStream(log *zap.Logger) *EventStream {
+	return &EventStream{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		buffer:      make([]StreamEvent, eventStreamBufferSize),
+		next:        1,
+		subscribers: make(map[*EventSubscription]struct{}),
+		log:         log,
+	}
+}
+
+// Handle 记录事件并推送给订阅者
+func (s *EventStream) Handle(event todo.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	streamEvent := StreamEvent{
+		ID:    s.id(s.next),
+		Event: event,
+	}
+
+	s.buffer[s.next%eventStreamBufferSize] = streamEvent
+	s.next++
+
+	for subscriber := range s.subscribers {
+
+		if !subscriber.matches(event) {
+			continue
+		}
+
+		select {
+		case subscriber.events <- streamEvent:
+		default:
+			s.log.Warn("event stream subscriber is too slow, disconnecting")
+			s.remove(subscriber)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe 订阅 owner 创建或被共享的待办事项的事件, todoID 为 uuid.Nil 时订阅全部待办事项。
+// lastEventID 非空时返回其后错过的事件;无法续传(事件已被覆盖或服务已重启)时
+// 返回当前最新的事件ID作为 resetID,客户端应重新加载数据并从该位置继续
+func (s *EventStream) Subscribe(owner todo.Principal, todoID uuid.UUID, lastEventID string) (subscription *EventSubscription, missed []StreamEvent, resetID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription = &EventSubscription{
+		events: make(chan StreamEvent, eventStreamSubscriberBuffer),
+		owner:  owner,
+		todoID: todoID,
+		stream: s,
+	}
+
+	if s.closed {
+		close(subscription.events)
+		return subscription, nil, ""
+	}
+
+	s.subscribers[subscription] = struct{}{}
+
+	if lastEventID == "" {
+		return subscription, nil, ""
+	}
+
+	last, ok := s.sequence(lastEventID)
+
+	// 序号之后的事件必须仍全部在缓冲区中
+	if !ok || last >= s.next || s.next-last-1 > eventStreamBufferSize {
+		return subscription, nil, s.id(s.next - 1)
+	}
+
+	for seq := last + 1; seq < s.next; seq++ {
+		if event := s.buffer[seq%eventStreamBufferSize]; subscription.matches(event.Event) {
+			missed = append(missed, event)
+		}
+	}
+
+	return subscription, missed, ""
+}
+
+// Close 关闭所有订阅并拒绝新的订阅,用于服务退出时结束长连接
+func (s *EventStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+
+	for subscriber := range s.subscribers {
+		s.remove(subscriber)
+	}
+}
+
+func (s *EventStream) unsubscribe(subscription *EventSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscribers[subscription]; ok {
+		s.remove(subscription)
+	}
+}
+
+func (s *EventStream) remove(subscription *EventSubscription) {
+	delete(s.subscribers, subscription)
+	close(subscription.events)
+}
+
+func (s *EventStream) id(seq uint64) string {
+	return fmt.Sprintf("%s-%d", s.epoch, seq)
+}
+
+// sequence 解析事件ID中的序号,启动标识不一致时返回 false
+func (s *EventStream) sequence(id string) (uint64, bool) {
+
+	epoch, seq, ok := strings.Cut(id, "-")
+
+	if !ok || epoch != s.epoch {
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(seq, 10, 64)
+
+	return n, err == nil
+}
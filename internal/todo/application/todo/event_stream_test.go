@@ -0,0 +1,170 @@
+package todo
+
+import (
+	"testing"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// created 返回 alice 新建待办事项产生的事件
+func created(t *testing.T) todo.Event {
+	t.Helper()
+
+	entity, err := todo.NewTodo(uuid.New(), alice, "Buy milk")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return entity.ClearEvents()[0]
+}
+
+// publish 推送 n 个事件并返回它们的事件ID
+func publish(t *testing.T, stream *EventStream, n int) []string {
+	t.Helper()
+
+	ids := make([]string, n)
+
+	for i := range ids {
+
+		ids[i] = stream.id(stream.next)
+
+		if err := stream.Handle(created(t)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return ids
+}
+
+func TestEventStreamResumesFromLastEventID(t *testing.T) {
+
+	stream := NewEventStream(zap.NewNop())
+	ids := publish(t, stream, 3)
+
+	subscription, missed, resetID := stream.Subscribe(alice, uuid.Nil, ids[0])
+	defer subscription.Close()
+
+	if resetID != "" || len(missed) != 2 || missed[0].ID != ids[1] || missed[1].ID != ids[2] {
+		t.Fatalf("expected events after %s, got %v with reset %q", ids[0], missed, resetID)
+	}
+
+	// 续传后继续接收实时事件
+	live := publish(t, stream, 1)
+
+	if event := <-subscription.Events(); event.ID != live[0] {
+		t.Fatalf("expected live event %s, got %s", live[0], event.ID)
+	}
+
+	// 已是最新位置时没有错过的事件
+	if _, missed, resetID := stream.Subscribe(alice, uuid.Nil, live[0]); len(missed) != 0 || resetID != "" {
+		t.Fatalf("expected nothing to resume, got %v with reset %q", missed, resetID)
+	}
+}
+
+func TestEventStreamFiltersBySubscriber(t *testing.T) {
+
+	stream := NewEventStream(zap.NewNop())
+	event := created(t)
+
+	bob, _, _ := stream.Subscribe(todo.Principal{TenantID: todo.DefaultTenant, UserID: "bob"}, uuid.Nil, "")
+	other, _, _ := stream.Subscribe(alice, uuid.New(), "")
+	mine, _, _ := stream.Subscribe(alice, event.AggregateID(), "")
+
+	if err := stream.Handle(event); err != nil {
+		t.Fatal(err)
+	}
+
+	stream.Close()
+
+	for name, subscription := range map[string]*EventSubscription{"bob": bob, "other todo": other} {
+		if _, open := <-subscription.Events(); open {
+			t.Errorf("%s: expected no event", name)
+		}
+	}
+
+	if received, open := <-mine.Events(); !open || received.Event.AggregateID() != event.AggregateID() {
+		t.Errorf("expected event of the subscribed todo, got %v", received)
+	}
+}
+
+// 缓冲区被覆盖或服务重启后无法续传,返回最新事件ID
+func TestEventStreamResetsWhenEventsAreEvicted(t *testing.T) {
+
+	stream := NewEventStream(zap.NewNop())
+	ids := publish(t, stream, eventStreamBufferSize+2)
+	latest := ids[len(ids)-1]
+
+	cases := map[string]string{
+		"evicted":      ids[0],
+		"other epoch":  "restarted-1",
+		"future":       stream.id(stream.next),
+		"not an event": "garbage",
+	}
+
+	for name, lastEventID := range cases {
+		if _, missed, resetID := stream.Subscribe(alice, uuid.Nil, lastEventID); resetID != latest || missed != nil {
+			t.Errorf("%s: expected reset to %s, got %q with %d events", name, latest, resetID, len(missed))
+		}
+	}
+
+	// 最早仍在缓冲区中的位置可以完整续传
+	if _, missed, resetID := stream.Subscribe(alice, uuid.Nil, ids[1]); resetID != "" || len(missed) != eventStreamBufferSize || missed[0].ID != ids[2] {
+		t.Errorf("expected %d buffered events, got %d with reset %q", eventStreamBufferSize, len(missed), resetID)
+	}
+}
+
+// 未及时读取的订阅者被断开,不影响其他订阅者
+func TestEventStreamDisconnectsSlowSubscriber(t *testing.T) {
+
+	stream := NewEventStream(zap.NewNop())
+
+	slow, _, _ := stream.Subscribe(alice, uuid.Nil, "")
+	fast, _, _ := stream.Subscribe(alice, uuid.Nil, "")
+	defer fast.Close()
+
+	for i := 0; i <= eventStreamSubscriberBuffer; i++ {
+
+		publish(t, stream, 1)
+
+		if _, open := <-fast.Events(); !open {
+			t.Fatal("expected fast subscriber to stay connected")
+		}
+	}
+
+	received := 0
+
+	for range slow.Events() {
+		received++
+	}
+
+	if received != eventStreamSubscriberBuffer {
+		t.Fatalf("expected %d buffered events before disconnect, got %d", eventStreamSubscriberBuffer, received)
+	}
+
+	// 断开后取消订阅不会重复关闭通道
+	slow.Close()
+}
+
+func TestEventStreamClose(t *testing.T) {
+
+	stream := NewEventStream(zap.NewNop())
+	subscription, _, _ := stream.Subscribe(alice, uuid.Nil, "")
+
+	stream.Close()
+
+	if _, open := <-subscription.Events(); open {
+		t.Fatal("expected subscription to be closed")
+	}
+
+	subscription.Close()
+
+	late, _, _ := stream.Subscribe(alice, uuid.Nil, "")
+
+	if _, open := <-late.Events(); open {
+		t.Fatal("expected subscription after close to end immediately")
+	}
+}
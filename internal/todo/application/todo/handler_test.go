@@ -0,0 +1,458 @@
+package todo
+
+import (
+	"encoding/base64"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/infrastructure/eventbus"
+	"workit-sample/internal/todo/infrastructure/outbox"
+	"workit-sample/internal/todo/infrastructure/persistence"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var alice = todo.Principal{TenantID: todo.DefaultTenant, UserID: "alice"}
+
+// fixture 基于内存仓储与内存工作单元的处理器,无需数据库
+type fixture struct {
+	repo    *persistence.MemoryTodoRepository
+	uow     *persistence.MemoryUnitOfWork
+	log     *zap.Logger
+	manager *todo.TodoManager
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	log := zap.NewNop()
+	repo := persistence.NewMemoryTodoRepository()
+	dispatcher := eventbus.NewInProcessDispatcher(eventbus.DispatcherParams{Log: log})
+	deliveries := persistence.NewMemoryDeliveryRepository()
+	subscriptions := persistence.NewMemorySubscriptionRepository(deliveries)
+
+	manager, err := todo.NewTodoManager(repo, log)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &fixture{
+		repo:    repo,
+		uow:     persistence.NewMemoryUnitOfWork(repo, outbox.NewMemoryStore(), subscriptions, deliveries, dispatcher),
+		log:     log,
+		manager: manager,
+	}
+}
+
+// create 创建待办事项并返回保存后的聚合
+func (f *fixture) create(t *testing.T, title string) *todo.Todo {
+	t.Helper()
+
+	handler := NewCreateTodoCommandHandler(f.uow, f.log, f.manager)
+
+	if _, err := handler.Handle(CreateTodoCommand{Title: title, Principal: alice}); err != nil {
+		t.Fatalf("create %q: %v", title, err)
+	}
+
+	todos, err := f.repo.List(todo.TodoSpecification{Principal: alice, Keyword: title})
+
+	if err != nil || len(todos) != 1 {
+		t.Fatalf("list %q: %v, %d todos", title, err, len(todos))
+	}
+
+	return f.get(t, todos[0].ID)
+}
+
+func (f *fixture) get(t *testing.T, id uuid.UUID) *todo.Todo {
+	t.Helper()
+
+	entity, err := f.repo.Get(id)
+
+	if err != nil {
+		t.Fatalf("get %s: %v", id, err)
+	}
+
+	return entity
+}
+
+func TestCreateTodoRejectsDuplicateTitle(t *testing.T) {
+
+	f := newFixture(t)
+	f.create(t, "Buy milk")
+
+	handler := NewCreateTodoCommandHandler(f.uow, f.log, f.manager)
+
+	_, err := handler.Handle(CreateTodoCommand{Title: "buy MILK", Principal: alice})
+
+	if !errors.Is(err, todo.ErrTodoAlreadyExists) {
+		t.Fatalf("expected ErrTodoAlreadyExists, got %v", err)
+	}
+
+	// 标题只在同一用户内唯一
+	bob := todo.Principal{TenantID: todo.DefaultTenant, UserID: "bob"}
+
+	if _, err := handler.Handle(CreateTodoCommand{Title: "Buy milk", Principal: bob}); err != nil {
+		t.Fatalf("create for another user: %v", err)
+	}
+}
+
+func TestCreateTodoRejectsEmptyTitle(t *testing.T) {
+
+	f := newFixture(t)
+
+	handler := NewCreateTodoCommandHandler(f.uow, f.log, f.manager)
+
+	if _, err := handler.Handle(CreateTodoCommand{Title: "", Principal: alice}); !errors.Is(err, todo.ErrEmptyTodoTitle) {
+		t.Fatalf("expected ErrEmptyTodoTitle, got %v", err)
+	}
+
+	if count, _ := f.repo.Count(todo.TodoSpecification{Principal: alice}); count != 0 {
+		t.Fatalf("failed command must not be committed, got %d todos", count)
+	}
+}
+
+func TestAddAndRemoveTask(t *testing.T) {
+
+	f := newFixture(t)
+	created := f.create(t, "Groceries")
+
+	add := NewAddTodoTaskCommandHandler(f.uow, f.log)
+
+	for _, title := range []string{"Milk", "Eggs"} {
+		if _, err := add.Handle(AddTodoTaskCommand{TodoID: created.ID, Title: title, Principal: alice}); err != nil {
+			t.Fatalf("add task %q: %v", title, err)
+		}
+	}
+
+	// 同一待办事项中任务标题唯一
+	if _, err := add.Handle(AddTodoTaskCommand{TodoID: created.ID, Title: "Milk", Principal: alice}); !errors.Is(err, todo.ErrTaskTitleExists) {
+		t.Fatalf("expected ErrTaskTitleExists, got %v", err)
+	}
+
+	saved := f.get(t, created.ID)
+
+	if len(saved.Tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(saved.Tasks))
+	}
+
+	if saved.Version != created.Version+2 {
+		t.Fatalf("expected version %d, got %d", created.Version+2, saved.Version)
+	}
+
+	remove := NewRemoveTodoTaskCommandHandler(f.uow, f.log)
+
+	cmd := RemoveTodoTaskCommand{TodoID: created.ID.String(), TaskID: saved.Tasks[0].ID.String(), Principal: alice}
+
+	if _, err := remove.Handle(cmd); err != nil {
+		t.Fatalf("remove task: %v", err)
+	}
+
+	if _, err := remove.Handle(cmd); !errors.Is(err, todo.ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+
+	if tasks := f.get(t, created.ID).Tasks; len(tasks) != 1 || tasks[0].ID != saved.Tasks[1].ID {
+		t.Fatalf("expected only task %s to remain, got %+v", saved.Tasks[1].ID, tasks)
+	}
+}
+
+func TestAddTaskRejectsStaleVersion(t *testing.T) {
+
+	f := newFixture(t)
+	created := f.create(t, "Groceries")
+
+	add := NewAddTodoTaskCommandHandler(f.uow, f.log)
+	stale := created.Version
+
+	if _, err := add.Handle(AddTodoTaskCommand{TodoID: created.ID, Title: "Milk", ExpectedVersion: &stale, Principal: alice}); err != nil {
+		t.Fatalf("add task: %v", err)
+	}
+
+	// 其他请求已修改,基于旧版本的写入被拒绝且不生效
+	_, err := add.Handle(AddTodoTaskCommand{TodoID: created.ID, Title: "Eggs", ExpectedVersion: &stale, Principal: alice})
+
+	if !errors.Is(err, todo.ErrTodoVersionConflict) {
+		t.Fatalf("expected ErrTodoVersionConflict, got %v", err)
+	}
+
+	if tasks := f.get(t, created.ID).Tasks; len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(tasks))
+	}
+}
+
+func TestConcurrentSaveConflicts(t *testing.T) {
+
+	f := newFixture(t)
+	created := f.create(t, "Groceries")
+
+	// 两个请求读取同一版本,后保存的请求失败
+	first, second := f.get(t, created.ID), f.get(t, created.ID)
+
+	err := f.uow.Execute(func(repo todo.TodoRepository) error {
+		return repo.Save(first)
+	})
+
+	if err != nil {
+		t.Fatalf("save first: %v", err)
+	}
+
+	err = f.uow.Execute(func(repo todo.TodoRepository) error {
+		return repo.Save(second)
+	})
+
+	if !errors.Is(err, todo.ErrTodoVersionConflict) {
+		t.Fatalf("expected ErrTodoVersionConflict, got %v", err)
+	}
+}
+
+func TestTaskRequiresEditorRole(t *testing.T) {
+
+	f := newFixture(t)
+	created := f.create(t, "Groceries")
+
+	bob := todo.Principal{TenantID: todo.DefaultTenant, UserID: "bob"}
+
+	add := NewAddTodoTaskCommandHandler(f.uow, f.log)
+
+	// 无权访问时与不存在一样
+	if _, err := add.Handle(AddTodoTaskCommand{TodoID: created.ID, Title: "Milk", Principal: bob}); !errors.Is(err, todo.ErrTodoNotFound) {
+		t.Fatalf("expected ErrTodoNotFound, got %v", err)
+	}
+}
+
+// 列表与 Get 使用相同的管理员规则,只能看到同一租户内的数据
+func TestListIncludesTenantTodosForAdmin(t *testing.T) {
+
+	f := newFixture(t)
+	f.create(t, "Groceries")
+
+	list := NewTodoListQueryHandler(f.repo, f.log)
+
+	cases := []struct {
+		name      string
+		principal todo.Principal
+		total     int64
+	}{
+		{"other user", todo.Principal{TenantID: todo.DefaultTenant, UserID: "bob"}, 0},
+		{"admin", todo.Principal{TenantID: todo.DefaultTenant, UserID: "bob", Admin: true}, 1},
+		{"admin of another tenant", todo.Principal{TenantID: "other", UserID: "bob", Admin: true}, 0},
+	}
+
+	for _, tt := range cases {
+
+		result, err := list.Handle(TodoListQuery{Principal: tt.principal})
+
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if result.Total != tt.total || int64(len(result.Items)) != tt.total {
+			t.Errorf("%s: expected %d todos, got total %d with %d items", tt.name, tt.total, result.Total, len(result.Items))
+		}
+	}
+}
+
+// seed 直接写入指定创建时间的待办事项,便于构造相同时间戳的数据
+func (f *fixture) seed(t *testing.T, title string, createdAt time.Time) uuid.UUID {
+	t.Helper()
+
+	entity, err := todo.NewTodo(uuid.New(), alice, title)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entity.CreatedAt = createdAt
+
+	if err := f.repo.Save(entity); err != nil {
+		t.Fatalf("seed %q: %v", title, err)
+	}
+
+	return entity.ID
+}
+
+// walk 从 cursor 开始沿同一方向翻页直到没有更多数据,返回每页的 ID
+func walk(t *testing.T, list *TodoListQueryHandler, cursor string, prev bool) [][]uuid.UUID {
+	t.Helper()
+
+	var pages [][]uuid.UUID
+
+	for i := 0; i < 20; i++ {
+
+		result, err := list.Handle(TodoListQuery{Principal: alice, Mode: PageModeCursor, Cursor: cursor, Size: 2})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([]uuid.UUID, len(result.Items))
+
+		for j, item := range result.Items {
+			ids[j] = item.ID
+		}
+
+		pages = append(pages, ids)
+
+		cursor = result.NextCursor
+
+		if prev {
+			cursor = result.PrevCursor
+		}
+
+		if cursor == "" {
+			return pages
+		}
+	}
+
+	t.Fatal("cursor pagination did not terminate")
+	return nil
+}
+
+func TestCursorPaginationRoundTrip(t *testing.T) {
+
+	f := newFixture(t)
+	list := NewTodoListQueryHandler(f.repo, f.log)
+
+	// 前三条创建时间相同,只能依靠 ID 区分先后
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, title := range []string{"a", "b", "c", "d", "e"} {
+		f.seed(t, title, at.Add(time.Duration(max(i-2, 0))*time.Minute))
+	}
+
+	want, err := f.repo.List(todo.TodoSpecification{Principal: alice})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forward := walk(t, list, "", false)
+
+	var seen []uuid.UUID
+
+	for _, page := range forward {
+		seen = append(seen, page...)
+	}
+
+	if len(forward) != 3 || len(seen) != len(want) {
+		t.Fatalf("expected 3 pages with %d todos, got %v", len(want), forward)
+	}
+
+	for i := range want {
+		if seen[i] != want[i].ID {
+			t.Fatalf("todo %d: expected %s, got %s", i, want[i].ID, seen[i])
+		}
+	}
+
+	// 从最后一页向前翻页得到相同的分页
+	last, err := list.Handle(TodoListQuery{Principal: alice, Mode: PageModeCursor, Size: 2, Cursor: cursorOf(t, list, 2)})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backward := walk(t, list, last.PrevCursor, true)
+
+	if len(backward) != 2 {
+		t.Fatalf("expected 2 pages before the last one, got %v", backward)
+	}
+
+	for i, page := range backward {
+		if !slices.Equal(page, forward[1-i]) {
+			t.Errorf("page %d: expected %v, got %v", 1-i, forward[1-i], page)
+		}
+	}
+}
+
+// cursorOf 返回从第一页开始连续翻 n 页后的 nextCursor
+func cursorOf(t *testing.T, list *TodoListQueryHandler, n int) string {
+	t.Helper()
+
+	cursor := ""
+
+	for i := 0; i < n; i++ {
+
+		result, err := list.Handle(TodoListQuery{Principal: alice, Mode: PageModeCursor, Cursor: cursor, Size: 2})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cursor = result.NextCursor
+	}
+
+	return cursor
+}
+
+// 翻页过程中插入新数据不会导致重复或遗漏
+func TestCursorPaginationIsStableUnderInserts(t *testing.T) {
+
+	f := newFixture(t)
+	list := NewTodoListQueryHandler(f.repo, f.log)
+
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, title := range []string{"a", "b", "c", "d"} {
+		f.seed(t, title, at.Add(time.Duration(i)*time.Minute))
+	}
+
+	first, err := list.Handle(TodoListQuery{Principal: alice, Mode: PageModeCursor, Size: 2})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.seed(t, "newest", at.Add(time.Hour))
+
+	second, err := list.Handle(TodoListQuery{Principal: alice, Mode: PageModeCursor, Size: 2, Cursor: first.NextCursor})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var titles []string
+
+	for _, item := range append(first.Items, second.Items...) {
+		titles = append(titles, item.Title)
+	}
+
+	if want := []string{"d", "c", "b", "a"}; !slices.Equal(titles, want) {
+		t.Fatalf("expected %v, got %v", want, titles)
+	}
+
+	if second.NextCursor != "" {
+		t.Errorf("expected no more pages, got cursor %q", second.NextCursor)
+	}
+}
+
+func TestInvalidCursorIsRejected(t *testing.T) {
+
+	f := newFixture(t)
+	list := NewTodoListQueryHandler(f.repo, f.log)
+
+	valid := encodeCursor(time.Now(), uuid.New(), cursorNext)
+
+	cursors := map[string]string{
+		"not base64":        "not a cursor!",
+		"truncated":         valid[:len(valid)-3],
+		"not json":          base64.RawURLEncoding.EncodeToString([]byte("garbage")),
+		"unknown direction": encodeCursor(time.Now(), uuid.New(), "sideways"),
+	}
+
+	for name, cursor := range cursors {
+
+		_, err := list.Handle(TodoListQuery{Principal: alice, Mode: PageModeCursor, Cursor: cursor})
+
+		// 接口层将校验类错误转换为 400 INVALID_CURSOR
+		var e todo.TodoError
+
+		if !errors.As(err, &e) || e.Code != ErrInvalidCursor.Code || e.Kind != todo.KindValidation {
+			t.Errorf("%s: expected %s validation error, got %v", name, ErrInvalidCursor.Code, err)
+		}
+	}
+}
@@ -1,52 +1,253 @@
 package todo
 
 import (
+	"strings"
+	"time"
+
 	"workit-sample/internal/todo/domain/todo"
 
 	"go.uber.org/zap"
-	"gorm.io/gorm"
+)
+
+const (
+	defaultPage     = 1   // 默认页码
+	defaultPageSize = 10  // 默认每页条数
+	maxPageSize     = 100 // 每页条数上限
+)
+
+const (
+	PageModeOffset = "offset" // 偏移分页
+	PageModeCursor = "cursor" // 游标分页
+)
+
+const (
+	DueOverdue = "overdue" // 已逾期
+	DueToday   = "today"   // 今天到期
+	DueWeek    = "week"    // 本周(周一至周日)到期
 )
 
 // TodoListQuery 表示查询 Todo 列表的参数
 type TodoListQuery struct {
 	// 这里可以添加其他查询参数
-	Title string `form:"title" example:"Buy milk"` // 可选标题关键词
-	Page  int    `form:"page" example:"1"`         // 页码
-	Size  int    `form:"size" example:"10"`        // 每页条数
+	Title     string         `form:"title" example:"Buy milk"`                                           // 可选标题关键词,同时匹配标题和描述
+	Page      int            `form:"page" binding:"gte=0" example:"1"`                                   // 页码,仅偏移分页使用
+	Size      int            `form:"size" binding:"gte=0" example:"10"`                                  // 每页条数
+	Mode      string         `form:"mode" binding:"omitempty,oneof=offset cursor" example:"offset"`      // 分页模式,offset(默认) 或 cursor
+	Cursor    string         `form:"cursor" example:"eyJ0Ijoi"`                                          // 游标,取自上一次响应的 nextCursor 或 prevCursor
+	Due       string         `form:"due" binding:"omitempty,oneof=overdue today week" example:"overdue"` // 截止时间筛选,只返回未完成的数据: overdue, today 或 week
+	TimeZone  string         `form:"tz" example:"Asia/Shanghai"`                                         // 计算今天与本周所用的 IANA 时区,默认 UTC
+	Principal todo.Principal `json:"-" form:"-"`                                                         // 当前用户,由接口层根据身份信息设置
 }
 
 type TodoListQueryHandler struct {
-	db  *gorm.DB
-	log *zap.Logger
+	repo todo.TodoRepository
+	log  *zap.Logger
 }
 
-func NewTodoListQueryHandler(db *gorm.DB, log *zap.Logger) *TodoListQueryHandler {
+func NewTodoListQueryHandler(repo todo.TodoRepository, log *zap.Logger) *TodoListQueryHandler {
 	return &TodoListQueryHandler{
-		db:  db,
-		log: log,
+		repo: repo,
+		log:  log,
 	}
 }
 
-func (h *TodoListQueryHandler) Handle(query TodoListQuery) ([]TodoDTO, error) {
-	var todos []todo.Todo
+func (h *TodoListQueryHandler) Handle(query TodoListQuery) (*PagedResult[TodoDTO], error) {
+
+	page, size := normalizePage(query.Page, query.Size)
+
+	// 关键词同时匹配标题和描述
+	spec := todo.TodoSpecification{
+		Principal: query.Principal,
+		Keyword:   strings.TrimSpace(query.Title),
+	}
+
+	if query.Due != "" {
+		from, to, err := dueRange(query.Due, query.TimeZone, time.Now())
+
+		if err != nil {
+			h.log.Error("invalid time zone", zap.String("tz", query.TimeZone), zap.Error(err))
+			return nil, err
+		}
+
+		spec.DueFrom, spec.DueTo = from, to
+	}
+
+	total, err := h.repo.Count(spec)
 
-	// 查询所有待办事项，按 ID 倒序排列
-	if err := h.db.Order("id DESC").Find(&todos).Error; err != nil {
+	if err != nil {
+		h.log.Error("failed to count todo list", zap.Error(err))
+		return nil, err
+	}
+
+	if query.Mode == PageModeCursor || query.Cursor != "" {
+		return h.handleCursor(spec, query.Cursor, size, total)
+	}
+
+	// 按创建时间倒序分页查询,ID 保证顺序稳定
+	spec.Offset = (page - 1) * size
+	spec.Limit = size
+
+	todos, err := h.repo.List(spec)
+
+	if err != nil {
 		h.log.Error("failed to query todo list", zap.Error(err))
 		return nil, err
 	}
 
+	return &PagedResult[TodoDTO]{
+		Items: toTodoDTOs(todos, query.Principal),
+		Total: total,
+		Page:  page,
+		Size:  size,
+	}, nil
+}
+
+// handleCursor 以 (created_at, id) 为排序键进行游标分页,并发插入时翻页结果依然稳定
+func (h *TodoListQueryHandler) handleCursor(spec todo.TodoSpecification, raw string, size int, total int64) (*PagedResult[TodoDTO], error) {
+
+	var c *cursor
+
+	if raw != "" {
+		decoded, err := decodeCursor(raw)
+		if err != nil {
+			h.log.Error("failed to decode cursor", zap.Error(err))
+			return nil, err
+		}
+		c = decoded
+	}
+
+	direction := cursorNext
+
+	if c != nil {
+		key := &todo.SortKey{CreatedAt: c.CreatedAt, ID: c.ID}
+
+		if c.Direction == cursorNext {
+			spec.After = key
+		} else {
+			direction = cursorPrev
+			spec.Before = key
+		}
+	}
+
+	// 多取一条用于判断是否还有更多数据
+	spec.Limit = size + 1
+
+	todos, err := h.repo.List(spec)
+
+	if err != nil {
+		h.log.Error("failed to query todo list", zap.Error(err))
+		return nil, err
+	}
+
+	hasMore := len(todos) > size
+
+	// 结果按倒序返回,向前翻页时多取的一条位于最前面
+	if hasMore {
+		if direction == cursorPrev {
+			todos = todos[1:]
+		} else {
+			todos = todos[:size]
+		}
+	}
+
+	result := &PagedResult[TodoDTO]{
+		Items: toTodoDTOs(todos, spec.Principal),
+		Total: total,
+		Size:  size,
+	}
+
+	if len(todos) == 0 {
+		return result, nil
+	}
+
+	first, last := todos[0], todos[len(todos)-1]
+
+	if (direction == cursorNext && hasMore) || direction == cursorPrev {
+		result.NextCursor = encodeCursor(last.CreatedAt, last.ID, cursorNext)
+	}
+
+	if (direction == cursorPrev && hasMore) || (direction == cursorNext && c != nil) {
+		result.PrevCursor = encodeCursor(first.CreatedAt, first.ID, cursorPrev)
+	}
+
+	return result, nil
+}
+
+func toTodoDTOs(todos []todo.Todo, principal todo.Principal) []TodoDTO {
+
 	todoDTOs := make([]TodoDTO, len(todos))
+	now := time.Now()
 
 	for i, t := range todos {
+		dueAt, overdue := dueOf(t.DueAt, t.DueTimeZone, t.Completed, now)
+
 		todoDTOs[i] = TodoDTO{
-			ID:          t.ID,
-			Title:       t.Title,
-			Description: t.Description,
-			Completed:   t.Completed,
-			Tasks:       []TaskDTO{}, // 为空但保持字段一致性
+			ID:               t.ID,
+			Title:            t.Title,
+			Description:      t.Description,
+			Completed:        t.Completed,
+			Version:          t.Version,
+			OwnerID:          t.OwnerID,
+			Role:             t.RoleOf(principal),
+			DueAt:            dueAt,
+			DueTimeZone:      t.DueTimeZone,
+			Overdue:          overdue,
+			Recurrence:       t.Recurrence,
+			SeriesID:         t.SeriesID,
+			Occurrence:       t.Occurrence,
+			NextOccurrenceID: t.NextOccurrenceID,
+			Tasks:            []TaskDTO{}, // 为空但保持字段一致性
 		}
 	}
 
-	return todoDTOs, nil
+	return todoDTOs
+}
+
+// dueRange 按时区计算截止时间筛选的范围 [from, to),按日历日计算以正确处理夏令时
+func dueRange(due, timeZone string, now time.Time) (from, to *time.Time, err error) {
+
+	if timeZone == "" {
+		timeZone = todo.DefaultTimeZone
+	}
+
+	location, err := time.LoadLocation(timeZone)
+
+	if err != nil {
+		return nil, nil, todo.ErrInvalidTimeZone
+	}
+
+	now = now.In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+
+	switch due {
+	case DueOverdue:
+		return nil, &now, nil
+	case DueToday:
+		end := today.AddDate(0, 0, 1)
+		return &today, &end, nil
+	case DueWeek:
+		// time.Sunday 为 0,换算为距本周一的天数
+		monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		end := monday.AddDate(0, 0, 7)
+		return &monday, &end, nil
+	}
+
+	return nil, nil, nil
+}
+
+// normalizePage 补全默认页码和每页条数,并限制每页条数上限
+func normalizePage(page, size int) (int, int) {
+	if page <= 0 {
+		page = defaultPage
+	}
+
+	if size <= 0 {
+		size = defaultPageSize
+	}
+
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+
+	return page, size
 }
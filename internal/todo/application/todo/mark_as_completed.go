@@ -5,50 +5,61 @@ import (
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 type MarkAsCompletedCommand struct {
-	TodoID uuid.UUID `json:"todoId" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
-	TaskID uuid.UUID `json:"taskId" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
+	TodoID          uuid.UUID      `json:"todoId" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
+	TaskID          uuid.UUID      `json:"taskId" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
+	ExpectedVersion *int64         `json:"-"` // 期望的版本号,取自 If-Match 请求头
+	Principal       todo.Principal `json:"-"` // 当前用户,由接口层根据身份信息设置
 }
 
 type MarkAsCompletedCommandHandler struct {
-	db  *gorm.DB
+	uow todo.UnitOfWork
 	log *zap.Logger
 }
 
-func NewMarkAsCompletedCommandHandler(db *gorm.DB, log *zap.Logger) *MarkAsCompletedCommandHandler {
+func NewMarkAsCompletedCommandHandler(uow todo.UnitOfWork, log *zap.Logger) *MarkAsCompletedCommandHandler {
 	return &MarkAsCompletedCommandHandler{
-		db:  db,
+		uow: uow,
 		log: log,
 	}
 }
 
 func (h *MarkAsCompletedCommandHandler) Handle(cmd MarkAsCompletedCommand) (bool, error) {
 
-	todo := todo.Todo{}
+	err := h.uow.Execute(func(repo todo.TodoRepository) error {
 
-	// 使用 Preload 加载关联的 Tasks
-	result := h.db.Preload("Tasks").First(&todo, "id = ?", cmd.TodoID)
+		todo, err := getAuthorized(repo, cmd.TodoID, cmd.Principal, todo.RoleEditor)
 
-	if result.Error != nil {
-		h.log.Error("failed to query todoList", zap.Error(result.Error))
-		return false, result.Error
-	}
+		if err != nil {
+			h.log.Error("failed to query todoList", zap.Error(err))
+			return err
+		}
 
-	err := todo.MarkAsCompleted(cmd.TaskID)
+		if err := todo.CheckVersion(cmd.ExpectedVersion); err != nil {
+			h.log.Error("todo version conflict", zap.Error(err))
+			return err
+		}
 
-	if err != nil {
-		h.log.Error("failed to add task", zap.Error(err))
-		return false, err
-	}
+		err = todo.MarkAsCompleted(cmd.TaskID)
 
-	tx := h.db.Session(&gorm.Session{FullSaveAssociations: true}).Save(&todo)
+		if err != nil {
+			h.log.Error("failed to mark task as completed", zap.Error(err))
+			return err
+		}
 
-	if tx.Error != nil {
-		h.log.Error("failed to save task", zap.Error(tx.Error))
-		return false, tx.Error
+		// 所有任务完成后待办事项自动完成,重复的待办事项随之生成下一次重复
+		if err := saveWithNextOccurrence(repo, todo); err != nil {
+			h.log.Error("failed to save todo", zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return false, err
 	}
 
 	return true, nil
@@ -0,0 +1,153 @@
+package todo
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// InviteMemberCommand 邀请同一租户内的用户共享待办事项
+type InviteMemberCommand struct {
+	TodoID          string         `json:"-" uri:"id" binding:"required,uuid"`                                 // 待办事项ID
+	UserID          string         `json:"userId" binding:"required" example:"bob"`                            // 被邀请的用户ID
+	Role            string         `json:"role" binding:"required,oneof=viewer editor owner" example:"editor"` // 角色
+	ExpectedVersion *int64         `json:"-"`                                                                  // 期望的版本号,取自 If-Match 请求头
+	Principal       todo.Principal `json:"-"`                                                                  // 当前用户,由接口层根据身份信息设置
+}
+
+// ChangeMemberRoleCommand 修改成员的角色
+type ChangeMemberRoleCommand struct {
+	TodoID          string         `json:"-" uri:"id" binding:"required,uuid"`                                 // 待办事项ID
+	UserID          string         `json:"-" uri:"userId" binding:"required"`                                  // 成员的用户ID
+	Role            string         `json:"role" binding:"required,oneof=viewer editor owner" example:"viewer"` // 角色
+	ExpectedVersion *int64         `json:"-"`                                                                  // 期望的版本号,取自 If-Match 请求头
+	Principal       todo.Principal `json:"-"`                                                                  // 当前用户,由接口层根据身份信息设置
+}
+
+// RevokeMemberCommand 移除成员
+type RevokeMemberCommand struct {
+	TodoID          string         `uri:"id" binding:"required,uuid"` // 待办事项ID
+	UserID          string         `uri:"userId" binding:"required"`  // 成员的用户ID
+	ExpectedVersion *int64         `json:"-"`                         // 期望的版本号,取自 If-Match 请求头
+	Principal       todo.Principal `json:"-"`                         // 当前用户,由接口层根据身份信息设置
+}
+
+// MemberCommandHandler 管理成员,只有 owner 可以执行
+type MemberCommandHandler struct {
+	uow todo.UnitOfWork
+	log *zap.Logger
+}
+
+func NewMemberCommandHandler(uow todo.UnitOfWork, log *zap.Logger) *MemberCommandHandler {
+	return &MemberCommandHandler{
+		uow: uow,
+		log: log,
+	}
+}
+
+func (h *MemberCommandHandler) Invite(cmd InviteMemberCommand) (bool, error) {
+	return h.execute(cmd.TodoID, cmd.ExpectedVersion, cmd.Principal, func(t *todo.Todo) error {
+		return t.Invite(cmd.UserID, cmd.Role)
+	})
+}
+
+func (h *MemberCommandHandler) ChangeRole(cmd ChangeMemberRoleCommand) (bool, error) {
+	return h.execute(cmd.TodoID, cmd.ExpectedVersion, cmd.Principal, func(t *todo.Todo) error {
+		return t.ChangeRole(cmd.UserID, cmd.Role)
+	})
+}
+
+func (h *MemberCommandHandler) Revoke(cmd RevokeMemberCommand) (bool, error) {
+	return h.execute(cmd.TodoID, cmd.ExpectedVersion, cmd.Principal, func(t *todo.Todo) error {
+		return t.Revoke(cmd.UserID)
+	})
+}
+
+func (h *MemberCommandHandler) execute(todoID string, expectedVersion *int64, principal todo.Principal, change func(*todo.Todo) error) (bool, error) {
+
+	id, err := uuid.Parse(todoID)
+
+	if err != nil {
+		h.log.Error("invalid todo id", zap.Error(err))
+		return false, err
+	}
+
+	err = h.uow.Execute(func(repo todo.TodoRepository) error {
+
+		todo, err := getAuthorized(repo, id, principal, todo.RoleOwner)
+
+		if err != nil {
+			h.log.Error("failed to query todo", zap.Error(err))
+			return err
+		}
+
+		if err := todo.CheckVersion(expectedVersion); err != nil {
+			h.log.Error("todo version conflict", zap.Error(err))
+			return err
+		}
+
+		if err := change(todo); err != nil {
+			h.log.Error("failed to change members", zap.Error(err))
+			return err
+		}
+
+		if err := repo.Save(todo); err != nil {
+			h.log.Error("failed to save todo", zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// MemberListQuery 查询待办事项的成员
+type MemberListQuery struct {
+	TodoID    string         `uri:"id" binding:"required,uuid"` // 待办事项ID
+	Principal todo.Principal `json:"-"`                         // 当前用户,由接口层根据身份信息设置
+}
+
+type MemberListQueryHandler struct {
+	repo todo.TodoRepository
+	log  *zap.Logger
+}
+
+func NewMemberListQueryHandler(repo todo.TodoRepository, log *zap.Logger) *MemberListQueryHandler {
+	return &MemberListQueryHandler{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// Handle 返回创建者与共享成员,创建者排在最前
+func (h *MemberListQueryHandler) Handle(query MemberListQuery) ([]MemberDTO, error) {
+
+	id, err := uuid.Parse(query.TodoID)
+
+	if err != nil {
+		h.log.Error("invalid todo id", zap.Error(err))
+		return nil, err
+	}
+
+	entity, err := getAuthorized(h.repo, id, query.Principal, todo.RoleViewer)
+
+	if err != nil {
+		h.log.Error("failed to query todo", zap.Error(err))
+		return nil, err
+	}
+
+	members := make([]MemberDTO, 0, len(entity.Members)+1)
+	members = append(members, MemberDTO{UserID: entity.OwnerID, Role: todo.RoleOwner, Creator: true, CreatedAt: entity.CreatedAt})
+
+	for _, member := range entity.Members {
+		members = append(members, MemberDTO{UserID: member.UserID, Role: member.Role, CreatedAt: member.CreatedAt})
+	}
+
+	return members, nil
+}
@@ -1,58 +1,90 @@
 package todo
 
 import (
+	"slices"
+	"strings"
+	"time"
+
 	"workit-sample/internal/todo/domain/todo"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 // TodoListQuery 表示查询 Todo 列表的参数
 type TodoQuery struct {
 	// 这里可以添加其他查询参数
-	ID string `uri:"id" binding:"required,uuid"`
+	ID        string         `uri:"id" binding:"required,uuid"`
+	Principal todo.Principal `json:"-"` // 当前用户,由接口层根据身份信息设置
 }
 
 type TodoQueryHandler struct {
-	db  *gorm.DB
-	log *zap.Logger
+	repo todo.TodoRepository
+	log  *zap.Logger
 }
 
-func NewTodoQueryHandler(db *gorm.DB, log *zap.Logger) *TodoQueryHandler {
+func NewTodoQueryHandler(repo todo.TodoRepository, log *zap.Logger) *TodoQueryHandler {
 	return &TodoQueryHandler{
-		db:  db,
-		log: log,
+		repo: repo,
+		log:  log,
 	}
 }
 
 func (h *TodoQueryHandler) Handle(query TodoQuery) (*TodoDTO, error) {
-	var todoEntity todo.Todo
-
-	// 预加载 Tasks，并按 ID 倒序排列
-	if err := h.db.
-		Preload("Tasks", func(db *gorm.DB) *gorm.DB {
-			return db.Order("id DESC")
-		}).
-		First(&todoEntity, "id = ?", query.ID).
-		Error; err != nil {
+
+	id, err := uuid.Parse(query.ID)
+
+	if err != nil {
+		h.log.Error("invalid todo id", zap.Error(err))
+		return nil, err
+	}
+
+	todoEntity, err := getAuthorized(h.repo, id, query.Principal, todo.RoleViewer)
+
+	if err != nil {
 		h.log.Error("failed to query todo", zap.Error(err))
 		return nil, err
 	}
 
+	// Tasks 按 ID 倒序排列
+	slices.SortFunc(todoEntity.Tasks, func(a, b todo.Task) int {
+		return strings.Compare(b.ID.String(), a.ID.String())
+	})
+
+	now := time.Now()
+	dueAt, overdue := dueOf(todoEntity.DueAt, todoEntity.DueTimeZone, todoEntity.Completed, now)
+
 	// 转换为 DTO
 	todoDTO := &TodoDTO{
-		ID:    todoEntity.ID,
-		Title: todoEntity.Title,
-		Tasks: make([]TaskDTO, len(todoEntity.Tasks)),
+		ID:               todoEntity.ID,
+		Title:            todoEntity.Title,
+		Description:      todoEntity.Description,
+		Completed:        todoEntity.Completed,
+		Version:          todoEntity.Version,
+		OwnerID:          todoEntity.OwnerID,
+		Role:             todoEntity.RoleOf(query.Principal),
+		DueAt:            dueAt,
+		DueTimeZone:      todoEntity.DueTimeZone,
+		Overdue:          overdue,
+		Recurrence:       todoEntity.Recurrence,
+		SeriesID:         todoEntity.SeriesID,
+		Occurrence:       todoEntity.Occurrence,
+		NextOccurrenceID: todoEntity.NextOccurrenceID,
+		Tasks:            make([]TaskDTO, len(todoEntity.Tasks)),
 	}
 
 	for i, task := range todoEntity.Tasks {
+		dueAt, overdue := dueOf(task.DueAt, task.DueTimeZone, task.Completed, now)
+
 		todoDTO.Tasks[i] = TaskDTO{
 			ID:          task.ID,
 			Title:       task.Title,
 			Description: task.Description,
 			Completed:   task.Completed,
 			TodoID:      task.TodoID,
+			DueAt:       dueAt,
+			DueTimeZone: task.DueTimeZone,
+			Overdue:     overdue,
 		}
 	}
 
@@ -0,0 +1,27 @@
+package todo
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+)
+
+// saveWithNextOccurrence 保存待办事项,重复的待办事项完成时在同一工作单元内生成并保存下一次重复
+func saveWithNextOccurrence(repo todo.TodoRepository, t *todo.Todo) error {
+
+	next, err := t.NextOccurrence(uuid.New())
+
+	if err != nil {
+		return err
+	}
+
+	if err := repo.Save(t); err != nil {
+		return err
+	}
+
+	if next == nil {
+		return nil
+	}
+
+	return repo.Save(next)
+}
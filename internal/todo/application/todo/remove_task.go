@@ -0,0 +1,78 @@
+package todo
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type RemoveTodoTaskCommand struct {
+	TodoID          string         `uri:"id" binding:"required,uuid"`     // 待办事项ID
+	TaskID          string         `uri:"taskId" binding:"required,uuid"` // 任务ID
+	ExpectedVersion *int64         `json:"-"`                             // 期望的版本号,取自 If-Match 请求头
+	Principal       todo.Principal `json:"-"`                             // 当前用户,由接口层根据身份信息设置
+}
+
+type RemoveTodoTaskCommandHandler struct {
+	uow todo.UnitOfWork
+	log *zap.Logger
+}
+
+func NewRemoveTodoTaskCommandHandler(uow todo.UnitOfWork, log *zap.Logger) *RemoveTodoTaskCommandHandler {
+	return &RemoveTodoTaskCommandHandler{
+		uow: uow,
+		log: log,
+	}
+}
+
+func (h *RemoveTodoTaskCommandHandler) Handle(cmd RemoveTodoTaskCommand) (bool, error) {
+
+	todoID, err := uuid.Parse(cmd.TodoID)
+
+	if err != nil {
+		h.log.Error("invalid todo id", zap.Error(err))
+		return false, err
+	}
+
+	taskID, err := uuid.Parse(cmd.TaskID)
+
+	if err != nil {
+		h.log.Error("invalid task id", zap.Error(err))
+		return false, err
+	}
+
+	err = h.uow.Execute(func(repo todo.TodoRepository) error {
+
+		todo, err := getAuthorized(repo, todoID, cmd.Principal, todo.RoleEditor)
+
+		if err != nil {
+			h.log.Error("failed to query todo", zap.Error(err))
+			return err
+		}
+
+		if err := todo.CheckVersion(cmd.ExpectedVersion); err != nil {
+			h.log.Error("todo version conflict", zap.Error(err))
+			return err
+		}
+
+		if err := todo.RemoveTask(taskID); err != nil {
+			h.log.Error("failed to remove task", zap.Error(err))
+			return err
+		}
+
+		// 仓储保存时会删除已从聚合中移除的任务
+		if err := repo.Save(todo); err != nil {
+			h.log.Error("failed to save todo", zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
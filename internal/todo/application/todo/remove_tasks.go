@@ -0,0 +1,71 @@
+package todo
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type RemoveTodoTasksCommand struct {
+	TodoID          string         `json:"-" uri:"id" binding:"required,uuid"`                                              // 待办事项ID
+	TaskIDs         []uuid.UUID    `json:"taskIds" binding:"required,min=1" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"` // 任务ID列表
+	ExpectedVersion *int64         `json:"-"`                                                                               // 期望的版本号,取自 If-Match 请求头
+	Principal       todo.Principal `json:"-"`                                                                               // 当前用户,由接口层根据身份信息设置
+}
+
+type RemoveTodoTasksCommandHandler struct {
+	uow todo.UnitOfWork
+	log *zap.Logger
+}
+
+func NewRemoveTodoTasksCommandHandler(uow todo.UnitOfWork, log *zap.Logger) *RemoveTodoTasksCommandHandler {
+	return &RemoveTodoTasksCommandHandler{
+		uow: uow,
+		log: log,
+	}
+}
+
+func (h *RemoveTodoTasksCommandHandler) Handle(cmd RemoveTodoTasksCommand) (bool, error) {
+
+	todoID, err := uuid.Parse(cmd.TodoID)
+
+	if err != nil {
+		h.log.Error("invalid todo id", zap.Error(err))
+		return false, err
+	}
+
+	err = h.uow.Execute(func(repo todo.TodoRepository) error {
+
+		todo, err := getAuthorized(repo, todoID, cmd.Principal, todo.RoleEditor)
+
+		if err != nil {
+			h.log.Error("failed to query todo", zap.Error(err))
+			return err
+		}
+
+		if err := todo.CheckVersion(cmd.ExpectedVersion); err != nil {
+			h.log.Error("todo version conflict", zap.Error(err))
+			return err
+		}
+
+		if err := todo.RemoveTasks(cmd.TaskIDs); err != nil {
+			h.log.Error("failed to remove tasks", zap.Error(err))
+			return err
+		}
+
+		// 仓储保存时会删除已从聚合中移除的任务
+		if err := repo.Save(todo); err != nil {
+			h.log.Error("failed to save todo", zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
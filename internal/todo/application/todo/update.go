@@ -0,0 +1,125 @@
+package todo
+
+import (
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type UpdateTodoCommand struct {
+	ID              string         `json:"-" uri:"id" binding:"required,uuid"`        // 待办事项ID
+	Title           *string        `json:"title" example:"Buy milk"`                  // 标题
+	Description     *string        `json:"description" example:"From supermarket"`    // 描述
+	Completed       *bool          `json:"completed" example:"false"`                 // 是否完成
+	DueAt           *time.Time     `json:"dueAt" example:"2025-01-01T18:00:00+08:00"` // 截止时间
+	TimeZone        *string        `json:"timeZone" example:"Asia/Shanghai"`          // 截止时间所属的 IANA 时区,未指定时保持不变
+	ClearDue        bool           `json:"clearDue" example:"false"`                  // 清除截止时间
+	Recurrence      *string        `json:"recurrence" example:"FREQ=WEEKLY;BYDAY=MO"` // 重复规则,daily、weekly、monthly、yearly 或 RRULE,空字符串表示取消重复
+	ExpectedVersion *int64         `json:"-"`                                         // 期望的版本号,取自 If-Match 请求头
+	Principal       todo.Principal `json:"-"`                                         // 当前用户,由接口层根据身份信息设置
+}
+
+type UpdateTodoCommandHandler struct {
+	uow     todo.UnitOfWork
+	log     *zap.Logger
+	manager *todo.TodoManager
+}
+
+func NewUpdateTodoCommandHandler(uow todo.UnitOfWork, log *zap.Logger, todoManager *todo.TodoManager) *UpdateTodoCommandHandler {
+	return &UpdateTodoCommandHandler{
+		uow:     uow,
+		log:     log,
+		manager: todoManager,
+	}
+}
+
+func (h *UpdateTodoCommandHandler) Handle(cmd UpdateTodoCommand) (bool, error) {
+
+	id, err := uuid.Parse(cmd.ID)
+
+	if err != nil {
+		h.log.Error("invalid todo id", zap.Error(err))
+		return false, err
+	}
+
+	err = h.uow.Execute(func(repo todo.TodoRepository) error {
+
+		todo, err := getAuthorized(repo, id, cmd.Principal, todo.RoleEditor)
+
+		if err != nil {
+			h.log.Error("failed to query todo", zap.Error(err))
+			return err
+		}
+
+		if err := todo.CheckVersion(cmd.ExpectedVersion); err != nil {
+			h.log.Error("todo version conflict", zap.Error(err))
+			return err
+		}
+
+		if cmd.Title != nil {
+			if err := h.manager.WithRepository(repo).ChangeTitle(todo, *cmd.Title); err != nil {
+				h.log.Error("failed to update title", zap.Error(err))
+				return err
+			}
+		}
+
+		if cmd.Description != nil {
+			todo.UpdateDescription(cmd.Description)
+		}
+
+		if cmd.Completed != nil {
+			todo.UpdateCompleted(*cmd.Completed)
+		}
+
+		// 先取消重复再修改截止时间,才能同时清除两者
+		if cmd.Recurrence != nil && *cmd.Recurrence == "" {
+			todo.SetRecurrence("")
+		}
+
+		if err := todo.SetDue(cmd.due(todo.DueAt, todo.DueTimeZone)); err != nil {
+			h.log.Error("failed to update due date", zap.Error(err))
+			return err
+		}
+
+		if cmd.Recurrence != nil {
+			if err := todo.SetRecurrence(*cmd.Recurrence); err != nil {
+				h.log.Error("failed to update recurrence", zap.Error(err))
+				return err
+			}
+		}
+
+		if err := saveWithNextOccurrence(repo, todo); err != nil {
+			h.log.Error("failed to save todo", zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// due 将修改命令与当前截止时间合并,只修改时区时截止时刻不变
+func (cmd UpdateTodoCommand) due(dueAt *time.Time, timeZone string) (*time.Time, string) {
+
+	if cmd.ClearDue {
+		return nil, ""
+	}
+
+	if cmd.DueAt != nil {
+		dueAt = cmd.DueAt
+	}
+
+	if cmd.TimeZone != nil {
+		timeZone = *cmd.TimeZone
+	}
+
+	return dueAt, timeZone
+}
@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+
+	"github.com/google/uuid"
+)
+
+// getOwned 加载当前用户的订阅,不属于当前用户时返回 ErrWebhookNotFound
+func getOwned(repo webhook.SubscriptionRepository, id uuid.UUID, principal todo.Principal) (*webhook.Subscription, error) {
+
+	subscription, err := repo.Get(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !subscription.Owner().Is(principal) {
+		return nil, webhook.ErrWebhookNotFound
+	}
+
+	return subscription, nil
+}
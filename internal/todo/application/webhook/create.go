@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type CreateWebhookCommand struct {
+	URL       string         `json:"url" binding:"required"` // 目标地址
+	Events    []string       `json:"events"`                 // 订阅的事件,为空表示全部事件
+	Secret    string         `json:"secret"`                 // 签名密钥,为空时自动生成
+	Principal todo.Principal `json:"-"`                      // 当前用户,由接口层根据身份信息设置
+}
+
+type CreateWebhookCommandHandler struct {
+	repo webhook.SubscriptionRepository
+	log  *zap.Logger
+}
+
+func NewCreateWebhookCommandHandler(repo webhook.SubscriptionRepository, log *zap.Logger) *CreateWebhookCommandHandler {
+	return &CreateWebhookCommandHandler{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (h *CreateWebhookCommandHandler) Handle(cmd CreateWebhookCommand) (*WebhookDTO, error) {
+
+	subscription, err := webhook.NewSubscription(uuid.New(), cmd.Principal, cmd.URL, cmd.Events, cmd.Secret)
+
+	if err != nil {
+		h.log.Error("failed to create webhook", zap.Error(err))
+		return nil, err
+	}
+
+	if err := h.repo.Save(subscription); err != nil {
+		h.log.Error("failed to save webhook", zap.Error(err))
+		return nil, err
+	}
+
+	// 密钥只在创建时返回一次
+	dto := toWebhookDTO(subscription)
+	dto.Secret = subscription.Secret
+
+	return &dto, nil
+}
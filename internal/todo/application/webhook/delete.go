@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type DeleteWebhookCommand struct {
+	ID        string         `uri:"id" binding:"required,uuid"` // Webhook ID
+	Principal todo.Principal `json:"-"`                         // 当前用户,由接口层根据身份信息设置
+}
+
+type DeleteWebhookCommandHandler struct {
+	repo webhook.SubscriptionRepository
+	log  *zap.Logger
+}
+
+func NewDeleteWebhookCommandHandler(repo webhook.SubscriptionRepository, log *zap.Logger) *DeleteWebhookCommandHandler {
+	return &DeleteWebhookCommandHandler{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (h *DeleteWebhookCommandHandler) Handle(cmd DeleteWebhookCommand) (bool, error) {
+
+	id, err := uuid.Parse(cmd.ID)
+
+	if err != nil {
+		h.log.Error("invalid webhook id", zap.Error(err))
+		return false, err
+	}
+
+	subscription, err := getOwned(h.repo, id, cmd.Principal)
+
+	if err != nil {
+		h.log.Error("failed to query webhook", zap.Error(err))
+		return false, err
+	}
+
+	if err := h.repo.Delete(subscription); err != nil {
+		h.log.Error("failed to delete webhook", zap.Error(err))
+		return false, err
+	}
+
+	return true, nil
+}
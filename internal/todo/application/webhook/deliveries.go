@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DeliveryListQuery 查询订阅的投递记录
+type DeliveryListQuery struct {
+	ID        string         `uri:"id" binding:"required,uuid"`               // Webhook ID
+	Limit     int            `form:"limit" binding:"omitempty,min=1,max=100"` // 返回条数,默认20
+	Principal todo.Principal `json:"-" form:"-"`                              // 当前用户,由接口层根据身份信息设置
+}
+
+type DeliveryListQueryHandler struct {
+	subscriptions webhook.SubscriptionRepository
+	deliveries    webhook.DeliveryRepository
+	log           *zap.Logger
+}
+
+func NewDeliveryListQueryHandler(subscriptions webhook.SubscriptionRepository, deliveries webhook.DeliveryRepository, log *zap.Logger) *DeliveryListQueryHandler {
+	return &DeliveryListQueryHandler{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		log:           log,
+	}
+}
+
+func (h *DeliveryListQueryHandler) Handle(query DeliveryListQuery) ([]DeliveryDTO, error) {
+
+	id, err := uuid.Parse(query.ID)
+
+	if err != nil {
+		h.log.Error("invalid webhook id", zap.Error(err))
+		return nil, err
+	}
+
+	if _, err := getOwned(h.subscriptions, id, query.Principal); err != nil {
+		h.log.Error("failed to query webhook", zap.Error(err))
+		return nil, err
+	}
+
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	deliveries, err := h.deliveries.ListBySubscription(id, query.Limit)
+
+	if err != nil {
+		h.log.Error("failed to list deliveries", zap.Error(err))
+		return nil, err
+	}
+
+	items := make([]DeliveryDTO, len(deliveries))
+
+	for i := range deliveries {
+		items[i] = toDeliveryDTO(&deliveries[i])
+	}
+
+	return items, nil
+}
+
+// RedeliverCommand 以原请求体重新投递
+type RedeliverCommand struct {
+	ID         string         `uri:"id" binding:"required,uuid"`         // Webhook ID
+	DeliveryID string         `uri:"deliveryId" binding:"required,uuid"` // 投递记录ID
+	Principal  todo.Principal `json:"-"`                                 // 当前用户,由接口层根据身份信息设置
+}
+
+type RedeliverCommandHandler struct {
+	subscriptions webhook.SubscriptionRepository
+	deliveries    webhook.DeliveryRepository
+	log           *zap.Logger
+}
+
+func NewRedeliverCommandHandler(subscriptions webhook.SubscriptionRepository, deliveries webhook.DeliveryRepository, log *zap.Logger) *RedeliverCommandHandler {
+	return &RedeliverCommandHandler{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		log:           log,
+	}
+}
+
+func (h *RedeliverCommandHandler) Handle(cmd RedeliverCommand) (*DeliveryDTO, error) {
+
+	subscriptionID, err := uuid.Parse(cmd.ID)
+
+	if err != nil {
+		h.log.Error("invalid webhook id", zap.Error(err))
+		return nil, err
+	}
+
+	if _, err := getOwned(h.subscriptions, subscriptionID, cmd.Principal); err != nil {
+		h.log.Error("failed to query webhook", zap.Error(err))
+		return nil, err
+	}
+
+	id, err := uuid.Parse(cmd.DeliveryID)
+
+	if err != nil {
+		h.log.Error("invalid delivery id", zap.Error(err))
+		return nil, err
+	}
+
+	delivery, err := h.deliveries.Get(id)
+
+	if err != nil {
+		h.log.Error("failed to query delivery", zap.Error(err))
+		return nil, err
+	}
+
+	if delivery.SubscriptionID != subscriptionID {
+		return nil, webhook.ErrDeliveryNotFound
+	}
+
+	redelivery := delivery.Redeliver()
+
+	if err := h.deliveries.Save(redelivery); err != nil {
+		h.log.Error("failed to save delivery", zap.Error(err))
+		return nil, err
+	}
+
+	dto := toDeliveryDTO(redelivery)
+
+	return &dto, nil
+}
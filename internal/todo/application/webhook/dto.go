@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"time"
+
+	"workit-sample/internal/todo/domain/webhook"
+
+	"github.com/google/uuid"
+)
+
+// WebhookDTO Webhook 订阅
+type WebhookDTO struct {
+	ID        uuid.UUID `json:"id" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
+	URL       string    `json:"url" example:"https://example.com/hooks/todo"`
+	Events    []string  `json:"events" example:"todo.created,todo.completed"` // 订阅的事件,为空表示全部事件
+	Active    bool      `json:"active" example:"true"`
+	Secret    string    `json:"secret,omitempty" example:"3f1c..."` // 签名密钥,仅创建时返回
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// DeliveryDTO 投递记录
+type DeliveryDTO struct {
+	ID             uuid.UUID  `json:"id" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
+	SubscriptionID uuid.UUID  `json:"subscriptionId" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
+	EventID        uuid.UUID  `json:"eventId" example:"b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"`
+	Event          string     `json:"event" example:"todo.created"`
+	Payload        string     `json:"payload"` // 请求体
+	Status         string     `json:"status" example:"succeeded" enums:"pending,succeeded,failed"`
+	Attempts       int        `json:"attempts" example:"1"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt"`
+	LastStatusCode *int       `json:"lastStatusCode" example:"200"`
+	LastError      *string    `json:"lastError"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	DeliveredAt    *time.Time `json:"deliveredAt"`
+}
+
+func toWebhookDTO(s *webhook.Subscription) WebhookDTO {
+
+	events := s.Events
+
+	if events == nil {
+		events = []string{}
+	}
+
+	return WebhookDTO{
+		ID:        s.ID,
+		URL:       s.URL,
+		Events:    events,
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func toDeliveryDTO(d *webhook.Delivery) DeliveryDTO {
+	return DeliveryDTO{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		Event:          d.EventName,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type WebhookQuery struct {
+	ID        string         `uri:"id" binding:"required,uuid"` // Webhook ID
+	Principal todo.Principal `json:"-"`                         // 当前用户,由接口层根据身份信息设置
+}
+
+type WebhookQueryHandler struct {
+	repo webhook.SubscriptionRepository
+	log  *zap.Logger
+}
+
+func NewWebhookQueryHandler(repo webhook.SubscriptionRepository, log *zap.Logger) *WebhookQueryHandler {
+	return &WebhookQueryHandler{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (h *WebhookQueryHandler) Handle(query WebhookQuery) (*WebhookDTO, error) {
+
+	id, err := uuid.Parse(query.ID)
+
+	if err != nil {
+		h.log.Error("invalid webhook id", zap.Error(err))
+		return nil, err
+	}
+
+	subscription, err := getOwned(h.repo, id, query.Principal)
+
+	if err != nil {
+		h.log.Error("failed to query webhook", zap.Error(err))
+		return nil, err
+	}
+
+	dto := toWebhookDTO(subscription)
+
+	return &dto, nil
+}
+
+type WebhookListQueryHandler struct {
+	repo webhook.SubscriptionRepository
+	log  *zap.Logger
+}
+
+func NewWebhookListQueryHandler(repo webhook.SubscriptionRepository, log *zap.Logger) *WebhookListQueryHandler {
+	return &WebhookListQueryHandler{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (h *WebhookListQueryHandler) Handle(principal todo.Principal) ([]WebhookDTO, error) {
+
+	subscriptions, err := h.repo.List(principal)
+
+	if err != nil {
+		h.log.Error("failed to list webhooks", zap.Error(err))
+		return nil, err
+	}
+
+	items := make([]WebhookDTO, len(subscriptions))
+
+	for i := range subscriptions {
+		items[i] = toWebhookDTO(&subscriptions[i])
+	}
+
+	return items, nil
+}
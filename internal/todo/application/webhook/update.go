@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type UpdateWebhookCommand struct {
+	ID        string         `json:"-" uri:"id" binding:"required,uuid"` // Webhook ID
+	URL       string         `json:"url" binding:"required"`             // 目标地址
+	Events    []string       `json:"events"`                             // 订阅的事件,为空表示全部事件
+	Active    *bool          `json:"active"`                             // 是否启用,为空时保持不变
+	Principal todo.Principal `json:"-"`                                  // 当前用户,由接口层根据身份信息设置
+}
+
+type UpdateWebhookCommandHandler struct {
+	repo webhook.SubscriptionRepository
+	log  *zap.Logger
+}
+
+func NewUpdateWebhookCommandHandler(repo webhook.SubscriptionRepository, log *zap.Logger) *UpdateWebhookCommandHandler {
+	return &UpdateWebhookCommandHandler{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (h *UpdateWebhookCommandHandler) Handle(cmd UpdateWebhookCommand) (*WebhookDTO, error) {
+
+	id, err := uuid.Parse(cmd.ID)
+
+	if err != nil {
+		h.log.Error("invalid webhook id", zap.Error(err))
+		return nil, err
+	}
+
+	subscription, err := getOwned(h.repo, id, cmd.Principal)
+
+	if err != nil {
+		h.log.Error("failed to query webhook", zap.Error(err))
+		return nil, err
+	}
+
+	active := subscription.Active
+
+	if cmd.Active != nil {
+		active = *cmd.Active
+	}
+
+	if err := subscription.Update(cmd.URL, cmd.Events, active); err != nil {
+		h.log.Error("failed to update webhook", zap.Error(err))
+		return nil, err
+	}
+
+	if err := h.repo.Save(subscription); err != nil {
+		h.log.Error("failed to save webhook", zap.Error(err))
+		return nil, err
+	}
+
+	dto := toWebhookDTO(subscription)
+
+	return &dto, nil
+}
@@ -13,3 +13,11 @@ func DependencyInjection() []fx.Option {
 	}
 
 }
+
+// EventHandlerGroup 领域事件处理器的 fx 分组名
+const EventHandlerGroup = "todo_event_handlers"
+
+// AsEventHandler 将构造函数注册为领域事件处理器
+func AsEventHandler(ctor any) fx.Option {
+	return fx.Provide(fx.Annotate(ctor, fx.As(new(todo.EventHandler)), fx.ResultTags(`group:"`+EventHandlerGroup+`"`)))
+}
@@ -0,0 +1,22 @@
+package identity
+
+import "workit-sample/internal/todo/domain/todo"
+
+// 错误沿用 todo.TodoError,由接口层统一翻译为状态码与错误码
+var (
+	ErrInvalidCredentials = todo.TodoError{Code: "INVALID_CREDENTIALS", Kind: todo.KindUnauthorized, Message: "用户名或密码错误"}
+	ErrInvalidClient      = todo.TodoError{Code: "INVALID_CLIENT", Kind: todo.KindUnauthorized, Message: "客户端认证失败"}
+	ErrInvalidGrant       = todo.TodoError{Code: "INVALID_GRANT", Kind: todo.KindValidation, Message: "刷新令牌无效、已过期或已吊销"}
+	ErrUserNotFound       = todo.TodoError{Code: "USER_NOT_FOUND", Kind: todo.KindNotFound, Message: "用户未找到"}
+	ErrClientNotFound     = todo.TodoError{Code: "CLIENT_NOT_FOUND", Kind: todo.KindNotFound, Message: "客户端未找到"}
+	// ErrRefreshTokenReused 已轮换的刷新令牌再次使用,视为令牌泄露
+	ErrRefreshTokenReused = todo.TodoError{Code: "REFRESH_TOKEN_REUSED", Kind: todo.KindValidation, Message: "刷新令牌已被使用"}
+
+	ErrApiKeyNotFound      = todo.TodoError{Code: "API_KEY_NOT_FOUND", Kind: todo.KindNotFound, Message: "API Key 未找到"}
+	ErrApiKeyNameEmpty     = todo.TodoError{Code: "API_KEY_NAME_EMPTY", Kind: todo.KindValidation, Message: "API Key 名称不能为空"}
+	ErrInvalidScope        = todo.TodoError{Code: "INVALID_SCOPE", Kind: todo.KindValidation, Message: "无效的权限范围"}
+	ErrInvalidApiKeyExpiry = todo.TodoError{Code: "INVALID_API_KEY_EXPIRY", Kind: todo.KindValidation, Message: "过期时间必须晚于当前时间"}
+	ErrInsufficientScope   = todo.TodoError{Code: "INSUFFICIENT_SCOPE", Kind: todo.KindForbidden, Message: "API Key 只有读取权限"}
+	// ErrApiKeyNotAllowed 使用 API Key 管理 API Key,避免泄露的 API Key 用于创建新的 API Key
+	ErrApiKeyNotAllowed = todo.TodoError{Code: "API_KEY_NOT_ALLOWED", Kind: todo.KindForbidden, Message: "不能使用 API Key 管理 API Key"}
+)
@@ -0,0 +1,20 @@
+package identity
+
+import "crypto"
+
+// PublicKey 访问令牌的校验公钥,供其他服务校验令牌
+type PublicKey struct {
+	ID        string // kid
+	Algorithm string // RS256 或 ES256
+	Key       crypto.PublicKey
+}
+
+// KeySet 签名密钥集合
+type KeySet interface {
+	// PublicKeys 返回当前可用于校验的公钥,对称签名时为空
+	PublicKeys() []PublicKey
+}
+
+// WebSocketProtocol 浏览器的 WebSocket 无法设置 Authorization 请求头,握手时以子协议传递访问令牌:
+// Sec-WebSocket-Protocol: bearer, <access_token>,服务端选择 bearer 子协议作为回应
+const WebSocketProtocol = "bearer"
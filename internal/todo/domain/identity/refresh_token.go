@@ -0,0 +1,80 @@
+package identity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xiaohangshuhub/go-workit/pkg/ddd"
+)
+
+// RefreshToken 刷新令牌,每次使用后轮换为新令牌。同一次登录签发的令牌属于同一家族,
+// 已轮换的令牌再次使用时吊销整个家族
+type RefreshToken struct {
+	ddd.BaseAggregateRoot[uuid.UUID]
+	FamilyID  uuid.UUID  `gorm:"column:family_id"`
+	TokenHash string     `gorm:"column:token_hash"` // 令牌的 SHA-256 摘要,不保存明文
+	Subject   string     `gorm:"column:subject"`    // 用户名
+	ExpiresAt time.Time  `gorm:"column:expires_at"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+	RotatedAt *time.Time `gorm:"column:rotated_at"` // 轮换时间,轮换后不能再使用
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// NewRefreshToken 为用户签发新家族的刷新令牌,返回令牌与明文
+func NewRefreshToken(subject string, ttl time.Duration) (*RefreshToken, string) {
+	return newRefreshToken(uuid.New(), subject, time.Now(), ttl)
+}
+
+// Rotate 使用当前令牌换取同一家族的新令牌。已轮换的令牌返回 ErrRefreshTokenReused,
+// 已吊销或过期的令牌返回 ErrInvalidGrant
+func (t *RefreshToken) Rotate(now time.Time, ttl time.Duration) (*RefreshToken, string, error) {
+
+	if t.RevokedAt != nil || !now.Before(t.ExpiresAt) {
+		return nil, "", ErrInvalidGrant
+	}
+
+	if t.RotatedAt != nil {
+		return nil, "", ErrRefreshTokenReused
+	}
+
+	t.RotatedAt = &now
+
+	next, raw := newRefreshToken(t.FamilyID, t.Subject, now, ttl)
+
+	return next, raw, nil
+}
+
+// HashToken 计算令牌摘要,按摘要查找令牌
+func HashToken(raw string) string {
+
+	sum := sha256.Sum256([]byte(raw))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func newRefreshToken(familyID uuid.UUID, subject string, now time.Time, ttl time.Duration) (*RefreshToken, string) {
+
+	b := make([]byte, 32)
+
+	// crypto/rand.Read 不会返回错误
+	_, _ = rand.Read(b)
+
+	raw := base64.RawURLEncoding.EncodeToString(b)
+
+	return &RefreshToken{
+		BaseAggregateRoot: ddd.NewBaseAggregateRoot(uuid.New()),
+		FamilyID:          familyID,
+		TokenHash:         HashToken(raw),
+		Subject:           subject,
+		ExpiresAt:         now.Add(ttl),
+		CreatedAt:         now,
+	}, raw
+}
@@ -0,0 +1,53 @@
+package identity
+
+import (
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+)
+
+// UserStore 用户存储,默认由配置文件提供,可替换为数据库或目录服务实现
+type UserStore interface {
+	// FindUser 按用户名查找,不存在时返回 ErrUserNotFound
+	FindUser(username string) (*User, error)
+}
+
+// ClientStore 客户端存储
+type ClientStore interface {
+	// FindClient 按客户端 ID 查找,不存在时返回 ErrClientNotFound
+	FindClient(clientID string) (*Client, error)
+}
+
+// TokenIssuer 访问令牌签发者
+type TokenIssuer interface {
+	// Issue 为身份签发访问令牌,返回令牌与有效期
+	Issue(identity Identity) (string, time.Duration, error)
+	// RefreshTokenTTL 刷新令牌的有效期
+	RefreshTokenTTL() time.Duration
+}
+
+// RefreshTokenRepository 刷新令牌仓储
+type RefreshTokenRepository interface {
+	// GetByHash 按令牌摘要查找,不存在时返回 ErrInvalidGrant
+	GetByHash(hash string) (*RefreshToken, error)
+	Save(token *RefreshToken) error
+	// Rotate 保存轮换结果,当前令牌已被并发轮换或吊销时返回 ErrRefreshTokenReused
+	Rotate(current *RefreshToken, next *RefreshToken) error
+	// RevokeFamily 吊销家族中所有未吊销的令牌
+	RevokeFamily(familyID uuid.UUID, at time.Time) error
+}
+
+// ApiKeyRepository API Key 仓储
+type ApiKeyRepository interface {
+	// Get 不存在时返回 ErrApiKeyNotFound
+	Get(id uuid.UUID) (*ApiKey, error)
+	// GetByHash 按明文摘要查找,不存在时返回 ErrApiKeyNotFound
+	GetByHash(hash string) (*ApiKey, error)
+	Save(key *ApiKey) error
+	// List 按创建时间倒序返回 owner 的所有 API Key,包括已吊销的
+	List(owner todo.Principal) ([]ApiKey, error)
+	// Touch 更新最后使用时间
+	Touch(id uuid.UUID, at time.Time) error
+}
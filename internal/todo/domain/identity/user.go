@@ -0,0 +1,56 @@
+package identity
+
+import "golang.org/x/crypto/bcrypt"
+
+// dummyHash 与配置中摘要代价相同的固定 bcrypt 摘要,用户或客户端不存在时参与比较,
+// 使响应时间与密码错误时一致,避免通过耗时枚举用户名
+var dummyHash = []byte("$2a$10$sEKpL4nhWTNEBpMluyZ9munU5TbpdB0iImdXSr9u0uRycXC2t8vqS")
+
+// VerifyDummy 对固定摘要执行一次比较并丢弃结果,用于用户或客户端不存在时
+func VerifyDummy(secret string) {
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(secret))
+}
+
+// Identity 令牌代表的身份,写入访问令牌的声明
+type Identity struct {
+	Subject  string   // 用户名或客户端 ID,对应 sub 声明
+	TenantID string   // 所属租户,为空时由接口层按默认租户处理
+	Roles    []string // 角色
+	ClientID string   // 客户端凭据授权时为客户端 ID
+}
+
+// User 可通过密码授权登录的用户
+type User struct {
+	Username     string
+	PasswordHash string // bcrypt 摘要
+	TenantID     string
+	Roles        []string
+}
+
+// VerifyPassword 校验密码
+func (u *User) VerifyPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
+
+// Identity 返回用户的身份
+func (u *User) Identity() Identity {
+	return Identity{Subject: u.Username, TenantID: u.TenantID, Roles: u.Roles}
+}
+
+// Client 可通过客户端凭据授权获取令牌的服务
+type Client struct {
+	ID         string
+	SecretHash string // bcrypt 摘要
+	TenantID   string
+	Roles      []string
+}
+
+// VerifySecret 校验客户端密钥
+func (c *Client) VerifySecret(secret string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(c.SecretHash), []byte(secret)) == nil
+}
+
+// Identity 返回客户端的身份
+func (c *Client) Identity() Identity {
+	return Identity{Subject: c.ID, TenantID: c.TenantID, Roles: c.Roles, ClientID: c.ID}
+}
@@ -0,0 +1,17 @@
+package identity
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 固定摘要的代价必须与配置中的摘要一致,否则不存在的用户响应更快
+func TestDummyHashUsesDefaultCost(t *testing.T) {
+
+	cost, err := bcrypt.Cost(dummyHash)
+
+	if err != nil || cost != bcrypt.DefaultCost {
+		t.Fatalf("expected cost %d, got %d, %v", bcrypt.DefaultCost, cost, err)
+	}
+}
@@ -0,0 +1,55 @@
+package todo
+
+import (
+	"time"
+)
+
+// DefaultTimeZone 未指定时区时使用的时区
+const DefaultTimeZone = "UTC"
+
+// normalizeDue 校验时区并将截止时间转换到该时区,at 为空表示未设置截止时间,此时忽略时区
+func normalizeDue(at *time.Time, timeZone string) (*time.Time, string, error) {
+
+	if at == nil {
+		return nil, "", nil
+	}
+
+	if timeZone == "" {
+		timeZone = DefaultTimeZone
+	}
+
+	location, err := time.LoadLocation(timeZone)
+
+	if err != nil {
+		return nil, "", ErrInvalidTimeZone
+	}
+
+	local := at.In(location)
+
+	return &local, timeZone, nil
+}
+
+// LocalDue 返回截止时间在其时区中的表示,存储层返回的时间可能已丢失时区信息
+func LocalDue(at *time.Time, timeZone string) *time.Time {
+
+	local, _, err := normalizeDue(at, timeZone)
+
+	if err != nil {
+		return at
+	}
+
+	return local
+}
+
+// dueAfter 判断截止时间 a 是否晚于 b,b 未设置时不限制
+func dueAfter(a, b *time.Time) bool {
+	return a != nil && b != nil && a.After(*b)
+}
+
+// sameDue 判断两个截止时间是否相同
+func sameDue(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
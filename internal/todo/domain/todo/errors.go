@@ -1,6 +1,19 @@
 package todo
 
+// ErrorKind 错误类别,由接口层映射为对应的 HTTP 状态码
+type ErrorKind int
+
+const (
+	KindValidation   ErrorKind = iota + 1 // 参数或业务规则校验失败
+	KindNotFound                          // 资源不存在
+	KindConflict                          // 与现有数据冲突
+	KindForbidden                         // 无权执行该操作
+	KindUnauthorized                      // 身份认证失败
+)
+
 type TodoError struct {
+	Code    string    // 稳定的机器可读错误码
+	Kind    ErrorKind // 错误类别
 	Message string
 }
 
@@ -9,9 +22,39 @@ func (e TodoError) Error() string {
 }
 
 var (
-	ErrEmptyTodoTitle    = TodoError{Message: "待办事项标题不能为空"}
-	ErrTodoAlreadyExists = TodoError{Message: "待办事项已存在"}
-	ErrEmptyTaskTitle    = TodoError{Message: "任务标题不能为空"}
-	ErrTaskNotFound      = TodoError{Message: "任务未找到"}
-	ErrTaskTitleExists   = TodoError{Message: "任务标题已存在"}
+	ErrEmptyTodoTitle    = TodoError{Code: "TODO_TITLE_EMPTY", Kind: KindValidation, Message: "待办事项标题不能为空"}
+	ErrTodoAlreadyExists = TodoError{Code: "TODO_ALREADY_EXISTS", Kind: KindConflict, Message: "待办事项已存在"}
+	ErrTodoNotFound      = TodoError{Code: "TODO_NOT_FOUND", Kind: KindNotFound, Message: "待办事项未找到"}
+	ErrEmptyTaskTitle    = TodoError{Code: "TASK_TITLE_EMPTY", Kind: KindValidation, Message: "任务标题不能为空"}
+	ErrTaskNotFound      = TodoError{Code: "TASK_NOT_FOUND", Kind: KindNotFound, Message: "任务未找到"}
+	ErrTaskTitleExists   = TodoError{Code: "TASK_TITLE_EXISTS", Kind: KindConflict, Message: "任务标题已存在"}
+
+	// ErrInvalidTimeZone 截止时间的时区不是有效的 IANA 时区名称
+	ErrInvalidTimeZone = TodoError{Code: "INVALID_TIME_ZONE", Kind: KindValidation, Message: "无效的时区"}
+	// ErrTaskDueAfterTodo 任务的截止时间晚于所属待办事项的截止时间
+	ErrTaskDueAfterTodo = TodoError{Code: "TASK_DUE_AFTER_TODO", Kind: KindValidation, Message: "任务截止时间不能晚于待办事项截止时间"}
+
+	// ErrInvalidRecurrence 重复规则格式错误或使用了不支持的 RRULE 属性
+	ErrInvalidRecurrence = TodoError{Code: "INVALID_RECURRENCE", Kind: KindValidation, Message: "无效的重复规则"}
+	// ErrRecurrenceRequiresDue 重复的待办事项以截止时间计算下一次重复,必须设置截止时间
+	ErrRecurrenceRequiresDue = TodoError{Code: "RECURRENCE_REQUIRES_DUE", Kind: KindValidation, Message: "重复的待办事项必须设置截止时间"}
+
+	// ErrTaskAlreadyCompleted 任务已被完成,用于协作时拒绝重复操作
+	ErrTaskAlreadyCompleted = TodoError{Code: "TASK_ALREADY_COMPLETED", Kind: KindConflict, Message: "任务已完成"}
+
+	// ErrTodoForbidden 可以访问待办事项,但角色不足以执行该操作
+	ErrTodoForbidden = TodoError{Code: "TODO_FORBIDDEN", Kind: KindForbidden, Message: "没有权限执行该操作"}
+	// ErrInvalidRole 成员角色不是 viewer、editor 或 owner
+	ErrInvalidRole = TodoError{Code: "INVALID_ROLE", Kind: KindValidation, Message: "无效的成员角色"}
+	// ErrMemberUserEmpty 未指定成员的用户ID
+	ErrMemberUserEmpty = TodoError{Code: "MEMBER_USER_EMPTY", Kind: KindValidation, Message: "成员用户不能为空"}
+	// ErrMemberAlreadyExists 用户已是创建者或成员
+	ErrMemberAlreadyExists = TodoError{Code: "MEMBER_ALREADY_EXISTS", Kind: KindConflict, Message: "用户已是成员"}
+	// ErrMemberNotFound 用户不是待办事项的成员
+	ErrMemberNotFound = TodoError{Code: "MEMBER_NOT_FOUND", Kind: KindNotFound, Message: "成员未找到"}
+	// ErrOwnerRoleImmutable 创建者始终是 owner,不能修改角色或被移除
+	ErrOwnerRoleImmutable = TodoError{Code: "OWNER_ROLE_IMMUTABLE", Kind: KindValidation, Message: "不能修改或移除创建者"}
+
+	// ErrTodoVersionConflict 待办事项已被其他请求修改,写入基于过期版本
+	ErrTodoVersionConflict = TodoError{Code: "TODO_VERSION_CONFLICT", Kind: KindConflict, Message: "待办事项已被修改,请刷新后重试"}
 )
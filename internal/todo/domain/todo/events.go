@@ -0,0 +1,181 @@
+package todo
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xiaohangshuhub/go-workit/pkg/ddd"
+)
+
+// 事件名称
+const (
+	EventTodoCreated   = "todo.created"
+	EventTodoUpdated   = "todo.updated"
+	EventTaskAdded     = "todo.task_added"
+	EventTaskCompleted = "todo.task_completed"
+	EventTaskRemoved   = "todo.task_removed"
+	EventTodoCompleted = "todo.completed"
+	EventTodoDeleted   = "todo.deleted"
+	EventTodoRecurred  = "todo.recurred"
+
+	EventMemberInvited     = "todo.member_invited"
+	EventMemberRoleChanged = "todo.member_role_changed"
+	EventMemberRevoked     = "todo.member_revoked"
+
+	EventTodoDueReminder = "todo.due_reminder"      // 由提醒调度产生,不修改聚合
+	EventTaskDueReminder = "todo.task_due_reminder" // 由提醒调度产生,不修改聚合
+)
+
+// EventNames 所有事件名称
+var EventNames = []string{
+	EventTodoCreated, EventTodoUpdated, EventTaskAdded, EventTaskCompleted,
+	EventTaskRemoved, EventTodoCompleted, EventTodoDeleted, EventTodoRecurred,
+	EventMemberInvited, EventMemberRoleChanged, EventMemberRevoked,
+	EventTodoDueReminder, EventTaskDueReminder,
+}
+
+// Event 待办事项聚合产生的领域事件
+type Event interface {
+	Metadata() ddd.DomainEvent // 事件元数据
+	AggregateID() uuid.UUID    // 所属待办事项ID
+	Owner() Principal          // 所属待办事项的归属
+	VisibleTo(Principal) bool  // 用户是否可以收到该事件
+	Audience() []Principal     // 可以收到该事件的用户
+}
+
+// EventBase 领域事件公共字段
+type EventBase struct {
+	ddd.DomainEvent `json:"-"` // 元数据由发件箱等消息信封单独携带
+	TodoID          uuid.UUID  `json:"todoId"`
+	TenantID        string     `json:"tenantId"`
+	OwnerID         string     `json:"ownerId"`
+	Members         []string   `json:"-"` // 事件发生时的共享成员,只用于进程内按用户过滤
+}
+
+func newEventBase(name string, todoID uuid.UUID) EventBase {
+	return EventBase{
+		DomainEvent: ddd.DomainEvent{
+			EventId:   uuid.New(),
+			Created:   time.Now(),
+			EventName: name,
+		},
+		TodoID: todoID,
+	}
+}
+
+func (e EventBase) Metadata() ddd.DomainEvent {
+	return e.DomainEvent
+}
+
+func (e EventBase) AggregateID() uuid.UUID {
+	return e.TodoID
+}
+
+func (e EventBase) Owner() Principal {
+	return Principal{TenantID: e.TenantID, UserID: e.OwnerID}
+}
+
+func (e EventBase) VisibleTo(principal Principal) bool {
+	return principal.TenantID == e.TenantID && (principal.UserID == e.OwnerID || slices.Contains(e.Members, principal.UserID))
+}
+
+func (e EventBase) Audience() []Principal {
+
+	audience := []Principal{e.Owner()}
+
+	for _, userID := range e.Members {
+		audience = append(audience, Principal{TenantID: e.TenantID, UserID: userID})
+	}
+
+	return audience
+}
+
+// TodoCreated 待办事项已创建
+type TodoCreated struct {
+	EventBase
+	Title       string     `json:"title"`
+	DueAt       *time.Time `json:"dueAt,omitempty"`
+	DueTimeZone string     `json:"dueTimeZone,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty"`
+	SeriesID    *uuid.UUID `json:"seriesId,omitempty"`
+}
+
+// TodoUpdated 待办事项的标题、描述、完成状态或截止时间已修改,同一次保存只产生一个
+type TodoUpdated struct {
+	EventBase
+	Title       string     `json:"title"`
+	Description *string    `json:"description"`
+	Completed   bool       `json:"completed"`
+	DueAt       *time.Time `json:"dueAt"`
+	DueTimeZone string     `json:"dueTimeZone"`
+	Recurrence  string     `json:"recurrence"`
+}
+
+// TaskAdded 任务已添加
+type TaskAdded struct {
+	EventBase
+	TaskID uuid.UUID  `json:"taskId"`
+	Title  string     `json:"title"`
+	DueAt  *time.Time `json:"dueAt,omitempty"`
+}
+
+// TaskCompleted 任务已完成
+type TaskCompleted struct {
+	EventBase
+	TaskID uuid.UUID `json:"taskId"`
+}
+
+// TaskRemoved 任务已删除
+type TaskRemoved struct {
+	EventBase
+	TaskID uuid.UUID `json:"taskId"`
+}
+
+// TodoCompleted 待办事项已完成,包括所有任务完成后的自动完成
+type TodoCompleted struct {
+	EventBase
+}
+
+// TodoRecurred 重复的待办事项完成后已生成下一次重复
+type TodoRecurred struct {
+	EventBase
+	NextTodoID uuid.UUID `json:"nextTodoId"`
+	NextDueAt  time.Time `json:"nextDueAt"`
+	Occurrence int       `json:"occurrence"` // 下一次重复在系列中的序号
+}
+
+// TodoDeleted 待办事项及其任务已删除
+type TodoDeleted struct {
+	EventBase
+}
+
+// MemberInvited 用户已被邀请为成员
+type MemberInvited struct {
+	EventBase
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+}
+
+// MemberRoleChanged 成员的角色已修改
+type MemberRoleChanged struct {
+	EventBase
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+}
+
+// MemberRevoked 成员已被移除
+type MemberRevoked struct {
+	EventBase
+	UserID string `json:"userId"`
+}
+
+// EventHandler 领域事件处理器,按需对事件类型做判断
+type EventHandler interface {
+	Handle(event Event) error
+}
+
+// EventDispatcher 领域事件分发器,在聚合保存成功后调用
+type EventDispatcher interface {
+	Dispatch(events ...Event)
+}
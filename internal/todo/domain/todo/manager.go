@@ -1,49 +1,76 @@
 package todo
 
 import (
-	"errors"
-
 	"github.com/google/uuid"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 type TodoManager struct {
-	db  *gorm.DB
-	log *zap.Logger
+	repo TodoRepository
+	log  *zap.Logger
 }
 
-func NewTodoManager(db *gorm.DB, log *zap.Logger) (*TodoManager, error) {
+func NewTodoManager(repo TodoRepository, log *zap.Logger) (*TodoManager, error) {
 	return &TodoManager{
-		db:  db,
-		log: log,
+		repo: repo,
+		log:  log,
 	}, nil
 }
-func (m *TodoManager) CreateTodo(title string, desc *string) (*Todo, error) {
 
-	// 检查标题是否存在
-	var todo *Todo
+// WithRepository 返回使用指定仓储的副本,用于在工作单元内复用事务仓储
+func (m *TodoManager) WithRepository(repo TodoRepository) *TodoManager {
+	return &TodoManager{
+		repo: repo,
+		log:  m.log,
+	}
+}
+
+// CreateTodo 为 owner 创建待办事项,标题在同一用户的待办事项中唯一
+func (m *TodoManager) CreateTodo(owner Principal, title string, desc *string) (*Todo, error) {
 
-	tx := m.db.Where("title = ?", title).First(&todo)
+	// 检查标题是否存在
+	exists, err := m.repo.ExistsByTitle(owner, title, uuid.Nil)
 
-	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
-		m.log.Error("failed to check todo title", zap.Error(tx.Error))
-		return nil, tx.Error
+	if err != nil {
+		m.log.Error("failed to check todo title", zap.Error(err))
+		return nil, err
 	}
 
-	if tx.RowsAffected != 0 {
+	if exists {
 		m.log.Error("todo already exists", zap.String("title", title))
 		return nil, ErrTodoAlreadyExists
 	}
 
-	todo, err := NewTodo(uuid.New(), title)
-
-	todo.Description = desc
+	todo, err := NewTodo(uuid.New(), owner, title)
 
 	if err != nil {
 		m.log.Error("failed to create todo", zap.Error(err))
 		return nil, err
 	}
 
+	todo.Description = desc
+
 	return todo, nil
 }
+
+func (m *TodoManager) ChangeTitle(todo *Todo, title string) error {
+
+	if todo.Title == title {
+		return nil
+	}
+
+	// 检查标题是否被其他待办事项占用
+	exists, err := m.repo.ExistsByTitle(todo.Owner(), title, todo.ID)
+
+	if err != nil {
+		m.log.Error("failed to check todo title", zap.Error(err))
+		return err
+	}
+
+	if exists {
+		m.log.Error("todo already exists", zap.String("title", title))
+		return ErrTodoAlreadyExists
+	}
+
+	return todo.UpdateTitle(title)
+}
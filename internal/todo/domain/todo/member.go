@@ -0,0 +1,171 @@
+package todo
+
+import (
+	"time"
+
+	"github.com/xiaohangshuhub/go-workit/pkg/tools/str"
+
+	"github.com/google/uuid"
+)
+
+// 成员角色,权限依次递增
+const (
+	RoleViewer = "viewer" // 查看待办事项
+	RoleEditor = "editor" // 修改待办事项与任务
+	RoleOwner  = "owner"  // 删除待办事项与管理成员
+)
+
+var roleRanks = map[string]int{RoleViewer: 1, RoleEditor: 2, RoleOwner: 3}
+
+// Member 待办事项的共享成员,与待办事项属于同一租户;
+// 创建者不在成员中,始终拥有 owner 角色
+type Member struct {
+	TodoID    uuid.UUID `json:"todo_id" gorm:"column:todo_id;primaryKey"`
+	UserID    string    `json:"user_id" gorm:"column:user_id;primaryKey"`
+	Role      string    `json:"role" gorm:"column:role"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+func (Member) TableName() string {
+	return "todo_members"
+}
+
+// RoleOf 返回用户在待办事项中的角色,无权访问时返回空字符串
+func (t *Todo) RoleOf(principal Principal) string {
+
+	if principal.TenantID != t.TenantID {
+		return ""
+	}
+
+	if principal.UserID == t.OwnerID {
+		return RoleOwner
+	}
+
+	if i := t.memberIndex(principal.UserID); i >= 0 {
+		return t.Members[i].Role
+	}
+
+	return ""
+}
+
+// Authorize 校验用户是否拥有 role 及以上的角色。无权访问时与不存在一样返回 ErrTodoNotFound,
+// 不暴露数据是否存在;可以访问但角色不足时返回 ErrTodoForbidden。管理员可访问同一租户内的所有待办事项
+func (t *Todo) Authorize(principal Principal, role string) error {
+
+	current := t.RoleOf(principal)
+
+	if principal.Admin && principal.TenantID == t.TenantID {
+		current = RoleOwner
+	}
+
+	if current == "" {
+		return ErrTodoNotFound
+	}
+
+	// 只读凭据最多拥有查看权限
+	if principal.ReadOnly {
+		current = RoleViewer
+	}
+
+	if roleRanks[current] < roleRanks[role] {
+		return ErrTodoForbidden
+	}
+
+	return nil
+}
+
+// Invite 邀请同一租户内的用户成为成员
+func (t *Todo) Invite(userID, role string) error {
+
+	if err := validateMember(userID, role); err != nil {
+		return err
+	}
+
+	if userID == t.OwnerID || t.memberIndex(userID) >= 0 {
+		return ErrMemberAlreadyExists
+	}
+
+	t.Members = append(t.Members, Member{
+		TodoID:    t.ID,
+		UserID:    userID,
+		Role:      role,
+		CreatedAt: time.Now(),
+	})
+
+	t.raise(MemberInvited{EventBase: t.newEventBase(EventMemberInvited), UserID: userID, Role: role})
+
+	return nil
+}
+
+// ChangeRole 修改成员的角色,创建者的角色不能修改
+func (t *Todo) ChangeRole(userID, role string) error {
+
+	if err := validateMember(userID, role); err != nil {
+		return err
+	}
+
+	if userID == t.OwnerID {
+		return ErrOwnerRoleImmutable
+	}
+
+	i := t.memberIndex(userID)
+
+	if i < 0 {
+		return ErrMemberNotFound
+	}
+
+	if t.Members[i].Role == role {
+		return nil
+	}
+
+	t.Members[i].Role = role
+	t.raise(MemberRoleChanged{EventBase: t.newEventBase(EventMemberRoleChanged), UserID: userID, Role: role})
+
+	return nil
+}
+
+// Revoke 移除成员,创建者不能被移除
+func (t *Todo) Revoke(userID string) error {
+
+	if userID == t.OwnerID {
+		return ErrOwnerRoleImmutable
+	}
+
+	i := t.memberIndex(userID)
+
+	if i < 0 {
+		return ErrMemberNotFound
+	}
+
+	t.Members = append(t.Members[:i], t.Members[i+1:]...)
+
+	// 被移除的成员也需要收到通知
+	base := t.newEventBase(EventMemberRevoked)
+	base.Members = append(base.Members, userID)
+
+	t.raise(MemberRevoked{EventBase: base, UserID: userID})
+
+	return nil
+}
+
+func (t *Todo) memberIndex(userID string) int {
+	for i, member := range t.Members {
+		if member.UserID == userID {
+			return i
+		}
+	}
+	return -1
+}
+
+func validateMember(userID, role string) error {
+
+	if str.IsEmptyOrWhiteSpace(userID) {
+		return ErrMemberUserEmpty
+	}
+
+	if _, ok := roleRanks[role]; !ok {
+		return ErrInvalidRole
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package todo
+
+// DefaultTenant 身份信息中没有租户时使用的租户
+const DefaultTenant = "default"
+
+// Principal 发起操作的用户,决定新数据的归属与可访问的数据范围
+type Principal struct {
+	TenantID string // 租户ID
+	UserID   string // 用户ID,即身份信息中的 Subject
+	Admin    bool   // 技术支持管理员,可绕过共享权限访问同一租户内的待办事项
+	ReadOnly bool   // 只读凭据(如只读 API Key),无论角色如何都只能查看
+}
+
+// Is 判断是否为同一租户中的同一用户,不比较管理员与只读标记
+func (p Principal) Is(other Principal) bool {
+	return p.TenantID == other.TenantID && p.UserID == other.UserID
+}
@@ -0,0 +1,309 @@
+package todo
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 重复频率
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+// maxRecurrenceSearch 查找下一次重复时最多检查的周期数,避免无法满足的规则(如每年 2 月 31 日)死循环
+const maxRecurrenceSearch = 1000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Recurrence 重复规则,支持 RFC 5545 RRULE 的子集:
+// FREQ(DAILY、WEEKLY、MONTHLY、YEARLY)、INTERVAL、COUNT、UNTIL、
+// BYDAY(仅 WEEKLY,不支持序数前缀)与 BYMONTHDAY(仅 MONTHLY,负数表示倒数第几天)
+type Recurrence struct {
+	Freq       string
+	Interval   int
+	Count      int        // 系列的总次数,0 表示不限
+	Until      *time.Time // 最后一次重复的时间上限
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+// ParseRecurrence 解析重复规则,除 RRULE 外也接受 daily、weekly、monthly、yearly 简写
+func ParseRecurrence(rule string) (*Recurrence, error) {
+
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+
+	switch rule {
+	case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+		rule = "FREQ=" + rule
+	}
+
+	r := &Recurrence{Interval: 1}
+
+	for _, part := range strings.Split(rule, ";") {
+
+		name, value, ok := strings.Cut(part, "=")
+
+		if !ok || value == "" {
+			return nil, ErrInvalidRecurrence
+		}
+
+		var err error
+
+		switch name {
+		case "FREQ":
+			r.Freq = value
+		case "INTERVAL":
+			r.Interval, err = parsePositive(value)
+		case "COUNT":
+			r.Count, err = parsePositive(value)
+		case "UNTIL":
+			r.Until, err = parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseByMonthDay(value)
+		default:
+			err = ErrInvalidRecurrence
+		}
+
+		if err != nil {
+			return nil, ErrInvalidRecurrence
+		}
+	}
+
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *Recurrence) validate() error {
+
+	switch {
+	case !slices.Contains([]string{FreqDaily, FreqWeekly, FreqMonthly, FreqYearly}, r.Freq):
+		return ErrInvalidRecurrence
+	case r.Count > 0 && r.Until != nil: // RFC 5545 不允许同时指定
+		return ErrInvalidRecurrence
+	case len(r.ByDay) > 0 && r.Freq != FreqWeekly:
+		return ErrInvalidRecurrence
+	case len(r.ByMonthDay) > 0 && r.Freq != FreqMonthly:
+		return ErrInvalidRecurrence
+	}
+
+	return nil
+}
+
+// String 返回规范化的 RRULE 文本,用于存储
+func (r *Recurrence) String() string {
+
+	parts := []string{"FREQ=" + r.Freq}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = strings.ToUpper(day.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Next 返回 current 之后的下一次重复时间,按 current 的时区计算并保持当天的时刻不变;
+// occurrence 为 current 在系列中的序号(从 1 开始),超出 COUNT 或 UNTIL 时返回 false
+func (r *Recurrence) Next(current time.Time, occurrence int) (time.Time, bool) {
+
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+
+	next, ok := r.next(current)
+
+	if !ok || (r.Until != nil && next.After(*r.Until)) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+func (r *Recurrence) next(current time.Time) (time.Time, bool) {
+
+	switch r.Freq {
+	case FreqDaily:
+		return current.AddDate(0, 0, r.Interval), true
+
+	case FreqWeekly:
+		if len(r.ByDay) == 0 {
+			return current.AddDate(0, 0, 7*r.Interval), true
+		}
+
+		// 以周一为一周的开始,只在相隔 INTERVAL 整数倍的周内取值
+		week := weekStart(current)
+
+		for day := 1; day <= 7*r.Interval+7; day++ {
+			candidate := current.AddDate(0, 0, day)
+			weeks := int(weekStart(candidate).Sub(week).Hours()+12) / (24 * 7)
+
+			if slices.Contains(r.ByDay, candidate.Weekday()) && weeks%r.Interval == 0 {
+				return candidate, true
+			}
+		}
+
+	case FreqMonthly:
+		days := r.ByMonthDay
+
+		if len(days) == 0 {
+			days = []int{current.Day()}
+		}
+
+		for i := 0; i < maxRecurrenceSearch; i++ {
+
+			var candidates []time.Time
+
+			for _, day := range days {
+				if candidate, ok := dateOf(current, 0, i*r.Interval, day); ok && candidate.After(current) {
+					candidates = append(candidates, candidate)
+				}
+			}
+
+			if len(candidates) > 0 {
+				return slices.MinFunc(candidates, time.Time.Compare), true
+			}
+		}
+
+	case FreqYearly:
+		// 2 月 29 日只在闰年重复
+		for i := 1; i < maxRecurrenceSearch; i++ {
+			if candidate, ok := dateOf(current, i*r.Interval, 0, current.Day()); ok {
+				return candidate, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// dateOf 返回 current 所在月份偏移 years 年 months 月后的第 day 天,保持当天的时刻;
+// day 为负数时表示倒数第几天,该月不存在这一天时返回 false
+func dateOf(current time.Time, years, months, day int) (time.Time, bool) {
+
+	first := time.Date(current.Year()+years, current.Month()+time.Month(months), 1,
+		current.Hour(), current.Minute(), current.Second(), current.Nanosecond(), current.Location())
+
+	last := first.AddDate(0, 1, -1).Day()
+
+	if day < 0 {
+		day = last + day + 1
+	}
+
+	if day < 1 || day > last {
+		return time.Time{}, false
+	}
+
+	return first.AddDate(0, 0, day-1), true
+}
+
+// weekStart 返回所在周周一的零点
+func weekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+func parsePositive(value string) (int, error) {
+
+	n, err := strconv.Atoi(value)
+
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidRecurrence
+	}
+
+	return n, nil
+}
+
+// parseUntil 支持 UTC 时间(20251231T235959Z)与日期(20251231,按当天结束计算)两种格式
+func parseUntil(value string) (*time.Time, error) {
+
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return &until, nil
+	}
+
+	date, err := time.Parse("20060102", value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	until := date.AddDate(0, 0, 1).Add(-time.Second)
+
+	return &until, nil
+}
+
+func parseByDay(value string) ([]time.Weekday, error) {
+
+	var days []time.Weekday
+
+	for _, code := range strings.Split(value, ",") {
+
+		day, ok := weekdays[code]
+
+		if !ok {
+			return nil, fmt.Errorf("unsupported BYDAY value %q", code)
+		}
+
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+
+	var days []int
+
+	for _, s := range strings.Split(value, ",") {
+
+		day, err := strconv.Atoi(s)
+
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, fmt.Errorf("unsupported BYMONTHDAY value %q", s)
+		}
+
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+
+	return days, nil
+}
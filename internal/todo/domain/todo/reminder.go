@@ -0,0 +1,102 @@
+package todo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReminderEvent 截止提醒事件,同一提醒可能被多个调度周期或实例重复计算,由 ReminderKey 去重
+type ReminderEvent interface {
+	Event
+	ReminderKey() string
+}
+
+// DueReminder 截止提醒公共字段
+type DueReminder struct {
+	EventBase
+	Title         string    `json:"title"`
+	DueAt         time.Time `json:"dueAt"`
+	DueTimeZone   string    `json:"dueTimeZone"`
+	BeforeMinutes int64     `json:"beforeMinutes"` // 提醒时间距截止时间的分钟数,0 表示已到期
+}
+
+// TodoDueReminder 待办事项即将到期
+type TodoDueReminder struct {
+	DueReminder
+}
+
+func (e TodoDueReminder) ReminderKey() string {
+	return reminderKey(e.TodoID, uuid.Nil, e.DueAt, e.BeforeMinutes)
+}
+
+// TaskDueReminder 任务即将到期
+type TaskDueReminder struct {
+	DueReminder
+	TaskID uuid.UUID `json:"taskId"`
+}
+
+func (e TaskDueReminder) ReminderKey() string {
+	return reminderKey(e.TodoID, e.TaskID, e.DueAt, e.BeforeMinutes)
+}
+
+// reminderKey 截止时间变更后重新提醒
+func reminderKey(todoID, taskID uuid.UUID, dueAt time.Time, beforeMinutes int64) string {
+	return fmt.Sprintf("%s:%s:%d:%d", todoID, taskID, dueAt.Unix(), beforeMinutes)
+}
+
+// DueReminders 返回提醒时间(截止时间减去 offsets 中的提前量)落在 [from, to) 内的提醒,
+// 已完成的待办事项和任务不提醒
+func (t *Todo) DueReminders(from, to time.Time, offsets []time.Duration) []ReminderEvent {
+
+	if t.Completed {
+		return nil
+	}
+
+	var reminders []ReminderEvent
+
+	reminder := func(name, title string, dueAt *time.Time, timeZone string, offset time.Duration) (DueReminder, bool) {
+
+		if dueAt == nil {
+			return DueReminder{}, false
+		}
+
+		if at := dueAt.Add(-offset); at.Before(from) || !at.Before(to) {
+			return DueReminder{}, false
+		}
+
+		return DueReminder{
+			EventBase:     t.newEventBase(name),
+			Title:         title,
+			DueAt:         *LocalDue(dueAt, timeZone),
+			DueTimeZone:   timeZone,
+			BeforeMinutes: int64(offset / time.Minute),
+		}, true
+	}
+
+	for _, offset := range offsets {
+
+		if r, ok := reminder(EventTodoDueReminder, t.Title, t.DueAt, t.DueTimeZone, offset); ok {
+			reminders = append(reminders, TodoDueReminder{DueReminder: r})
+		}
+
+		for _, task := range t.Tasks {
+			if task.Completed {
+				continue
+			}
+
+			if r, ok := reminder(EventTaskDueReminder, task.Title, task.DueAt, task.DueTimeZone, offset); ok {
+				reminders = append(reminders, TaskDueReminder{DueReminder: r, TaskID: task.ID})
+			}
+		}
+	}
+
+	return reminders
+}
+
+// ReminderLog 已发送提醒的记录
+type ReminderLog interface {
+	// MarkSent 记录提醒已发送,返回 false 表示该提醒此前已记录
+	MarkSent(key string, at time.Time) (bool, error)
+}
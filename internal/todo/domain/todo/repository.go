@@ -0,0 +1,47 @@
+package todo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SortKey 列表排序键,按创建时间倒序, ID 倒序保证顺序稳定
+type SortKey struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// TodoSpecification 列表查询规约
+type TodoSpecification struct {
+	Principal Principal  // 只返回该用户创建或共享给该用户的待办事项,管理员返回同一租户内的所有待办事项
+	Keyword   string     // 标题或描述关键词
+	After     *SortKey   // 只返回排在该键之后(更早)的数据
+	Before    *SortKey   // 只返回排在该键之前(更新)的数据
+	DueFrom   *time.Time // 截止时间下限(含),与 DueTo 任一设置时只返回未完成且设置了截止时间的数据
+	DueTo     *time.Time // 截止时间上限(不含)
+	Offset    int        // 跳过条数
+	Limit     int        // 返回条数,0 表示不限制
+}
+
+// TodoRepository 待办事项聚合仓储
+type TodoRepository interface {
+	// Get 加载聚合及其任务与成员,不存在时返回 ErrTodoNotFound
+	Get(id uuid.UUID) (*Todo, error)
+	// Save 保存聚合及其任务与成员,并删除已从聚合中移除的任务与成员;
+	// 存储中的版本与聚合版本不一致时返回 ErrTodoVersionConflict,
+	// 与其他待办事项标题冲突时返回 ErrTodoAlreadyExists,成功后递增聚合版本
+	Save(todo *Todo) error
+	// Delete 删除聚合及其任务与成员,版本不一致时返回 ErrTodoVersionConflict
+	Delete(todo *Todo) error
+	// ExistsByTitle 判断 owner 的待办事项中除 excludeID 外是否存在相同标题的待办事项,
+	// 重复系列共用标题,只比较系列中尚未生成下一次重复的待办事项
+	ExistsByTitle(owner Principal, title string, excludeID uuid.UUID) (bool, error)
+	// List 按规约查询列表,结果按 SortKey 倒序排列,加载成员但不加载任务
+	List(spec TodoSpecification) ([]Todo, error)
+	// Count 按规约的归属、关键词与截止时间统计条数
+	Count(spec TodoSpecification) (int64, error)
+	// ListDue 加载自身或任一未完成任务的截止时间在 [from, to) 内的未完成聚合及其任务与成员,
+	// 与 DueFrom/DueTo 一致包含下限不含上限
+	ListDue(from, to time.Time) ([]Todo, error)
+}
@@ -1,6 +1,8 @@
 package todo
 
 import (
+	"time"
+
 	"github.com/xiaohangshuhub/go-workit/pkg/ddd"
 
 	"github.com/google/uuid"
@@ -8,8 +10,10 @@ import (
 
 type Task struct {
 	ddd.Entity[uuid.UUID]
-	Title       string    `json:"title" gorm:"column:title"`
-	Description *string   `json:"description" gorm:"column:description"`
-	Completed   bool      `json:"completed" gorm:"column:completed"`
-	TodoID      uuid.UUID `json:"todo_id" gorm:"column:todo_id"`
+	Title       string     `json:"title" gorm:"column:title"`
+	Description *string    `json:"description" gorm:"column:description"`
+	Completed   bool       `json:"completed" gorm:"column:completed"`
+	TodoID      uuid.UUID  `json:"todo_id" gorm:"column:todo_id"`
+	DueAt       *time.Time `json:"due_at" gorm:"column:due_at"`               // 截止时间,为空表示不限
+	DueTimeZone string     `json:"due_time_zone" gorm:"column:due_time_zone"` // 截止时间所属的 IANA 时区
 }
@@ -1,6 +1,8 @@
 package todo
 
 import (
+	"time"
+
 	"github.com/xiaohangshuhub/go-workit/pkg/ddd"
 	"github.com/xiaohangshuhub/go-workit/pkg/tools/str"
 
@@ -9,30 +11,139 @@ import (
 
 type Todo struct {
 	ddd.BaseAggregateRoot[uuid.UUID]
-	Title       string  `json:"title" gorm:"column:title"`
-	Description *string `json:"description" gorm:"column:description"`
-	Completed   bool    `json:"completed" gorm:"column:completed"`
-	Tasks       []Task  `json:"tasks" gorm:"foreignKey:TodoID;references:ID"`
+	TenantID    string     `json:"tenant_id" gorm:"column:tenant_id"` // 所属租户
+	OwnerID     string     `json:"owner_id" gorm:"column:owner_id"`   // 创建者,即身份信息中的 Subject
+	Title       string     `json:"title" gorm:"column:title"`
+	Description *string    `json:"description" gorm:"column:description"`
+	Completed   bool       `json:"completed" gorm:"column:completed"`
+	Tasks       []Task     `json:"tasks" gorm:"foreignKey:TodoID;references:ID"`
+	Members     []Member   `json:"members" gorm:"foreignKey:TodoID;references:ID"` // 共享成员
+	CreatedAt   time.Time  `json:"created_at" gorm:"column:created_at"`
+	Version     int64      `json:"version" gorm:"column:version"`             // 乐观锁版本号,每次保存递增,0 表示尚未持久化
+	DueAt       *time.Time `json:"due_at" gorm:"column:due_at"`               // 截止时间,为空表示不限
+	DueTimeZone string     `json:"due_time_zone" gorm:"column:due_time_zone"` // 截止时间所属的 IANA 时区
+	Recurrence  string     `json:"recurrence" gorm:"column:recurrence"`       // 规范化的 RRULE,为空表示不重复
+	SeriesID    *uuid.UUID `json:"series_id" gorm:"column:series_id"`         // 重复系列ID,即系列中第一个待办事项的ID
+	Occurrence  int        `json:"occurrence" gorm:"column:occurrence"`       // 在重复系列中的序号,从 1 开始
+	// NextOccurrenceID 已生成的下一次重复,每个待办事项只生成一次
+	NextOccurrenceID *uuid.UUID `json:"next_occurrence_id" gorm:"column:next_occurrence_id"`
+
+	events []Event // 尚未分发的领域事件
 }
 
-func NewTodo(id uuid.UUID, title string) (*Todo, error) {
+func NewTodo(id uuid.UUID, owner Principal, title string) (*Todo, error) {
 	if str.IsEmptyOrWhiteSpace(title) {
 		return nil, ErrEmptyTodoTitle
 	}
-	return &Todo{
+	todo := &Todo{
 		BaseAggregateRoot: ddd.NewBaseAggregateRoot(id),
+		TenantID:          owner.TenantID,
+		OwnerID:           owner.UserID,
 		Title:             title,
 		Completed:         false,
-	}, nil
+		CreatedAt:         time.Now(),
+	}
+
+	todo.raise(TodoCreated{EventBase: todo.newEventBase(EventTodoCreated), Title: title})
+
+	return todo, nil
+}
+
+// Owner 返回待办事项的归属
+func (t *Todo) Owner() Principal {
+	return Principal{TenantID: t.TenantID, UserID: t.OwnerID}
+}
+
+// newEventBase 创建携带归属与成员信息的事件公共字段,供事件订阅方按用户过滤
+func (t *Todo) newEventBase(name string) EventBase {
+
+	base := newEventBase(name, t.ID)
+	base.TenantID, base.OwnerID = t.TenantID, t.OwnerID
+
+	for _, member := range t.Members {
+		base.Members = append(base.Members, member.UserID)
+	}
+
+	return base
+}
+
+// raise 记录领域事件,同时登记到聚合根的事件元数据中
+func (t *Todo) raise(event Event) {
+	t.AddDomainEvent(event.Metadata())
+	t.events = append(t.events, event)
+}
+
+// touch 记录修改事件,已存在未分发的修改事件时以最新状态替换;
+// 尚未分发创建事件时,修改并入创建事件
+func (t *Todo) touch() {
+
+	event := TodoUpdated{
+		EventBase:   t.newEventBase(EventTodoUpdated),
+		Title:       t.Title,
+		Description: t.Description,
+		Completed:   t.Completed,
+		DueAt:       t.DueAt,
+		DueTimeZone: t.DueTimeZone,
+		Recurrence:  t.Recurrence,
+	}
+
+	for i, pending := range t.events {
+		switch e := pending.(type) {
+		case TodoCreated:
+			e.Title, e.DueAt, e.DueTimeZone = t.Title, t.DueAt, t.DueTimeZone
+			e.Recurrence, e.SeriesID = t.Recurrence, t.SeriesID
+			e.Members = event.Members
+			t.events[i] = e
+			return
+		case TodoUpdated:
+			event.EventBase = e.EventBase
+			t.events[i] = event
+			return
+		}
+	}
+
+	t.raise(event)
+}
+
+// Events 返回尚未分发的领域事件
+func (t *Todo) Events() []Event {
+	return t.events
+}
+
+// ClearEvents 清空并返回尚未分发的领域事件
+func (t *Todo) ClearEvents() []Event {
+	t.ClearDomainEvents()
+	events := t.events
+	t.events = nil
+	return events
+}
+
+// CheckVersion 校验客户端期望的版本号,未指定时不校验
+func (t *Todo) CheckVersion(expected *int64) error {
+	if expected != nil && *expected != t.Version {
+		return ErrTodoVersionConflict
+	}
+	return nil
 }
 
-func (t *Todo) AddTask(taskId uuid.UUID, title string, description *string) error {
+// AddTask 添加任务, dueAt 为空表示不设截止时间,否则不能晚于待办事项的截止时间
+func (t *Todo) AddTask(taskId uuid.UUID, title string, description *string, dueAt *time.Time, timeZone string) error {
 
 	// 判断标题是否为空
 	if str.IsEmptyOrWhiteSpace(title) {
 		return ErrEmptyTaskTitle
 	}
 
+	dueAt, timeZone, err := normalizeDue(dueAt, timeZone)
+
+	if err != nil {
+		return err
+	}
+
+	if dueAfter(dueAt, t.DueAt) {
+		return ErrTaskDueAfterTodo
+	}
+
 	// 判读task 中标题是否存在
 	for _, task := range t.Tasks {
 		if task.Title == title {
@@ -46,27 +157,210 @@ func (t *Todo) AddTask(taskId uuid.UUID, title string, description *string) erro
 		Description: description,
 		Completed:   false,
 		TodoID:      t.ID,
+		DueAt:       dueAt,
+		DueTimeZone: timeZone,
 	}
 
 	t.Tasks = append(t.Tasks, task)
 
 	// todo 任务添加了新的任务后，默认未完成
 	t.Completed = false
+
+	t.raise(TaskAdded{EventBase: t.newEventBase(EventTaskAdded), TaskID: taskId, Title: title, DueAt: dueAt})
+	return nil
+}
+
+// SetDue 设置截止时间, dueAt 为空时清除截止时间;已有任务的截止时间不能晚于新的截止时间
+func (t *Todo) SetDue(dueAt *time.Time, timeZone string) error {
+
+	dueAt, timeZone, err := normalizeDue(dueAt, timeZone)
+
+	if err != nil {
+		return err
+	}
+
+	if dueAt == nil && t.Recurrence != "" {
+		return ErrRecurrenceRequiresDue
+	}
+
+	for _, task := range t.Tasks {
+		if dueAfter(task.DueAt, dueAt) {
+			return ErrTaskDueAfterTodo
+		}
+	}
+
+	if sameDue(t.DueAt, dueAt) && t.DueTimeZone == timeZone {
+		return nil
+	}
+
+	t.DueAt = dueAt
+	t.DueTimeZone = timeZone
+	t.touch()
+
+	return nil
+}
+
+// SetRecurrence 设置重复规则, rule 为空时取消重复;当前截止时间即为本次重复的时间
+func (t *Todo) SetRecurrence(rule string) error {
+
+	normalized := ""
+
+	if !str.IsEmptyOrWhiteSpace(rule) {
+
+		recurrence, err := ParseRecurrence(rule)
+
+		if err != nil {
+			return err
+		}
+
+		if t.DueAt == nil {
+			return ErrRecurrenceRequiresDue
+		}
+
+		normalized = recurrence.String()
+	}
+
+	if t.Recurrence == normalized {
+		return nil
+	}
+
+	t.Recurrence = normalized
+
+	// 首次设置时以自身作为系列的第一次重复
+	if normalized != "" && t.SeriesID == nil {
+		seriesID := t.ID
+		t.SeriesID = &seriesID
+		t.Occurrence = 1
+	}
+
+	t.touch()
+
 	return nil
 }
 
+// NextOccurrence 为已完成的重复待办事项生成下一次重复:截止时间按规则顺延,
+// 任务按相同间隔顺延并重置为未完成,同一系列共用标题。
+// 未完成、不重复、已生成过或系列已结束时返回 nil
+func (t *Todo) NextOccurrence(id uuid.UUID) (*Todo, error) {
+
+	if !t.Completed || t.Recurrence == "" || t.NextOccurrenceID != nil || t.DueAt == nil {
+		return nil, nil
+	}
+
+	recurrence, err := ParseRecurrence(t.Recurrence)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 按截止时间所属时区计算,保证夏令时切换前后的时刻不变
+	current := LocalDue(t.DueAt, t.DueTimeZone)
+
+	dueAt, ok := recurrence.Next(*current, t.Occurrence)
+
+	if !ok {
+		return nil, nil
+	}
+
+	next, err := NewTodo(id, t.Owner(), t.Title)
+
+	if err != nil {
+		return nil, err
+	}
+
+	next.Description = t.Description
+	next.SeriesID = t.SeriesID
+
+	// 下一次重复沿用相同的共享成员
+	for _, member := range t.Members {
+		next.Members = append(next.Members, Member{TodoID: id, UserID: member.UserID, Role: member.Role, CreatedAt: time.Now()})
+	}
+	next.Occurrence = t.Occurrence + 1
+
+	if err := next.SetDue(&dueAt, t.DueTimeZone); err != nil {
+		return nil, err
+	}
+
+	if err := next.SetRecurrence(t.Recurrence); err != nil {
+		return nil, err
+	}
+
+	shift := dueAt.Sub(*current)
+
+	for _, task := range t.Tasks {
+
+		var taskDueAt *time.Time
+
+		if task.DueAt != nil {
+			shifted := task.DueAt.Add(shift)
+			taskDueAt = &shifted
+		}
+
+		if err := next.AddTask(uuid.New(), task.Title, task.Description, taskDueAt, task.DueTimeZone); err != nil {
+			return nil, err
+		}
+	}
+
+	t.NextOccurrenceID = &id
+	t.raise(TodoRecurred{EventBase: t.newEventBase(EventTodoRecurred), NextTodoID: id, NextDueAt: dueAt, Occurrence: next.Occurrence})
+
+	return next, nil
+}
+
 func (t *Todo) UpdateTitle(title string) error {
 	if str.IsEmptyOrWhiteSpace(title) {
 		return ErrEmptyTodoTitle
 	}
-	t.Title = title
+	if t.Title != title {
+		t.Title = title
+		t.touch()
+	}
 	return nil
 }
 
+func (t *Todo) UpdateDescription(description *string) {
+	if sameDescription(t.Description, description) {
+		return
+	}
+	t.Description = description
+	t.touch()
+}
+
+// sameDescription 判断两个描述是否相同
+func sameDescription(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (t *Todo) UpdateCompleted(completed bool) {
+
+	// 标记完成时，同时完成所有任务
+	if completed {
+		for i := range t.Tasks {
+			if !t.Tasks[i].Completed {
+				t.Tasks[i].Completed = true
+				t.raise(TaskCompleted{EventBase: t.newEventBase(EventTaskCompleted), TaskID: t.Tasks[i].ID})
+			}
+		}
+	}
+
+	if completed && !t.Completed {
+		t.raise(TodoCompleted{EventBase: t.newEventBase(EventTodoCompleted)})
+	}
+
+	if t.Completed != completed {
+		t.Completed = completed
+		t.touch()
+	}
+}
+
 func (t *Todo) RemoveTask(taskId uuid.UUID) error {
 	for i, task := range t.Tasks {
 		if task.ID == taskId {
 			t.Tasks = append(t.Tasks[:i], t.Tasks[i+1:]...)
+			t.raise(TaskRemoved{EventBase: t.newEventBase(EventTaskRemoved), TaskID: taskId})
 			return nil
 		}
 	}
@@ -82,10 +376,19 @@ func (t *Todo) RemoveTasks(taskIds []uuid.UUID) error {
 	return nil
 }
 
+// Delete 记录删除事件,由仓储的 Delete 完成实际删除
+func (t *Todo) Delete() {
+	t.raise(TodoDeleted{EventBase: t.newEventBase(EventTodoDeleted)})
+}
+
 func (t *Todo) MarkAsCompleted(taskId uuid.UUID) error {
 	for i, task := range t.Tasks {
 		if task.ID == taskId {
-			t.Tasks[i].Completed = true
+
+			if !task.Completed {
+				t.Tasks[i].Completed = true
+				t.raise(TaskCompleted{EventBase: t.newEventBase(EventTaskCompleted), TaskID: taskId})
+			}
 
 			// 如果所有任务都完成，则将 Todo 标记为完成
 			allCompleted := true
@@ -95,8 +398,9 @@ func (t *Todo) MarkAsCompleted(taskId uuid.UUID) error {
 					break
 				}
 			}
-			if allCompleted {
+			if allCompleted && !t.Completed {
 				t.Completed = true
+				t.raise(TodoCompleted{EventBase: t.newEventBase(EventTodoCompleted)})
 			}
 
 			return nil
@@ -0,0 +1,277 @@
+package todo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestUpdateIgnoresUnchangedValues(t *testing.T) {
+
+	description := "From supermarket"
+
+	todo, err := NewTodo(uuid.New(), Principal{TenantID: DefaultTenant, UserID: "alice"}, "Buy milk")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	todo.Description = &description
+	todo.ClearEvents()
+
+	same := description
+
+	if err := todo.UpdateTitle("Buy milk"); err != nil {
+		t.Fatal(err)
+	}
+
+	todo.UpdateDescription(&same)
+
+	if events := todo.ClearEvents(); len(events) != 0 {
+		t.Fatalf("expected no events, got %d", len(events))
+	}
+
+	todo.UpdateDescription(nil)
+
+	if events := todo.ClearEvents(); len(events) != 1 || events[0].Metadata().EventName != EventTodoUpdated {
+		t.Fatalf("expected one %s event, got %v", EventTodoUpdated, events)
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+
+	tests := []struct {
+		rule string
+		want string // 为空表示规则无效
+	}{
+		{"daily", "FREQ=DAILY"},
+		{"freq=daily;interval=1", "FREQ=DAILY"},
+		{"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE,MO", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"},
+		{"FREQ=MONTHLY;BYMONTHDAY=31,-1;COUNT=5", "FREQ=MONTHLY;BYMONTHDAY=31,-1;COUNT=5"},
+		{"FREQ=YEARLY;UNTIL=20301231", "FREQ=YEARLY;UNTIL=20301231T235959Z"},
+		{"FREQ=DAILY;UNTIL=20300101T120000Z", "FREQ=DAILY;UNTIL=20300101T120000Z"},
+
+		{"", ""},
+		{"FREQ", ""},
+		{"FREQ=", ""},
+		{"FREQ=HOURLY", ""},
+		{"FREQ=DAILY;FOO=1", ""},
+		{"FREQ=DAILY;INTERVAL=0", ""},
+		{"FREQ=DAILY;COUNT=-1", ""},
+		{"FREQ=DAILY;UNTIL=tomorrow", ""},
+		{"FREQ=DAILY;COUNT=3;UNTIL=20301231", ""},
+		{"FREQ=DAILY;BYDAY=MO", ""},
+		{"FREQ=MONTHLY;BYDAY=MO", ""},
+		{"FREQ=WEEKLY;BYDAY=1MO", ""},
+		{"FREQ=WEEKLY;BYMONTHDAY=1", ""},
+		{"FREQ=MONTHLY;BYMONTHDAY=0", ""},
+		{"FREQ=MONTHLY;BYMONTHDAY=32", ""},
+	}
+
+	for _, tt := range tests {
+
+		r, err := ParseRecurrence(tt.rule)
+
+		if tt.want == "" {
+			if !errors.Is(err, ErrInvalidRecurrence) {
+				t.Errorf("%q: expected ErrInvalidRecurrence, got %v", tt.rule, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%q: %v", tt.rule, err)
+			continue
+		}
+
+		if got := r.String(); got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.rule, tt.want, got)
+		}
+
+		// 规范化后的文本再次解析结果不变
+		again, err := ParseRecurrence(r.String())
+
+		if err != nil || again.String() != r.String() {
+			t.Errorf("%q: round trip gave %v, %v", tt.rule, again, err)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+
+	newYork, err := time.LoadLocation("America/New_York")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := func(loc *time.Location, year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []time.Time // 依次返回的重复时间,返回 false 后结束
+	}{
+		{
+			// 2025-03-09 起夏令时,间隔两周且保持当地 9 点
+			name:  "weekly byday interval across dst",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=4",
+			start: date(newYork, 2025, 3, 5, 9),
+			want:  []time.Time{date(newYork, 2025, 3, 17, 9), date(newYork, 2025, 3, 19, 9), date(newYork, 2025, 3, 31, 9)},
+		},
+		{
+			name:  "monthly on 31st skips short months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=4",
+			start: date(time.UTC, 2025, 1, 31, 10),
+			want:  []time.Time{date(time.UTC, 2025, 3, 31, 10), date(time.UTC, 2025, 5, 31, 10), date(time.UTC, 2025, 7, 31, 10)},
+		},
+		{
+			name:  "monthly on last day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=4",
+			start: date(time.UTC, 2024, 1, 31, 10),
+			want:  []time.Time{date(time.UTC, 2024, 2, 29, 10), date(time.UTC, 2024, 3, 31, 10), date(time.UTC, 2024, 4, 30, 10)},
+		},
+		{
+			name:  "yearly from leap day",
+			rule:  "FREQ=YEARLY;COUNT=3",
+			start: date(time.UTC, 2024, 2, 29, 8),
+			want:  []time.Time{date(time.UTC, 2028, 2, 29, 8), date(time.UTC, 2032, 2, 29, 8)},
+		},
+		{
+			name:  "count",
+			rule:  "FREQ=DAILY;COUNT=2",
+			start: date(time.UTC, 2025, 1, 1, 10),
+			want:  []time.Time{date(time.UTC, 2025, 1, 2, 10)},
+		},
+		{
+			// UNTIL 为日期时包含当天
+			name:  "until",
+			rule:  "FREQ=DAILY;UNTIL=20250103",
+			start: date(time.UTC, 2025, 1, 1, 10),
+			want:  []time.Time{date(time.UTC, 2025, 1, 2, 10), date(time.UTC, 2025, 1, 3, 10)},
+		},
+	}
+
+	for _, tt := range tests {
+
+		r, err := ParseRecurrence(tt.rule)
+
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		var got []time.Time
+
+		current := tt.start
+
+		for occurrence := 1; occurrence <= 10; occurrence++ {
+
+			next, ok := r.Next(current, occurrence)
+
+			if !ok {
+				break
+			}
+
+			got = append(got, next)
+			current = next
+		}
+
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+			continue
+		}
+
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) || got[i].Hour() != tt.want[i].Hour() {
+				t.Errorf("%s: occurrence %d expected %s, got %s", tt.name, i+2, tt.want[i], got[i])
+			}
+		}
+	}
+}
+
+// 完成最后一个任务后生成下一次重复,任务顺延并重置为未完成
+func TestCompletingLastTaskSpawnsNextOccurrence(t *testing.T) {
+
+	newYork, err := time.LoadLocation("America/New_York")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := NewTodo(uuid.New(), Principal{TenantID: DefaultTenant, UserID: "alice"}, "Weekly review")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	due := time.Date(2025, 3, 5, 9, 0, 0, 0, newYork)
+	taskDue := due.Add(-time.Hour)
+	taskID := uuid.New()
+
+	if err := current.SetDue(&due, "America/New_York"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := current.SetRecurrence("weekly"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := current.AddTask(taskID, "Collect notes", nil, &taskDue, "America/New_York"); err != nil {
+		t.Fatal(err)
+	}
+
+	if next, _ := current.NextOccurrence(uuid.New()); next != nil {
+		t.Fatal("an unfinished todo must not recur")
+	}
+
+	if err := current.MarkAsCompleted(taskID); err != nil {
+		t.Fatal(err)
+	}
+
+	if !current.Completed {
+		t.Fatal("expected todo to be completed with its last task")
+	}
+
+	current.ClearEvents()
+
+	nextID := uuid.New()
+	next, err := current.NextOccurrence(nextID)
+
+	if err != nil || next == nil {
+		t.Fatalf("next occurrence: %v, %v", next, err)
+	}
+
+	if want := time.Date(2025, 3, 12, 9, 0, 0, 0, newYork); !next.DueAt.Equal(want) {
+		t.Errorf("expected due %s, got %s", want, next.DueAt)
+	}
+
+	if next.Completed || next.Occurrence != 2 || *next.SeriesID != *current.SeriesID || next.Title != current.Title {
+		t.Errorf("unexpected next occurrence %+v", next)
+	}
+
+	if len(next.Tasks) != 1 || next.Tasks[0].Completed || next.Tasks[0].ID == taskID || next.Tasks[0].Title != "Collect notes" {
+		t.Fatalf("expected one reset task, got %+v", next.Tasks)
+	}
+
+	if want := time.Date(2025, 3, 12, 8, 0, 0, 0, newYork); !next.Tasks[0].DueAt.Equal(want) {
+		t.Errorf("expected task due %s, got %s", want, next.Tasks[0].DueAt)
+	}
+
+	if current.NextOccurrenceID == nil || *current.NextOccurrenceID != nextID {
+		t.Error("expected current occurrence to point at the next one")
+	}
+
+	if events := current.ClearEvents(); len(events) != 1 || events[0].Metadata().EventName != EventTodoRecurred {
+		t.Errorf("expected one %s event, got %v", EventTodoRecurred, events)
+	}
+
+	// 每次重复只生成一次
+	if again, _ := current.NextOccurrence(uuid.New()); again != nil {
+		t.Error("expected next occurrence to be generated only once")
+	}
+}
@@ -0,0 +1,7 @@
+package todo
+
+// UnitOfWork 工作单元,fn 内通过 repo 进行的读写处于同一事务中,
+// fn 返回 nil 时提交,返回任何错误时回滚
+type UnitOfWork interface {
+	Execute(fn func(repo TodoRepository) error) error
+}
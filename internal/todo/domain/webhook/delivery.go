@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xiaohangshuhub/go-workit/pkg/ddd"
+)
+
+// 投递状态
+const (
+	DeliveryPending   = "pending"   // 等待投递或重试
+	DeliverySucceeded = "succeeded" // 对方返回 2xx
+	DeliveryFailed    = "failed"    // 超过最大投递次数或订阅已停用,不再重试
+)
+
+// Delivery 一次事件投递及其投递记录,重新投递会产生新的记录
+type Delivery struct {
+	ddd.Entity[uuid.UUID]
+	SubscriptionID uuid.UUID  `json:"subscriptionId" gorm:"column:subscription_id"`
+	EventID        uuid.UUID  `json:"eventId" gorm:"column:event_id"`
+	EventName      string     `json:"event" gorm:"column:event_name"`
+	Payload        string     `json:"payload" gorm:"column:payload"` // 请求体,重试与重新投递时原样发送
+	Status         string     `json:"status" gorm:"column:status"`
+	Attempts       int        `json:"attempts" gorm:"column:attempts"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt" gorm:"column:next_attempt_at"`
+	LastStatusCode *int       `json:"lastStatusCode" gorm:"column:last_status_code"` // 最近一次响应状态码,网络错误时为空
+	LastError      *string    `json:"lastError" gorm:"column:last_error"`
+	CreatedAt      time.Time  `json:"createdAt" gorm:"column:created_at"`
+	DeliveredAt    *time.Time `json:"deliveredAt" gorm:"column:delivered_at"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func NewDelivery(subscriptionID, eventID uuid.UUID, eventName string, payload string) *Delivery {
+
+	now := time.Now()
+
+	return &Delivery{
+		Entity:         ddd.NewEntity(uuid.New()),
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventName:      eventName,
+		Payload:        payload,
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+}
+
+// Redeliver 以相同的事件与请求体创建新的投递
+func (d *Delivery) Redeliver() *Delivery {
+	return NewDelivery(d.SubscriptionID, d.EventID, d.EventName, d.Payload)
+}
+
+// Succeed 记录投递成功
+func (d *Delivery) Succeed(statusCode int, at time.Time) {
+	d.Attempts++
+	d.Status = DeliverySucceeded
+	d.LastStatusCode = &statusCode
+	d.LastError = nil
+	d.DeliveredAt = &at
+}
+
+// Fail 记录投递失败, next 为空时不再重试
+func (d *Delivery) Fail(statusCode *int, reason string, next *time.Time) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = &reason
+
+	if next == nil {
+		d.Status = DeliveryFailed
+		return
+	}
+
+	d.NextAttemptAt = *next
+}
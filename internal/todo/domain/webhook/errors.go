@@ -0,0 +1,14 @@
+package webhook
+
+import "workit-sample/internal/todo/domain/todo"
+
+// 错误沿用 todo.TodoError,由接口层统一翻译为状态码与错误码
+var (
+	ErrWebhookNotFound   = todo.TodoError{Code: "WEBHOOK_NOT_FOUND", Kind: todo.KindNotFound, Message: "Webhook 未找到"}
+	ErrInvalidWebhookURL = todo.TodoError{Code: "INVALID_WEBHOOK_URL", Kind: todo.KindValidation, Message: "Webhook 地址必须是 http 或 https 绝对地址"}
+	ErrUnknownEvent      = todo.TodoError{Code: "UNKNOWN_WEBHOOK_EVENT", Kind: todo.KindValidation, Message: "不支持的 Webhook 事件"}
+	ErrDeliveryNotFound  = todo.TodoError{Code: "DELIVERY_NOT_FOUND", Kind: todo.KindNotFound, Message: "投递记录未找到"}
+
+	// ErrWebhookURLNotAllowed 目标地址指向本机、内网或链路本地地址,防止借助投递访问内部服务
+	ErrWebhookURLNotAllowed = todo.TodoError{Code: "WEBHOOK_URL_NOT_ALLOWED", Kind: todo.KindValidation, Message: "Webhook 地址不能指向本机或内网地址"}
+)
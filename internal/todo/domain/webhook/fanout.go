@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+)
+
+// Payload Webhook 请求体
+type Payload struct {
+	ID        uuid.UUID  `json:"id"`        // 事件ID,接收方可据此去重
+	Event     string     `json:"event"`     // 事件名称
+	CreatedAt time.Time  `json:"createdAt"` // 事件发生时间
+	Data      todo.Event `json:"data"`      // 事件内容
+}
+
+// Fanout 为订阅了事件的创建者与共享成员生成待投递记录,由投递程序异步发送。
+// 调用方应在写入聚合的同一事务中调用,提交后投递记录不会因进程退出而丢失
+func Fanout(subscriptions SubscriptionRepository, deliveries DeliveryRepository, events ...todo.Event) error {
+
+	for _, event := range events {
+		if err := fanout(subscriptions, deliveries, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func fanout(subscriptions SubscriptionRepository, deliveries DeliveryRepository, event todo.Event) error {
+
+	meta := event.Metadata()
+
+	var payload []byte
+
+	for _, principal := range event.Audience() {
+
+		owned, err := subscriptions.List(principal)
+
+		if err != nil {
+			return err
+		}
+
+		for i := range owned {
+
+			subscription := &owned[i]
+
+			if !subscription.Matches(event) {
+				continue
+			}
+
+			if payload == nil {
+				payload, err = json.Marshal(Payload{
+					ID:        meta.EventId,
+					Event:     meta.EventName,
+					CreatedAt: meta.Created,
+					Data:      event,
+				})
+
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := deliveries.Save(NewDelivery(subscription.ID, meta.EventId, meta.EventName, string(payload))); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionRepository Webhook 订阅仓储
+type SubscriptionRepository interface {
+	// Get 不存在时返回 ErrWebhookNotFound
+	Get(id uuid.UUID) (*Subscription, error)
+	Save(subscription *Subscription) error
+	// Delete 删除订阅及其投递记录
+	Delete(subscription *Subscription) error
+	// List 按创建时间倒序返回 owner 的所有订阅
+	List(owner todo.Principal) ([]Subscription, error)
+}
+
+// DeliveryRepository 投递记录仓储
+type DeliveryRepository interface {
+	// Get 不存在时返回 ErrDeliveryNotFound
+	Get(id uuid.UUID) (*Delivery, error)
+	Save(delivery *Delivery) error
+	// ListBySubscription 按创建时间倒序返回订阅的投递记录, limit 为 0 表示不限制
+	ListBySubscription(subscriptionID uuid.UUID, limit int) ([]Delivery, error)
+	// Claim 按计划时间升序领取到期待投递的记录,并将下次投递时间推迟 lease 作为租约,
+	// 租约内其他实例不会重复领取;投递结果保存时覆盖,实例退出后租约到期即可被重新领取
+	Claim(now time.Time, limit int, lease time.Duration) ([]Delivery, error)
+}
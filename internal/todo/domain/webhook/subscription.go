@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"github.com/xiaohangshuhub/go-workit/pkg/ddd"
+)
+
+// Subscription Webhook 订阅,事件发生后向目标地址投递签名的 JSON
+type Subscription struct {
+	ddd.BaseAggregateRoot[uuid.UUID]
+	TenantID  string    `json:"tenantId" gorm:"column:tenant_id"`
+	OwnerID   string    `json:"ownerId" gorm:"column:owner_id"` // 只投递该用户待办事项的事件
+	URL       string    `json:"url" gorm:"column:url"`
+	Events    []string  `json:"events" gorm:"column:events;serializer:json"` // 订阅的事件,为空表示全部事件
+	Secret    string    `json:"-" gorm:"column:secret"`                      // HMAC-SHA256 签名密钥
+	Active    bool      `json:"active" gorm:"column:active"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"column:updated_at"`
+}
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// NewSubscription 创建订阅,未指定密钥时随机生成
+func NewSubscription(id uuid.UUID, owner todo.Principal, target string, events []string, secret string) (*Subscription, error) {
+
+	subscription := &Subscription{
+		BaseAggregateRoot: ddd.NewBaseAggregateRoot(id),
+		TenantID:          owner.TenantID,
+		OwnerID:           owner.UserID,
+		Secret:            secret,
+		Active:            true,
+		CreatedAt:         time.Now(),
+	}
+
+	if err := subscription.Update(target, events, true); err != nil {
+		return nil, err
+	}
+
+	if subscription.Secret == "" {
+		subscription.Secret = newSecret()
+	}
+
+	return subscription, nil
+}
+
+// Update 修改目标地址、订阅事件与启用状态
+func (s *Subscription) Update(target string, events []string, active bool) error {
+
+	if err := validateURL(target); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if !slices.Contains(todo.EventNames, event) {
+			return ErrUnknownEvent
+		}
+	}
+
+	s.URL = target
+	s.Events = slices.Compact(slices.Sorted(slices.Values(events)))
+	s.Active = active
+	s.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Owner 返回订阅的所有者
+func (s *Subscription) Owner() todo.Principal {
+	return todo.Principal{TenantID: s.TenantID, UserID: s.OwnerID}
+}
+
+// Matches 判断订阅是否需要投递指定事件,只投递所有者可以访问的待办事项事件
+func (s *Subscription) Matches(event todo.Event) bool {
+
+	if !s.Active || !event.VisibleTo(s.Owner()) {
+		return false
+	}
+
+	return len(s.Events) == 0 || slices.Contains(s.Events, event.Metadata().EventName)
+}
+
+// validateURL 校验地址格式,并拒绝直接指向本机或内网的地址。
+// 域名在投递时才解析,解析结果由投递方在建立连接时再次校验
+func validateURL(target string) error {
+
+	u, err := url.Parse(target)
+
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrWebhookURLNotAllowed
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil && !PublicAddress(addr) {
+		return ErrWebhookURLNotAllowed
+	}
+
+	return nil
+}
+
+// sharedAddressSpace 运营商级 NAT 地址段(RFC 6598),与内网地址一样不可从公网访问
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// PublicAddress 判断是否为允许投递的公网地址,
+// 本机、内网、链路本地(含云厂商元数据地址 169.254.169.254)、组播与未指定地址都不允许
+func PublicAddress(addr netip.Addr) bool {
+
+	addr = addr.Unmap()
+
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!(addr.Is4() && addr.As4()[0] == 0) &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+func newSecret() string {
+
+	b := make([]byte, 32)
+
+	// crypto/rand.Read 不会返回错误
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+)
+
+func TestNewSubscriptionValidatesURL(t *testing.T) {
+
+	owner := todo.Principal{TenantID: todo.DefaultTenant, UserID: "alice"}
+
+	tests := []struct {
+		url string
+		err error
+	}{
+		{"https://hooks.example.com/todo", nil},
+		{"http://203.0.113.10:8080/hook", nil},
+		{"ftp://hooks.example.com/todo", ErrInvalidWebhookURL},
+		{"/relative", ErrInvalidWebhookURL},
+		{"http://localhost:8080/hook", ErrWebhookURLNotAllowed},
+		{"http://api.LOCALHOST/hook", ErrWebhookURLNotAllowed},
+		{"http://127.0.0.1/hook", ErrWebhookURLNotAllowed},
+		{"http://10.0.0.5/hook", ErrWebhookURLNotAllowed},
+		{"http://192.168.1.1/hook", ErrWebhookURLNotAllowed},
+		{"http://169.254.169.254/latest/meta-data", ErrWebhookURLNotAllowed},
+		{"http://100.64.0.1/hook", ErrWebhookURLNotAllowed},
+		{"http://0.0.0.0/hook", ErrWebhookURLNotAllowed},
+		{"http://[::1]/hook", ErrWebhookURLNotAllowed},
+		{"http://[fd00::1]/hook", ErrWebhookURLNotAllowed},
+		{"http://[fe80::1]/hook", ErrWebhookURLNotAllowed},
+		{"http://[::ffff:127.0.0.1]/hook", ErrWebhookURLNotAllowed},
+	}
+
+	for _, tt := range tests {
+
+		_, err := NewSubscription(uuid.New(), owner, tt.url, nil, "")
+
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v, got %v", tt.url, tt.err, err)
+		}
+	}
+}
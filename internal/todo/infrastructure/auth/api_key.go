@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"workit-sample/internal/todo/domain/identity"
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/xiaohangshuhub/go-workit/pkg/workit"
+	"go.uber.org/zap"
+)
+
+// HeaderApiKey 携带 API Key 的请求头
+const HeaderApiKey = "X-API-Key"
+
+// touchInterval 最后使用时间的更新间隔,避免每个请求都写数据库
+const touchInterval = time.Minute
+
+// ApiKeyHandler 校验请求头中的 API Key,身份为 API Key 的所有者,角色每次请求从用户存储读取所有者当前的角色。
+// 鉴权方案需在构建应用前注册,仓储与用户存储在依赖注入完成后通过 Bind 设置
+type ApiKeyHandler struct {
+	keys    identity.ApiKeyRepository
+	users   identity.UserStore
+	clients identity.ClientStore
+	log     *zap.Logger
+	mu      sync.RWMutex
+}
+
+func NewApiKeyHandler() *ApiKeyHandler {
+	return &ApiKeyHandler{}
+}
+
+// Bind 设置 API Key 仓储与用户存储
+func (h *ApiKeyHandler) Bind(keys identity.ApiKeyRepository, users identity.UserStore, clients identity.ClientStore, log *zap.Logger) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.keys = keys
+	h.users = users
+	h.clients = clients
+	h.log = log
+}
+
+func (h *ApiKeyHandler) Scheme() string {
+	return identity.MethodApiKey
+}
+
+func (h *ApiKeyHandler) Authenticate(r *http.Request) (*workit.ClaimsPrincipal, error) {
+
+	raw := r.Header.Get(HeaderApiKey)
+
+	if raw == "" {
+		return nil, errors.New("api key not found")
+	}
+
+	h.mu.RLock()
+	keys, users, clients, log := h.keys, h.users, h.clients, h.log
+	h.mu.RUnlock()
+
+	if keys == nil {
+		return nil, errors.New("api key repository not bound")
+	}
+
+	key, err := keys.GetByHash(identity.HashToken(raw))
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if !key.Usable(now) {
+		return nil, errors.New("api key revoked or expired")
+	}
+
+	roles, err := ownerRoles(key, users, clients)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= touchInterval {
+		if err := keys.Touch(key.ID, now); err != nil {
+			log.Warn("failed to update api key last used time", zap.Error(err))
+		}
+	}
+
+	principal := &workit.ClaimsPrincipal{
+		Subject:              key.OwnerID,
+		Name:                 key.OwnerID,
+		AuthenticationMethod: identity.MethodApiKey,
+		AuthenticatedAt:      now,
+	}
+
+	for _, role := range roles {
+		principal.AddRole(role)
+	}
+
+	principal.AddClaim("sub", key.OwnerID)
+	principal.AddClaim("tenant_id", key.TenantID)
+	principal.AddClaim(identity.ClaimScope, key.Scope)
+	principal.AddClaim(identity.ClaimApiKeyID, key.ID.String())
+
+	return principal, nil
+}
+
+// ownerRoles 返回所有者当前的角色,所有者被移除或转到其他租户后 API Key 随之失效。
+// 通过客户端凭据授权的身份以客户端 ID 作为所有者
+func ownerRoles(key *identity.ApiKey, users identity.UserStore, clients identity.ClientStore) ([]string, error) {
+
+	var tenantID string
+	var roles []string
+
+	user, err := users.FindUser(key.OwnerID)
+
+	switch {
+	case err == nil:
+		tenantID, roles = user.TenantID, user.Roles
+	case errors.Is(err, identity.ErrUserNotFound):
+		client, err := clients.FindClient(key.OwnerID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		tenantID, roles = client.TenantID, client.Roles
+	default:
+		return nil, err
+	}
+
+	if tenantID == "" {
+		tenantID = todo.DefaultTenant
+	}
+
+	if tenantID != key.TenantID {
+		return nil, errors.New("api key owner belongs to another tenant")
+	}
+
+	return roles, nil
+}
+
+// SchemeSelector 按请求选择鉴权方案:携带 X-API-Key 请求头时使用 API Key,否则使用 Bearer 令牌
+type SchemeSelector struct {
+	bearer workit.AuthenticationHandler
+	apiKey workit.AuthenticationHandler
+}
+
+func NewSchemeSelector(bearer workit.AuthenticationHandler, apiKey workit.AuthenticationHandler) *SchemeSelector {
+	return &SchemeSelector{
+		bearer: bearer,
+		apiKey: apiKey,
+	}
+}
+
+func (s *SchemeSelector) Scheme() string {
+	return "selector"
+}
+
+func (s *SchemeSelector) Authenticate(r *http.Request) (*workit.ClaimsPrincipal, error) {
+
+	if r.Header.Get(HeaderApiKey) != "" {
+		return s.apiKey.Authenticate(r)
+	}
+
+	return s.bearer.Authenticate(r)
+}
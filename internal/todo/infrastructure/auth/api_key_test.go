@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"workit-sample/internal/todo/domain/identity"
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/infrastructure/persistence"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 角色每次请求从用户存储读取,修改或移除用户后立即生效
+func TestApiKeyUsesOwnerCurrentRoles(t *testing.T) {
+
+	repo := persistence.NewMemoryApiKeyRepository()
+
+	key, raw, err := identity.NewApiKey(uuid.New(), todo.Principal{TenantID: "acme", UserID: "alice"}, "ci", identity.ScopeWrite, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Save(key); err != nil {
+		t.Fatal(err)
+	}
+
+	authenticate := func(users ...UserOptions) ([]string, error) {
+
+		store := NewConfigStore(Options{Users: users})
+
+		handler := NewApiKeyHandler()
+		handler.Bind(repo, store, store, zap.NewNop())
+
+		r := httptest.NewRequest("GET", "/todos", nil)
+		r.Header.Set(HeaderApiKey, raw)
+
+		principal, err := handler.Authenticate(r)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return principal.Roles, nil
+	}
+
+	if roles, err := authenticate(UserOptions{Username: "alice", TenantID: "acme", Roles: []string{"Admin"}}); err != nil || len(roles) != 1 || roles[0] != "Admin" {
+		t.Fatalf("expected Admin role, got %v, %v", roles, err)
+	}
+
+	if roles, err := authenticate(UserOptions{Username: "alice", TenantID: "acme"}); err != nil || len(roles) != 0 {
+		t.Fatalf("expected revoked Admin role to be dropped, got %v, %v", roles, err)
+	}
+
+	if _, err := authenticate(); err == nil {
+		t.Fatal("expected key of a removed user to be rejected")
+	}
+
+	if _, err := authenticate(UserOptions{Username: "alice", TenantID: "other"}); err == nil {
+		t.Fatal("expected key to be rejected after the owner moved to another tenant")
+	}
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"workit-sample/internal/todo/domain/identity"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+	"github.com/xiaohangshuhub/go-workit/pkg/workit"
+)
+
+// BearerHandler 校验本服务签发的访问令牌。非对称签名时按头部的 kid 从密钥环查找公钥,
+// 因此可以同时接受轮换前后的多个密钥签发的令牌
+type BearerHandler struct {
+	keys    *KeyRing
+	options Options
+}
+
+func NewBearerHandler(keys *KeyRing, options Options) *BearerHandler {
+	return &BearerHandler{
+		keys:    keys,
+		options: options,
+	}
+}
+
+func (h *BearerHandler) Scheme() string {
+	return workit.SchemeJwtBearer
+}
+
+func (h *BearerHandler) Authenticate(r *http.Request) (*workit.ClaimsPrincipal, error) {
+
+	token, err := bearerToken(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(h.methods()),
+		jwt.WithIssuer(h.options.Issuer),
+		jwt.WithAudience(h.options.Audience),
+		jwt.WithExpirationRequired(),
+	)
+
+	claims := jwt.MapClaims{}
+
+	if _, err := parser.ParseWithClaims(token, claims, h.key); err != nil {
+		return nil, err
+	}
+
+	return principalFromClaims(claims), nil
+}
+
+// bearerToken 读取 Authorization 请求头中的访问令牌,WebSocket 握手请求未携带该请求头时从子协议中读取
+func bearerToken(r *http.Request) (string, error) {
+
+	header := r.Header.Get("Authorization")
+
+	if header == "" {
+
+		if protocols := websocket.Subprotocols(r); websocket.IsWebSocketUpgrade(r) && len(protocols) == 2 && protocols[0] == identity.WebSocketProtocol {
+			return protocols[1], nil
+		}
+
+		return "", errors.New("token not found")
+	}
+
+	if !strings.HasPrefix(strings.ToLower(header), "bearer ") {
+		return "", errors.New("authorization header missing Bearer prefix")
+	}
+
+	return strings.TrimSpace(header[len("Bearer "):]), nil
+}
+
+// key 按签名算法与 kid 返回校验密钥,密钥算法与令牌头部的算法必须一致
+func (h *BearerHandler) key(token *jwt.Token) (any, error) {
+
+	if !h.keys.Asymmetric() {
+		return []byte(h.options.SigningKey), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := h.keys.verifier(kid)
+
+	if !ok {
+		return nil, errors.New("key not found for kid")
+	}
+
+	if token.Method.Alg() != key.algorithm {
+		return nil, errors.New("signing method does not match key")
+	}
+
+	return key.private.Public(), nil
+}
+
+func (h *BearerHandler) methods() []string {
+
+	if !h.keys.Asymmetric() {
+		return []string{AlgorithmHS256}
+	}
+
+	return []string{AlgorithmRS256, AlgorithmES256}
+}
+
+// principalFromClaims 与框架内置的 JwtBearer 方案保持一致:sub 作为标识与名称,
+// role 与 roles 声明作为角色,其余声明原样保留
+func principalFromClaims(claims jwt.MapClaims) *workit.ClaimsPrincipal {
+
+	principal := &workit.ClaimsPrincipal{
+		Claims: make([]workit.Claim, 0, len(claims)),
+	}
+
+	if sub, ok := claims["sub"].(string); ok {
+		principal.Subject = sub
+		principal.Name = sub
+	}
+
+	if iat, ok := claims["iat"].(float64); ok {
+		principal.AuthenticatedAt = time.Unix(int64(iat), 0)
+	}
+
+	if iss, ok := claims["iss"].(string); ok {
+		principal.IdentityProvider = iss
+	}
+
+	for _, key := range []string{"role", "roles"} {
+		switch value := claims[key].(type) {
+		case string:
+			principal.AddRole(value)
+		case []any:
+			for _, item := range value {
+				if role, ok := item.(string); ok {
+					principal.AddRole(role)
+				}
+			}
+		}
+	}
+
+	for key, value := range claims {
+		principal.AddClaim(key, value)
+	}
+
+	return principal
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"time"
+
+	"workit-sample/internal/todo/domain/identity"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// accessClaims 访问令牌声明,tenant_id 与 roles 由接口层解析为当前用户
+type accessClaims struct {
+	jwt.RegisteredClaims
+	TenantID string   `json:"tenant_id,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+	ClientID string   `json:"client_id,omitempty"`
+}
+
+// JwtIssuer 使用密钥环的当前签名密钥签发访问令牌,非对称签名时在头部写入 kid
+type JwtIssuer struct {
+	keys    *KeyRing
+	options Options
+}
+
+func NewJwtIssuer(keys *KeyRing, options Options) *JwtIssuer {
+	return &JwtIssuer{
+		keys:    keys,
+		options: options,
+	}
+}
+
+func (i *JwtIssuer) Issue(subject identity.Identity) (string, time.Duration, error) {
+
+	now := time.Now()
+
+	claims := accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   subject.Subject,
+			Issuer:    i.options.Issuer,
+			Audience:  jwt.ClaimStrings{i.options.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(i.options.AccessTokenTTL)),
+		},
+		TenantID: subject.TenantID,
+		Roles:    subject.Roles,
+		ClientID: subject.ClientID,
+	}
+
+	token, err := i.sign(claims)
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	return token, i.options.AccessTokenTTL, nil
+}
+
+func (i *JwtIssuer) RefreshTokenTTL() time.Duration {
+	return i.options.RefreshTokenTTL
+}
+
+func (i *JwtIssuer) sign(claims accessClaims) (string, error) {
+
+	if !i.keys.Asymmetric() {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(i.options.SigningKey))
+	}
+
+	key, err := i.keys.signer()
+
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.algorithm), claims)
+	token.Header["kid"] = key.id
+
+	return token.SignedString(key.private)
+}
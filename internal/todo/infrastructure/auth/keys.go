@@ -0,0 +1,333 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"workit-sample/internal/todo/domain/identity"
+)
+
+// 签名算法
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+)
+
+// kidLayout 生成的 kid 以创建时间开头,密钥的创建时间取自 kid,复制或修改文件不会改变密钥的先后顺序
+const kidLayout = "20060102T150405Z"
+
+// signingKey 非对称签名密钥,文件名(不含扩展名)作为 kid
+type signingKey struct {
+	id        string
+	algorithm string
+	private   crypto.Signer
+	createdAt time.Time
+}
+
+// KeyRing 签名密钥环。HS256 使用配置的对称密钥;RS256 与 ES256 从密钥目录加载 PEM 私钥,
+// 最新的密钥用于签名,其余密钥在被替换后的一个访问令牌有效期内仍可用于校验
+type KeyRing struct {
+	options Options
+	keys    []signingKey // 按创建时间倒序
+	mu      sync.RWMutex
+}
+
+// NewKeyRing 加载密钥目录,非对称算法下目录中没有密钥时生成一个。启动时任何密钥文件无法加载都视为配置错误
+func NewKeyRing(options Options) (*KeyRing, error) {
+
+	ring := &KeyRing{
+		options: options,
+	}
+
+	if !ring.Asymmetric() {
+		return ring, nil
+	}
+
+	if err := ring.Reload(); err != nil {
+		return nil, err
+	}
+
+	if len(ring.snapshot()) == 0 {
+		if _, err := ring.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return ring, nil
+}
+
+// Asymmetric 是否使用非对称签名
+func (r *KeyRing) Asymmetric() bool {
+	return r.options.Algorithm != AlgorithmHS256
+}
+
+// Reload 重新扫描密钥目录,无需重启即可增加或移除密钥。无法加载的文件被跳过并通过错误返回,
+// 其余密钥照常生效,同一 kid 之前加载的密钥继续使用
+func (r *KeyRing) Reload() error {
+
+	entries, err := os.ReadDir(r.options.Keys.Dir)
+
+	if errors.Is(err, os.ErrNotExist) {
+		entries = nil
+	} else if err != nil {
+		return err
+	}
+
+	previous := r.snapshot()
+
+	var keys []signingKey
+	var errs []error
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pem" {
+			continue
+		}
+
+		key, err := loadKey(filepath.Join(r.options.Keys.Dir, entry.Name()))
+
+		if err != nil {
+			errs = append(errs, err)
+
+			kid := strings.TrimSuffix(entry.Name(), ".pem")
+
+			if i := slices.IndexFunc(previous, func(key signingKey) bool { return key.id == kid }); i >= 0 {
+				keys = append(keys, previous[i])
+			}
+
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	r.replace(keys)
+
+	return errors.Join(errs...)
+}
+
+// RotationDue 当前签名密钥是否已超过轮换间隔
+func (r *KeyRing) RotationDue(now time.Time) bool {
+
+	if !r.Asymmetric() || r.options.Keys.RotationInterval <= 0 {
+		return false
+	}
+
+	keys := r.snapshot()
+
+	return len(keys) == 0 || !now.Before(keys[0].createdAt.Add(r.options.Keys.RotationInterval))
+}
+
+// Rotate 按配置的算法生成新密钥并写入密钥目录,新密钥立即用于签名,返回新密钥的 kid
+func (r *KeyRing) Rotate() (string, error) {
+
+	private, err := generateKey(r.options.Algorithm)
+
+	if err != nil {
+		return "", err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(r.options.Keys.Dir, 0o700); err != nil {
+		return "", err
+	}
+
+	suffix := make([]byte, 4)
+
+	// crypto/rand.Read 不会返回错误
+	_, _ = rand.Read(suffix)
+
+	// 多个实例共享密钥目录时,随机后缀避免同时轮换产生相同的 kid
+	now := time.Now().UTC().Truncate(time.Second)
+	kid := now.Format(kidLayout) + "-" + hex.EncodeToString(suffix)
+
+	file := filepath.Join(r.options.Keys.Dir, kid+".pem")
+
+	// 先写入临时文件再重命名,其他实例扫描目录时不会读到写了一半的密钥
+	if err := os.WriteFile(file+".tmp", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(file+".tmp", file); err != nil {
+		return "", err
+	}
+
+	algorithm := AlgorithmRS256
+
+	if r.options.Algorithm == AlgorithmES256 {
+		algorithm = AlgorithmES256
+	}
+
+	r.replace(append([]signingKey{{id: kid, algorithm: algorithm, private: private, createdAt: now}}, r.snapshot()...))
+
+	return kid, nil
+}
+
+// PublicKeys 返回可用于校验的公钥
+func (r *KeyRing) PublicKeys() []identity.PublicKey {
+
+	keys := r.active(time.Now())
+	publicKeys := make([]identity.PublicKey, 0, len(keys))
+
+	for _, key := range keys {
+		publicKeys = append(publicKeys, identity.PublicKey{ID: key.id, Algorithm: key.algorithm, Key: key.private.Public()})
+	}
+
+	return publicKeys
+}
+
+// signer 返回当前签名密钥
+func (r *KeyRing) signer() (signingKey, error) {
+
+	keys := r.snapshot()
+
+	if len(keys) == 0 {
+		return signingKey{}, errors.New("no signing key available")
+	}
+
+	return keys[0], nil
+}
+
+// verifier 按 kid 查找可用于校验的公钥
+func (r *KeyRing) verifier(kid string) (signingKey, bool) {
+
+	for _, key := range r.active(time.Now()) {
+		if key.id == kid {
+			return key, true
+		}
+	}
+
+	return signingKey{}, false
+}
+
+// active 返回未退役的密钥,被替换超过一个访问令牌有效期的密钥签发的令牌都已过期,不再使用
+func (r *KeyRing) active(now time.Time) []signingKey {
+
+	keys := r.snapshot()
+
+	for i := 1; i < len(keys); i++ {
+		if !now.Before(keys[i-1].createdAt.Add(r.options.AccessTokenTTL)) {
+			return keys[:i]
+		}
+	}
+
+	return keys
+}
+
+// replace 按创建时间倒序保存密钥
+func (r *KeyRing) replace(keys []signingKey) {
+
+	slices.SortFunc(keys, func(a, b signingKey) int {
+		if c := b.createdAt.Compare(a.createdAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.id, a.id)
+	})
+
+	r.mu.Lock()
+	r.keys = keys
+	r.mu.Unlock()
+}
+
+func (r *KeyRing) snapshot() []signingKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.keys
+}
+
+func loadKey(file string) (signingKey, error) {
+
+	data, err := os.ReadFile(file)
+
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return signingKey{}, fmt.Errorf("invalid pem file %s", file)
+	}
+
+	var private any
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		private, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+
+	if err != nil {
+		return signingKey{}, fmt.Errorf("parse private key %s: %w", file, err)
+	}
+
+	id := strings.TrimSuffix(filepath.Base(file), ".pem")
+
+	key := signingKey{
+		id:        id,
+		createdAt: createdAt(id),
+	}
+
+	switch private := private.(type) {
+	case *rsa.PrivateKey:
+		key.algorithm, key.private = AlgorithmRS256, private
+	case *ecdsa.PrivateKey:
+		if private.Curve != elliptic.P256() {
+			return signingKey{}, fmt.Errorf("unsupported curve in %s, ES256 requires P-256", file)
+		}
+		key.algorithm, key.private = AlgorithmES256, private
+	default:
+		return signingKey{}, fmt.Errorf("unsupported private key type in %s", file)
+	}
+
+	return key, nil
+}
+
+// createdAt 从 kid 解析密钥的创建时间。不符合生成格式的 kid(如手动导入的密钥)视为最早创建,
+// 只在没有其他密钥时用于签名
+func createdAt(kid string) time.Time {
+
+	if len(kid) < len(kidLayout) {
+		return time.Time{}
+	}
+
+	at, err := time.Parse(kidLayout, kid[:len(kidLayout)])
+
+	if err != nil {
+		return time.Time{}
+	}
+
+	return at
+}
+
+func generateKey(algorithm string) (crypto.Signer, error) {
+
+	if algorithm == AlgorithmES256 {
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+
+	return rsa.GenerateKey(rand.Reader, 2048)
+}
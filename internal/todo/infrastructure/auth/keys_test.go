@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func newKeyRing(t *testing.T) *KeyRing {
+	t.Helper()
+
+	ring, err := NewKeyRing(Options{
+		Algorithm:      AlgorithmES256,
+		AccessTokenTTL: 15 * time.Minute,
+		Keys:           KeyOptions{Dir: t.TempDir(), ReloadInterval: time.Minute},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ring
+}
+
+// 复制或 touch 旧密钥文件不会让它重新成为签名密钥
+func TestSignerIgnoresFileModificationTime(t *testing.T) {
+
+	ring := newKeyRing(t)
+	first, _ := ring.signer()
+
+	// 同一秒内生成的 kid 只靠随机后缀排序
+	time.Sleep(time.Second)
+
+	second, err := ring.Rotate()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	future := time.Now().Add(time.Hour)
+
+	if err := os.Chtimes(filepath.Join(ring.options.Keys.Dir, first.id+".pem"), future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ring.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if signer, _ := ring.signer(); signer.id != second {
+		t.Fatalf("expected %s to sign, got %s", second, signer.id)
+	}
+}
+
+func TestReloadSkipsUnreadableKey(t *testing.T) {
+
+	ring := newKeyRing(t)
+	signer, _ := ring.signer()
+	dir := ring.options.Keys.Dir
+
+	// 写了一半的密钥文件与无关的损坏文件都不影响已加载的密钥
+	if err := os.WriteFile(filepath.Join(dir, signer.id+".pem"), []byte("-----BEGIN PRIVATE"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := ring.Reload()
+
+	if err == nil || !strings.Contains(err.Error(), "broken.pem") {
+		t.Fatalf("expected error naming broken.pem, got %v", err)
+	}
+
+	if keys := ring.snapshot(); len(keys) != 1 || keys[0].id != signer.id {
+		t.Fatalf("expected previously loaded key to stay in use, got %d keys", len(keys))
+	}
+}
+
+func TestCreatedAtFromKid(t *testing.T) {
+
+	if at := createdAt("20250102T030405Z-ab12cd34"); !at.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("unexpected creation time %s", at)
+	}
+
+	if at := createdAt("imported"); !at.IsZero() {
+		t.Errorf("expected foreign kid to sort first, got %s", at)
+	}
+}
+
+func TestNewOptionsRejectsInvalidIntervals(t *testing.T) {
+
+	for _, key := range []string{"auth.keys.reload_interval", "auth.keys.rotation_interval"} {
+
+		v := viper.New()
+		v.Set("auth.algorithm", AlgorithmRS256)
+		v.Set(key, "-1s")
+
+		if _, err := NewOptions(v); err == nil || !strings.Contains(err.Error(), key) {
+			t.Errorf("expected error for %s, got %v", key, err)
+		}
+	}
+
+	v := viper.New()
+	v.Set("auth.algorithm", AlgorithmRS256)
+	v.Set("auth.keys.reload_interval", "0s")
+
+	if _, err := NewOptions(v); err == nil {
+		t.Error("expected error for zero reload interval")
+	}
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Options 令牌签发配置,对应 application.yaml 中的 auth 节点
+type Options struct {
+	Issuer          string          `mapstructure:"issuer"`            // 签发者,与令牌校验参数的 ValidIssuer 一致
+	Audience        string          `mapstructure:"audience"`          // 受众,与令牌校验参数的 ValidAudience 一致
+	Algorithm       string          `mapstructure:"algorithm"`         // 签名算法: HS256, RS256, ES256
+	SigningKey      string          `mapstructure:"signing_key"`       // HS256 签名密钥
+	Keys            KeyOptions      `mapstructure:"keys"`              // RS256 与 ES256 的密钥目录与轮换
+	AccessTokenTTL  time.Duration   `mapstructure:"access_token_ttl"`  // 访问令牌有效期
+	RefreshTokenTTL time.Duration   `mapstructure:"refresh_token_ttl"` // 刷新令牌有效期
+	Users           []UserOptions   `mapstructure:"users"`             // 可通过密码授权登录的用户
+	Clients         []ClientOptions `mapstructure:"clients"`           // 可通过客户端凭据授权的客户端
+}
+
+// KeyOptions 非对称签名密钥配置
+type KeyOptions struct {
+	Dir              string        `mapstructure:"dir"`               // PEM 私钥目录,每个文件一个密钥,文件名(不含扩展名)作为 kid,以 UTC 创建时间开头
+	ReloadInterval   time.Duration `mapstructure:"reload_interval"`   // 重新扫描目录的间隔,必须大于 0
+	RotationInterval time.Duration `mapstructure:"rotation_interval"` // 自动轮换间隔, 0 表示不自动轮换
+}
+
+// UserOptions 配置文件中的用户
+type UserOptions struct {
+	Username     string   `mapstructure:"username"`
+	PasswordHash string   `mapstructure:"password_hash"` // bcrypt 摘要,可通过 todo hash-password 生成
+	TenantID     string   `mapstructure:"tenant_id"`
+	Roles        []string `mapstructure:"roles"`
+}
+
+// ClientOptions 配置文件中的客户端
+type ClientOptions struct {
+	ClientID   string   `mapstructure:"client_id"`
+	SecretHash string   `mapstructure:"secret_hash"` // bcrypt 摘要
+	TenantID   string   `mapstructure:"tenant_id"`
+	Roles      []string `mapstructure:"roles"`
+}
+
+func NewOptions(v *viper.Viper) (Options, error) {
+
+	options := Options{
+		Issuer:          "sample",
+		Audience:        "sample",
+		Algorithm:       AlgorithmHS256,
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 30 * 24 * time.Hour,
+		Keys: KeyOptions{
+			Dir:            "./keys",
+			ReloadInterval: time.Minute,
+		},
+	}
+
+	if err := v.UnmarshalKey("auth", &options); err != nil {
+		return Options{}, err
+	}
+
+	// UnmarshalKey 不读取环境变量,密钥单独读取以支持通过 AUTH_SIGNING_KEY 设置
+	if key := v.GetString("auth.signing_key"); key != "" {
+		options.SigningKey = key
+	}
+
+	switch options.Algorithm {
+	case AlgorithmHS256:
+		if options.SigningKey == "" {
+			return Options{}, errors.New("auth.signing_key is required for HS256")
+		}
+	case AlgorithmRS256, AlgorithmES256:
+		if options.Keys.ReloadInterval <= 0 {
+			return Options{}, errors.New("auth.keys.reload_interval must be greater than 0")
+		}
+		if options.Keys.RotationInterval < 0 {
+			return Options{}, errors.New("auth.keys.rotation_interval must not be negative")
+		}
+	default:
+		return Options{}, fmt.Errorf("unsupported auth.algorithm %q", options.Algorithm)
+	}
+
+	return options, nil
+}
+
+// HashSecret 计算密码或客户端密钥的 bcrypt 摘要,用于填写配置文件
+func HashSecret(secret string) (string, error) {
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// KeyRotator 定期重新加载密钥目录,签名密钥超过轮换间隔后生成新密钥,无需重启服务
+type KeyRotator struct {
+	keys    *KeyRing
+	options Options
+	log     *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewKeyRotator(keys *KeyRing, options Options, log *zap.Logger) *KeyRotator {
+	return &KeyRotator{
+		keys:    keys,
+		options: options,
+		log:     log,
+	}
+}
+
+// Start 启动后台轮换,对称签名没有需要轮换的密钥
+func (r *KeyRotator) Start(ctx context.Context) error {
+
+	if !r.keys.Asymmetric() {
+		return nil
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(runCtx)
+
+	return nil
+}
+
+// Stop 停止后台轮换并等待当前轮换完成
+func (r *KeyRotator) Stop(ctx context.Context) error {
+
+	if r.cancel == nil {
+		return nil
+	}
+
+	r.cancel()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *KeyRotator) run(ctx context.Context) {
+
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.options.Keys.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.tick()
+		}
+	}
+}
+
+func (r *KeyRotator) tick() {
+
+	// 无法加载的文件已跳过,其余密钥照常使用,仍需检查是否到期轮换
+	if err := r.keys.Reload(); err != nil {
+		r.log.Error("failed to reload signing keys", zap.Error(err))
+	}
+
+	if !r.keys.RotationDue(time.Now()) {
+		return
+	}
+
+	kid, err := r.keys.Rotate()
+
+	if err != nil {
+		r.log.Error("failed to rotate signing key", zap.Error(err))
+		return
+	}
+
+	r.log.Info("signing key rotated", zap.String("kid", kid))
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"slices"
+
+	"workit-sample/internal/todo/domain/identity"
+)
+
+// ConfigStore 基于配置文件的用户与客户端存储
+type ConfigStore struct {
+	users   map[string]identity.User
+	clients map[string]identity.Client
+}
+
+func NewConfigStore(options Options) *ConfigStore {
+
+	store := &ConfigStore{
+		users:   make(map[string]identity.User, len(options.Users)),
+		clients: make(map[string]identity.Client, len(options.Clients)),
+	}
+
+	for _, user := range options.Users {
+		store.users[user.Username] = identity.User{
+			Username:     user.Username,
+			PasswordHash: user.PasswordHash,
+			TenantID:     user.TenantID,
+			Roles:        user.Roles,
+		}
+	}
+
+	for _, client := range options.Clients {
+		store.clients[client.ClientID] = identity.Client{
+			ID:         client.ClientID,
+			SecretHash: client.SecretHash,
+			TenantID:   client.TenantID,
+			Roles:      client.Roles,
+		}
+	}
+
+	return store
+}
+
+func (s *ConfigStore) FindUser(username string) (*identity.User, error) {
+
+	user, ok := s.users[username]
+	if !ok {
+		return nil, identity.ErrUserNotFound
+	}
+
+	user.Roles = slices.Clone(user.Roles)
+	return &user, nil
+}
+
+func (s *ConfigStore) FindClient(clientID string) (*identity.Client, error) {
+
+	client, ok := s.clients[clientID]
+	if !ok {
+		return nil, identity.ErrClientNotFound
+	}
+
+	client.Roles = slices.Clone(client.Roles)
+	return &client, nil
+}
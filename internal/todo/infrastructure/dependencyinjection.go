@@ -0,0 +1,131 @@
+package infrastructure
+
+import (
+	"fmt"
+
+	"workit-sample/internal/todo/domain/identity"
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+	"workit-sample/internal/todo/infrastructure/auth"
+	"workit-sample/internal/todo/infrastructure/eventbus"
+	"workit-sample/internal/todo/infrastructure/migration"
+	"workit-sample/internal/todo/infrastructure/outbox"
+	"workit-sample/internal/todo/infrastructure/persistence"
+	"workit-sample/internal/todo/infrastructure/reminder"
+	webhookworker "workit-sample/internal/todo/infrastructure/webhook"
+
+	"github.com/xiaohangshuhub/go-workit/pkg/database"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// 存储提供者
+const (
+	ProviderMysql    = "mysql"
+	ProviderPostgres = "postgres"
+	ProviderMemory   = "memory"
+)
+
+// DependencyInjection 根据存储提供者注入数据库与仓储实现,未配置时默认使用 mysql,
+// 并注入领域事件分发器、发件箱投递、Webhook 投递、截止提醒调度、令牌签发与签名密钥轮换
+func DependencyInjection(provider string) []fx.Option {
+
+	return append(storage(provider),
+		fx.Provide(fx.Annotate(eventbus.NewInProcessDispatcher, fx.As(new(todo.EventDispatcher)))),
+		fx.Provide(outbox.NewOptions),
+		fx.Provide(outbox.NewPublisher),
+		fx.Provide(outbox.NewRelay),
+		fx.Invoke(func(lc fx.Lifecycle, relay *outbox.Relay) {
+			lc.Append(fx.StartStopHook(relay.Start, relay.Stop))
+		}),
+		fx.Provide(webhookworker.NewOptions),
+		fx.Provide(webhookworker.NewWorker),
+		fx.Invoke(func(lc fx.Lifecycle, worker *webhookworker.Worker) {
+			lc.Append(fx.StartStopHook(worker.Start, worker.Stop))
+		}),
+		fx.Provide(reminder.NewOptions),
+		fx.Provide(reminder.NewScheduler),
+		fx.Invoke(func(lc fx.Lifecycle, scheduler *reminder.Scheduler) {
+			lc.Append(fx.StartStopHook(scheduler.Start, scheduler.Stop))
+		}),
+		fx.Provide(auth.NewOptions),
+		// 用户与客户端默认来自配置文件,替换为其他实现时只需修改此处
+		fx.Provide(fx.Annotate(auth.NewConfigStore, fx.As(new(identity.UserStore)), fx.As(new(identity.ClientStore)))),
+		fx.Provide(fx.Annotate(auth.NewJwtIssuer, fx.As(new(identity.TokenIssuer)))),
+		// 密钥环在构建应用前创建并通过 fx.Supply 注入,与令牌校验共用同一实例
+		fx.Provide(fx.Annotate(func(keys *auth.KeyRing) *auth.KeyRing { return keys }, fx.As(new(identity.KeySet)))),
+		// API Key 鉴权方案同样在构建应用前创建,依赖注入完成后绑定仓储与用户存储
+		fx.Invoke(func(handler *auth.ApiKeyHandler, keys identity.ApiKeyRepository, users identity.UserStore, clients identity.ClientStore, log *zap.Logger) {
+			handler.Bind(keys, users, clients, log)
+		}),
+		fx.Provide(auth.NewKeyRotator),
+		fx.Invoke(func(lc fx.Lifecycle, rotator *auth.KeyRotator) {
+			lc.Append(fx.StartStopHook(rotator.Start, rotator.Stop))
+		}),
+	)
+}
+
+// MigrationInjection 仅注入数据库与迁移执行器,供 migrate 子命令使用
+func MigrationInjection(provider string) []fx.Option {
+
+	return []fx.Option{
+		databaseModule(provider),
+		fx.Provide(migration.NewMigrator),
+	}
+}
+
+// storage 根据存储提供者注入数据库、仓储、工作单元、发件箱存储、Webhook 仓储、提醒记录、刷新令牌与 API Key 仓储
+func storage(provider string) []fx.Option {
+
+	if provider == ProviderMemory {
+		return []fx.Option{
+			fx.Provide(fx.Annotate(persistence.NewMemoryTodoRepository, fx.As(fx.Self()), fx.As(new(todo.TodoRepository)))),
+			fx.Provide(fx.Annotate(outbox.NewMemoryStore, fx.As(fx.Self()), fx.As(new(outbox.Store)))),
+			fx.Provide(fx.Annotate(persistence.NewMemoryUnitOfWork, fx.As(new(todo.UnitOfWork)))),
+			fx.Provide(fx.Annotate(persistence.NewMemoryDeliveryRepository, fx.As(fx.Self()), fx.As(new(webhook.DeliveryRepository)))),
+			fx.Provide(fx.Annotate(persistence.NewMemorySubscriptionRepository, fx.As(new(webhook.SubscriptionRepository)))),
+			fx.Provide(fx.Annotate(persistence.NewMemoryReminderLog, fx.As(new(todo.ReminderLog)))),
+			fx.Provide(fx.Annotate(persistence.NewMemoryRefreshTokenRepository, fx.As(new(identity.RefreshTokenRepository)))),
+			fx.Provide(fx.Annotate(persistence.NewMemoryApiKeyRepository, fx.As(new(identity.ApiKeyRepository)))),
+		}
+	}
+
+	return []fx.Option{
+		databaseModule(provider),
+		fx.Provide(migration.NewMigrator),
+		fx.Provide(fx.Annotate(persistence.NewGormTodoRepository, fx.As(new(todo.TodoRepository)))),
+		fx.Provide(fx.Annotate(outbox.NewGormStore, fx.As(new(outbox.Store)))),
+		fx.Provide(fx.Annotate(persistence.NewGormUnitOfWork, fx.As(new(todo.UnitOfWork)))),
+		fx.Provide(fx.Annotate(persistence.NewGormDeliveryRepository, fx.As(new(webhook.DeliveryRepository)))),
+		fx.Provide(fx.Annotate(persistence.NewGormSubscriptionRepository, fx.As(new(webhook.SubscriptionRepository)))),
+		fx.Provide(fx.Annotate(persistence.NewGormReminderLog, fx.As(new(todo.ReminderLog)))),
+		fx.Provide(fx.Annotate(persistence.NewGormRefreshTokenRepository, fx.As(new(identity.RefreshTokenRepository)))),
+		fx.Provide(fx.Annotate(persistence.NewGormApiKeyRepository, fx.As(new(identity.ApiKeyRepository)))),
+	}
+}
+
+func databaseModule(provider string) fx.Option {
+
+	switch provider {
+	case ProviderMysql, "":
+		return database.MysqlModule()
+	case ProviderPostgres:
+		return database.PostgresModule()
+	default:
+		panic(fmt.Sprintf("invalid database provider: %s", provider))
+	}
+}
+
+// AutoMigrate 启动时执行未完成的数据库迁移,内存存储无需迁移
+func AutoMigrate(provider string) []fx.Option {
+
+	if provider == ProviderMemory {
+		return nil
+	}
+
+	return []fx.Option{
+		fx.Invoke(func(migrator *migration.Migrator) error {
+			return migrator.Up()
+		}),
+	}
+}
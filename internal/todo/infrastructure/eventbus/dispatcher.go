@@ -0,0 +1,43 @@
+package eventbus
+
+import (
+	"workit-sample/internal/todo/domain/todo"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type DispatcherParams struct {
+	fx.In
+
+	Handlers []todo.EventHandler `group:"todo_event_handlers"` // 见 domain.EventHandlerGroup
+	Log      *zap.Logger
+}
+
+// InProcessDispatcher 进程内领域事件分发器,按注册顺序同步调用所有处理器
+type InProcessDispatcher struct {
+	handlers []todo.EventHandler
+	log      *zap.Logger
+}
+
+func NewInProcessDispatcher(p DispatcherParams) *InProcessDispatcher {
+	return &InProcessDispatcher{
+		handlers: p.Handlers,
+		log:      p.Log,
+	}
+}
+
+// Dispatch 分发事件,事务已提交,处理器失败只记录日志不影响其他处理器
+func (d *InProcessDispatcher) Dispatch(events ...todo.Event) {
+
+	for _, event := range events {
+		for _, handler := range d.handlers {
+			if err := handler.Handle(event); err != nil {
+				d.log.Error("failed to handle domain event",
+					zap.String("event", event.Metadata().EventName),
+					zap.Stringer("todo_id", event.AggregateID()),
+					zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// scripts 按方言存放的迁移脚本,命名格式: 0001_name.up.sql / 0001_name.down.sql
+//
+//go:embed scripts
+var scripts embed.FS
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration 单个版本的迁移脚本
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // up 脚本的 sha256,用于校验已执行脚本是否被篡改
+}
+
+// load 读取指定方言的迁移脚本,按版本号升序返回
+func load(dialect string) ([]Migration, error) {
+
+	dir := path.Join("scripts", dialect)
+
+	entries, err := fs.ReadDir(scripts, dir)
+
+	if err != nil {
+		return nil, fmt.Errorf("unsupported migration dialect %q: %w", dialect, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+
+	for _, entry := range entries {
+
+		if entry.IsDir() {
+			continue
+		}
+
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version: %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(scripts, path.Join(dir, entry.Name()))
+
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = m
+		}
+
+		if m.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names: %s, %s", version, m.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			m.Up = string(content)
+			m.Checksum = checksum(m.Up)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, m := range byVersion {
+
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s is missing up script", m.Version, m.Name)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func checksum(script string) string {
+	sum := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(sum[:])
+}
+
+// splitStatements 按行尾分号拆分脚本,驱动默认不支持一次执行多条语句
+func splitStatements(script string) []string {
+
+	var (
+		statements []string
+		current    strings.Builder
+	)
+
+	for _, line := range strings.Split(script, "\n") {
+
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
@@ -0,0 +1,85 @@
+package migration
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDialectsHaveSameMigrations(t *testing.T) {
+
+	mysql, err := load("mysql")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	postgres, err := load("postgres")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mysql) != len(postgres) {
+		t.Fatalf("mysql has %d migrations, postgres has %d", len(mysql), len(postgres))
+	}
+
+	for i := range mysql {
+
+		if mysql[i].Version != int64(i+1) {
+			t.Errorf("expected version %d, got %d_%s", i+1, mysql[i].Version, mysql[i].Name)
+		}
+
+		if mysql[i].Version != postgres[i].Version || mysql[i].Name != postgres[i].Name {
+			t.Errorf("mysql %d_%s does not match postgres %d_%s", mysql[i].Version, mysql[i].Name, postgres[i].Version, postgres[i].Name)
+		}
+
+		if mysql[i].Down == "" || postgres[i].Down == "" {
+			t.Errorf("migration %d_%s is missing down script", mysql[i].Version, mysql[i].Name)
+		}
+	}
+}
+
+// 基线迁移在已有数据库上不执行任何修改,后续迁移依赖的列不能放在基线中
+func TestBaselineMatchesInitialSchema(t *testing.T) {
+
+	for _, dialect := range []string{"mysql", "postgres"} {
+
+		migrations, err := load(dialect)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(migrations[0].Up, "created_at") {
+			t.Errorf("%s baseline must not create created_at", dialect)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+
+	script := `-- comment
+ALTER TABLE todos ADD COLUMN created_at TIMESTAMPTZ(3);
+
+UPDATE todos
+  SET created_at = CURRENT_TIMESTAMP;
+CREATE INDEX idx ON todos (created_at)`
+
+	statements := splitStatements(script)
+
+	expected := []string{
+		"ALTER TABLE todos ADD COLUMN created_at TIMESTAMPTZ(3);",
+		"UPDATE todos\n  SET created_at = CURRENT_TIMESTAMP;",
+		"CREATE INDEX idx ON todos (created_at)",
+	}
+
+	if len(statements) != len(expected) {
+		t.Fatalf("expected %d statements, got %q", len(expected), statements)
+	}
+
+	for i := range expected {
+		if statements[i] != expected[i] {
+			t.Errorf("statement %d: expected %q, got %q", i, expected[i], statements[i])
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+	ErrUnknownVersion   = errors.New("applied migration not found in scripts")
+	ErrNoDownScript     = errors.New("migration has no down script")
+	ErrLockTimeout      = errors.New("timed out waiting for migration lock")
+)
+
+// lockName 迁移锁名称,多个实例同时启动时只有一个实例执行迁移
+const lockName = "schema_migrations"
+
+// lockTimeout 等待其他实例完成迁移的最长时间
+const lockTimeout = 10 * time.Minute
+
+// schemaMigration 已执行的迁移记录
+type schemaMigration struct {
+	Version   int64     `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"column:name;size:255;not null"`
+	Checksum  string    `gorm:"column:checksum;size:64;not null"`
+	AppliedAt time.Time `gorm:"column:applied_at;not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status 迁移版本状态
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator 版本化迁移执行器
+type Migrator struct {
+	db         *gorm.DB
+	log        *zap.Logger
+	migrations []Migration
+}
+
+// NewMigrator 根据数据库方言加载内嵌的迁移脚本
+func NewMigrator(db *gorm.DB, log *zap.Logger) (*Migrator, error) {
+
+	migrations, err := load(db.Dialector.Name())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		log:        log,
+		migrations: migrations,
+	}, nil
+}
+
+// Up 持有迁移锁,按版本顺序执行所有未执行的迁移
+func (m *Migrator) Up() error {
+	return m.withLock(m.up)
+}
+
+func (m *Migrator) up(db *gorm.DB) error {
+
+	applied, err := m.verify(db)
+
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		// mysql 的 DDL 会隐式提交,事务仅保证 postgres 下的原子性
+		err := db.Transaction(func(tx *gorm.DB) error {
+
+			if err := exec(tx, migration.Up); err != nil {
+				return err
+			}
+
+			return tx.Create(&schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Checksum:  migration.Checksum,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+
+		if err != nil {
+			return fmt.Errorf("apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		m.log.Info("migration applied", zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+	}
+
+	return nil
+}
+
+// Down 持有迁移锁,回滚最近执行的 steps 个迁移
+func (m *Migrator) Down(steps int) error {
+	return m.withLock(func(db *gorm.DB) error {
+		return m.down(db, steps)
+	})
+}
+
+func (m *Migrator) down(db *gorm.DB, steps int) error {
+
+	if _, err := m.verify(db); err != nil {
+		return err
+	}
+
+	var records []schemaMigration
+
+	if err := db.Order("version DESC").Limit(steps).Find(&records).Error; err != nil {
+		return err
+	}
+
+	for _, record := range records {
+
+		migration, _ := m.find(record.Version)
+
+		if migration.Down == "" {
+			return fmt.Errorf("%w: %d_%s", ErrNoDownScript, migration.Version, migration.Name)
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+
+			if err := exec(tx, migration.Down); err != nil {
+				return err
+			}
+
+			return tx.Delete(&schemaMigration{}, "version = ?", record.Version).Error
+		})
+
+		if err != nil {
+			return fmt.Errorf("revert migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		m.log.Info("migration reverted", zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+	}
+
+	return nil
+}
+
+// Status 返回所有迁移的执行状态
+func (m *Migrator) Status() ([]Status, error) {
+
+	applied, err := m.verify(m.db)
+
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+
+	for _, migration := range m.migrations {
+
+		status := Status{Version: migration.Version, Name: migration.Name}
+
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &record.AppliedAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// verify 确保版本表存在,并校验已执行脚本与内嵌脚本一致
+func (m *Migrator) verify(db *gorm.DB) (map[int64]schemaMigration, error) {
+
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var records []schemaMigration
+
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]schemaMigration, len(records))
+
+	for _, record := range records {
+
+		migration, ok := m.find(record.Version)
+
+		if !ok {
+			return nil, fmt.Errorf("%w: %d_%s", ErrUnknownVersion, record.Version, record.Name)
+		}
+
+		if migration.Checksum != record.Checksum {
+			return nil, fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, record.Version, record.Name)
+		}
+
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+// withLock 在同一个连接上持有数据库级的迁移锁执行 fn,锁随连接绑定,fn 必须使用传入的 db
+func (m *Migrator) withLock(fn func(db *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+
+		if err := lock(conn); err != nil {
+			return err
+		}
+
+		defer func() {
+			if err := unlock(conn); err != nil {
+				m.log.Warn("failed to release migration lock", zap.Error(err))
+			}
+		}()
+
+		return fn(conn)
+	})
+}
+
+func lock(conn *gorm.DB) error {
+
+	switch conn.Dialector.Name() {
+	case "mysql":
+		// GET_LOCK 成功返回 1,超时返回 0,出错返回 NULL
+		var acquired *int
+
+		if err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, int(lockTimeout.Seconds())).Scan(&acquired).Error; err != nil {
+			return err
+		}
+
+		if acquired == nil || *acquired != 1 {
+			return ErrLockTimeout
+		}
+
+		return nil
+	case "postgres":
+		// 会话级锁在事务提交后仍然持有,lock_timeout 只作用于加锁所在的事务,不影响随后执行的迁移
+		return conn.Transaction(func(tx *gorm.DB) error {
+
+			if err := tx.Exec(fmt.Sprintf("SET LOCAL lock_timeout = '%dms'", lockTimeout.Milliseconds())).Error; err != nil {
+				return err
+			}
+
+			return tx.Exec("SELECT pg_advisory_lock(hashtext(?))", lockName).Error
+		})
+	default:
+		return nil
+	}
+}
+
+func unlock(conn *gorm.DB) error {
+
+	switch conn.Dialector.Name() {
+	case "mysql":
+		return conn.Exec("SELECT RELEASE_LOCK(?)", lockName).Error
+	case "postgres":
+		return conn.Exec("SELECT pg_advisory_unlock(hashtext(?))", lockName).Error
+	default:
+		return nil
+	}
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+
+	return Migration{}, false
+}
+
+func exec(tx *gorm.DB, script string) error {
+
+	for _, statement := range splitStatements(script) {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
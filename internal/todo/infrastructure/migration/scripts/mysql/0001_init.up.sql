@@ -1,9 +1,8 @@
--- 创建数据库
-CREATE DATABASE IF NOT EXISTS `newb` DEFAULT CHARSET utf8mb4 COLLATE utf8mb4_unicode_ci;
-USE `newb`;
+-- 基线结构,与引入迁移前手工初始化的数据库一致,在已有数据库上不做任何修改。
+-- 不要修改本脚本,结构变更一律通过新的迁移完成
 
 -- 创建 todo 表
-CREATE TABLE `todos` (
+CREATE TABLE IF NOT EXISTS `todos` (
   `id` CHAR(36) NOT NULL PRIMARY KEY,
   `title` VARCHAR(255) NOT NULL,
   `description` TEXT,
@@ -11,7 +10,7 @@ CREATE TABLE `todos` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- 创建 task 表
-CREATE TABLE `tasks` (
+CREATE TABLE IF NOT EXISTS `tasks` (
   `id` CHAR(36) NOT NULL PRIMARY KEY,
   `todo_id` CHAR(36) NOT NULL,
   `title` VARCHAR(255) NOT NULL,
@@ -0,0 +1 @@
+ALTER TABLE `todos` DROP KEY `idx_todos_created_at_id`, DROP COLUMN `created_at`;
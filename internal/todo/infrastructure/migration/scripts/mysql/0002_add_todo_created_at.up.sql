@@ -0,0 +1,8 @@
+-- 创建时间,用于游标分页。已有数据没有创建时间,以迁移时间补齐,同一时间内按 id 排序
+ALTER TABLE `todos` ADD COLUMN `created_at` DATETIME(3) NULL;
+
+UPDATE `todos` SET `created_at` = CURRENT_TIMESTAMP(3) WHERE `created_at` IS NULL;
+
+ALTER TABLE `todos`
+  MODIFY COLUMN `created_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  ADD KEY `idx_todos_created_at_id` (`created_at`, `id`);
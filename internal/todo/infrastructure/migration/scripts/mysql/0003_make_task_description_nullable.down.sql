@@ -0,0 +1,2 @@
+UPDATE `tasks` SET `description` = '' WHERE `description` IS NULL;
+ALTER TABLE `tasks` MODIFY COLUMN `description` TEXT NOT NULL;
@@ -0,0 +1,2 @@
+-- 任务描述可以为空,与待办事项描述一致
+ALTER TABLE `tasks` MODIFY COLUMN `description` TEXT NULL;
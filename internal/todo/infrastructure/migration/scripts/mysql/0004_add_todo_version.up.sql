@@ -0,0 +1,2 @@
+-- 乐观锁版本号,已有数据从 1 开始
+ALTER TABLE `todos` ADD COLUMN `version` BIGINT NOT NULL DEFAULT 1;
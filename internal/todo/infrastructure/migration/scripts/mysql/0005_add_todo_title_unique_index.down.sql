@@ -0,0 +1 @@
+ALTER TABLE `todos` DROP KEY `uk_todos_title`;
@@ -0,0 +1,3 @@
+-- 待办事项标题唯一,按表的排序规则不区分大小写,防止并发创建时绕过应用层检查。
+-- 已有重复标题时迁移失败,需先手工改名
+ALTER TABLE `todos` ADD UNIQUE KEY `uk_todos_title` (`title`);
@@ -0,0 +1,17 @@
+-- 发件箱,与聚合在同一事务中写入
+CREATE TABLE IF NOT EXISTS `outbox` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `event_id` CHAR(36) NOT NULL,
+  `event_name` VARCHAR(100) NOT NULL,
+  `aggregate_id` CHAR(36) NOT NULL,
+  `payload` TEXT NOT NULL,
+  `created_at` DATETIME(3) NOT NULL,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `next_attempt_at` DATETIME(3) NOT NULL,
+  `last_error` TEXT,
+  `published_at` DATETIME(3) NULL,
+  `dead_at` DATETIME(3) NULL,
+  UNIQUE KEY `uk_outbox_event_id` (`event_id`),
+  KEY `idx_outbox_pending` (`published_at`, `dead_at`, `id`),
+  KEY `idx_outbox_aggregate` (`aggregate_id`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
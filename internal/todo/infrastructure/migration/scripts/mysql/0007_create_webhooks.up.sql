@@ -0,0 +1,28 @@
+-- Webhook 订阅
+CREATE TABLE IF NOT EXISTS `webhook_subscriptions` (
+  `id` CHAR(36) NOT NULL PRIMARY KEY,
+  `url` VARCHAR(2048) NOT NULL,
+  `events` TEXT,
+  `secret` VARCHAR(255) NOT NULL,
+  `active` TINYINT(1) NOT NULL DEFAULT 1,
+  `created_at` DATETIME(3) NOT NULL,
+  `updated_at` DATETIME(3) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Webhook 投递记录
+CREATE TABLE IF NOT EXISTS `webhook_deliveries` (
+  `id` CHAR(36) NOT NULL PRIMARY KEY,
+  `subscription_id` CHAR(36) NOT NULL,
+  `event_id` CHAR(36) NOT NULL,
+  `event_name` VARCHAR(100) NOT NULL,
+  `payload` TEXT NOT NULL,
+  `status` VARCHAR(20) NOT NULL,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `next_attempt_at` DATETIME(3) NOT NULL,
+  `last_status_code` INT NULL,
+  `last_error` TEXT,
+  `created_at` DATETIME(3) NOT NULL,
+  `delivered_at` DATETIME(3) NULL,
+  KEY `idx_webhook_deliveries_due` (`status`, `next_attempt_at`),
+  KEY `idx_webhook_deliveries_subscription` (`subscription_id`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `todo_reminders`;
+ALTER TABLE `tasks` DROP KEY `idx_tasks_due_at`, DROP COLUMN `due_at`, DROP COLUMN `due_time_zone`;
+ALTER TABLE `todos` DROP KEY `idx_todos_due_at`, DROP COLUMN `due_at`, DROP COLUMN `due_time_zone`;
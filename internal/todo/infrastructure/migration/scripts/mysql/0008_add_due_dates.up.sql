@@ -0,0 +1,16 @@
+-- 截止时间与所属时区
+ALTER TABLE `todos`
+  ADD COLUMN `due_at` DATETIME(3) NULL,
+  ADD COLUMN `due_time_zone` VARCHAR(64) NOT NULL DEFAULT '',
+  ADD KEY `idx_todos_due_at` (`due_at`);
+
+ALTER TABLE `tasks`
+  ADD COLUMN `due_at` DATETIME(3) NULL,
+  ADD COLUMN `due_time_zone` VARCHAR(64) NOT NULL DEFAULT '',
+  ADD KEY `idx_tasks_due_at` (`due_at`);
+
+-- 已发送的截止提醒,用于多实例与重启后去重
+CREATE TABLE IF NOT EXISTS `todo_reminders` (
+  `reminder_key` VARCHAR(191) NOT NULL PRIMARY KEY,
+  `sent_at` DATETIME(3) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
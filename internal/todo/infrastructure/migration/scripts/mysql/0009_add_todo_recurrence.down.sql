@@ -0,0 +1,11 @@
+ALTER TABLE `todos`
+  DROP KEY `uk_todos_title`,
+  DROP COLUMN `unique_title`,
+  ADD UNIQUE KEY `uk_todos_title` (`title`);
+
+ALTER TABLE `todos`
+  DROP KEY `idx_todos_series_id`,
+  DROP COLUMN `recurrence`,
+  DROP COLUMN `series_id`,
+  DROP COLUMN `occurrence`,
+  DROP COLUMN `next_occurrence_id`;
@@ -0,0 +1,14 @@
+-- 重复规则与重复系列
+ALTER TABLE `todos`
+  ADD COLUMN `recurrence` VARCHAR(255) NOT NULL DEFAULT '',
+  ADD COLUMN `series_id` CHAR(36) NULL,
+  ADD COLUMN `occurrence` INT NOT NULL DEFAULT 0,
+  ADD COLUMN `next_occurrence_id` CHAR(36) NULL,
+  ADD KEY `idx_todos_series_id` (`series_id`, `occurrence`);
+
+-- 重复系列共用标题,标题唯一索引只约束尚未生成下一次重复的待办事项:
+-- 已生成下一次重复的行 unique_title 为 NULL,不参与唯一性比较
+ALTER TABLE `todos`
+  ADD COLUMN `unique_title` VARCHAR(255) AS (IF(`next_occurrence_id` IS NULL, `title`, NULL)) STORED,
+  DROP KEY `uk_todos_title`,
+  ADD UNIQUE KEY `uk_todos_title` (`unique_title`);
@@ -0,0 +1,13 @@
+ALTER TABLE `webhook_subscriptions`
+  DROP KEY `idx_webhook_subscriptions_owner`,
+  DROP COLUMN `tenant_id`,
+  DROP COLUMN `owner_id`;
+
+ALTER TABLE `todos`
+  DROP KEY `uk_todos_owner_title`,
+  ADD UNIQUE KEY `uk_todos_title` (`unique_title`);
+
+ALTER TABLE `todos`
+  DROP KEY `idx_todos_owner_created_at_id`,
+  DROP COLUMN `tenant_id`,
+  DROP COLUMN `owner_id`;
@@ -0,0 +1,16 @@
+-- 待办事项与 Webhook 订阅的所属租户与用户。
+-- 已有数据归入默认租户且没有所有者,任何用户都无法访问,需按实际情况补充 owner_id
+ALTER TABLE `todos`
+  ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default',
+  ADD COLUMN `owner_id` VARCHAR(255) NOT NULL DEFAULT '',
+  ADD KEY `idx_todos_owner_created_at_id` (`tenant_id`, `owner_id`, `created_at`, `id`);
+
+-- 待办事项标题改为在同一用户内唯一
+ALTER TABLE `todos`
+  DROP KEY `uk_todos_title`,
+  ADD UNIQUE KEY `uk_todos_owner_title` (`tenant_id`, `owner_id`, `unique_title`);
+
+ALTER TABLE `webhook_subscriptions`
+  ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default',
+  ADD COLUMN `owner_id` VARCHAR(255) NOT NULL DEFAULT '',
+  ADD KEY `idx_webhook_subscriptions_owner` (`tenant_id`, `owner_id`);
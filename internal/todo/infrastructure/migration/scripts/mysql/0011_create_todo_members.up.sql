@@ -0,0 +1,10 @@
+-- 待办事项的共享成员,创建者不在其中
+CREATE TABLE IF NOT EXISTS `todo_members` (
+  `todo_id` CHAR(36) NOT NULL,
+  `user_id` VARCHAR(255) NOT NULL,
+  `role` VARCHAR(16) NOT NULL,
+  `created_at` DATETIME(3) NOT NULL,
+  PRIMARY KEY (`todo_id`, `user_id`),
+  KEY `idx_todo_members_user_id` (`user_id`),
+  CONSTRAINT `fk_todo_members_todo` FOREIGN KEY (`todo_id`) REFERENCES `todos`(`id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,14 @@
+-- 刷新令牌,只保存令牌的 SHA-256 摘要
+CREATE TABLE IF NOT EXISTS `refresh_tokens` (
+  `id` CHAR(36) NOT NULL,
+  `family_id` CHAR(36) NOT NULL,
+  `token_hash` CHAR(64) NOT NULL,
+  `subject` VARCHAR(255) NOT NULL,
+  `expires_at` DATETIME(3) NOT NULL,
+  `created_at` DATETIME(3) NOT NULL,
+  `rotated_at` DATETIME(3) NULL,
+  `revoked_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_refresh_tokens_token_hash` (`token_hash`),
+  KEY `idx_refresh_tokens_family_id` (`family_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
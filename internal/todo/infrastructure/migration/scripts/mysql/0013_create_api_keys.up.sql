@@ -0,0 +1,17 @@
+-- 个人 API Key,只保存明文的 SHA-256 摘要
+CREATE TABLE IF NOT EXISTS `api_keys` (
+  `id` CHAR(36) NOT NULL,
+  `tenant_id` VARCHAR(64) NOT NULL,
+  `owner_id` VARCHAR(255) NOT NULL,
+  `name` VARCHAR(100) NOT NULL,
+  `prefix` VARCHAR(16) NOT NULL,
+  `key_hash` CHAR(64) NOT NULL,
+  `scope` VARCHAR(16) NOT NULL,
+  `expires_at` DATETIME(3) NULL,
+  `last_used_at` DATETIME(3) NULL,
+  `revoked_at` DATETIME(3) NULL,
+  `created_at` DATETIME(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_api_keys_key_hash` (`key_hash`),
+  KEY `idx_api_keys_owner` (`tenant_id`, `owner_id`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
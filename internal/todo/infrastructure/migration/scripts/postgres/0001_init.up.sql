@@ -0,0 +1,22 @@
+-- 基线结构,与引入迁移前手工初始化的数据库一致,在已有数据库上不做任何修改。
+-- 不要修改本脚本,结构变更一律通过新的迁移完成
+
+-- 创建 todo 表
+CREATE TABLE IF NOT EXISTS todos (
+  id UUID NOT NULL PRIMARY KEY,
+  title VARCHAR(255) NOT NULL,
+  description TEXT,
+  completed BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- 创建 task 表
+CREATE TABLE IF NOT EXISTS tasks (
+  id UUID NOT NULL PRIMARY KEY,
+  todo_id UUID NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  description TEXT NOT NULL,
+  completed BOOLEAN NOT NULL DEFAULT FALSE,
+  CONSTRAINT fk_tasks_todo FOREIGN KEY (todo_id) REFERENCES todos (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_todo_id ON tasks (todo_id);
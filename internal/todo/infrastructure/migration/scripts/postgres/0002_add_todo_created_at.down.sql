@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_todos_created_at_id;
+ALTER TABLE todos DROP COLUMN created_at;
@@ -0,0 +1,10 @@
+-- 创建时间,用于游标分页。已有数据没有创建时间,以迁移时间补齐,同一时间内按 id 排序
+ALTER TABLE todos ADD COLUMN created_at TIMESTAMPTZ(3);
+
+UPDATE todos SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+
+ALTER TABLE todos
+  ALTER COLUMN created_at SET NOT NULL,
+  ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_todos_created_at_id ON todos (created_at, id);
@@ -0,0 +1,2 @@
+UPDATE tasks SET description = '' WHERE description IS NULL;
+ALTER TABLE tasks ALTER COLUMN description SET NOT NULL;
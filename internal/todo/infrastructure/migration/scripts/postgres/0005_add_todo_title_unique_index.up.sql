@@ -0,0 +1,3 @@
+-- 待办事项标题唯一,不区分大小写,防止并发创建时绕过应用层检查。
+-- 已有重复标题时迁移失败,需先手工改名
+CREATE UNIQUE INDEX IF NOT EXISTS uk_todos_title ON todos (LOWER(title));
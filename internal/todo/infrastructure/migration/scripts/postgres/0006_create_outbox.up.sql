@@ -0,0 +1,18 @@
+-- 发件箱,与聚合在同一事务中写入
+CREATE TABLE IF NOT EXISTS outbox (
+  id BIGSERIAL NOT NULL PRIMARY KEY,
+  event_id UUID NOT NULL,
+  event_name VARCHAR(100) NOT NULL,
+  aggregate_id UUID NOT NULL,
+  payload TEXT NOT NULL,
+  created_at TIMESTAMPTZ(3) NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ(3) NOT NULL,
+  last_error TEXT,
+  published_at TIMESTAMPTZ(3),
+  dead_at TIMESTAMPTZ(3),
+  CONSTRAINT uk_outbox_event_id UNIQUE (event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (id) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_aggregate ON outbox (aggregate_id, id) WHERE published_at IS NULL AND dead_at IS NULL;
@@ -0,0 +1,29 @@
+-- Webhook 订阅
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+  id UUID NOT NULL PRIMARY KEY,
+  url VARCHAR(2048) NOT NULL,
+  events TEXT,
+  secret VARCHAR(255) NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ(3) NOT NULL,
+  updated_at TIMESTAMPTZ(3) NOT NULL
+);
+
+-- Webhook 投递记录
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id UUID NOT NULL PRIMARY KEY,
+  subscription_id UUID NOT NULL,
+  event_id UUID NOT NULL,
+  event_name VARCHAR(100) NOT NULL,
+  payload TEXT NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ(3) NOT NULL,
+  last_status_code INT,
+  last_error TEXT,
+  created_at TIMESTAMPTZ(3) NOT NULL,
+  delivered_at TIMESTAMPTZ(3)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries (subscription_id, created_at);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS todo_reminders;
+DROP INDEX IF EXISTS idx_tasks_due_at;
+DROP INDEX IF EXISTS idx_todos_due_at;
+ALTER TABLE tasks DROP COLUMN due_at, DROP COLUMN due_time_zone;
+ALTER TABLE todos DROP COLUMN due_at, DROP COLUMN due_time_zone;
@@ -0,0 +1,17 @@
+-- 截止时间与所属时区
+ALTER TABLE todos
+  ADD COLUMN due_at TIMESTAMPTZ(3),
+  ADD COLUMN due_time_zone VARCHAR(64) NOT NULL DEFAULT '';
+
+ALTER TABLE tasks
+  ADD COLUMN due_at TIMESTAMPTZ(3),
+  ADD COLUMN due_time_zone VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_todos_due_at ON todos (due_at) WHERE completed = FALSE;
+CREATE INDEX IF NOT EXISTS idx_tasks_due_at ON tasks (due_at) WHERE completed = FALSE;
+
+-- 已发送的截止提醒,用于多实例与重启后去重
+CREATE TABLE IF NOT EXISTS todo_reminders (
+  reminder_key VARCHAR(191) NOT NULL PRIMARY KEY,
+  sent_at TIMESTAMPTZ(3) NOT NULL
+);
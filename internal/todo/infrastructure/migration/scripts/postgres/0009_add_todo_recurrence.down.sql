@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS uk_todos_title;
+CREATE UNIQUE INDEX IF NOT EXISTS uk_todos_title ON todos (LOWER(title));
+
+DROP INDEX IF EXISTS idx_todos_series_id;
+ALTER TABLE todos
+  DROP COLUMN recurrence,
+  DROP COLUMN series_id,
+  DROP COLUMN occurrence,
+  DROP COLUMN next_occurrence_id;
@@ -0,0 +1,12 @@
+-- 重复规则与重复系列
+ALTER TABLE todos
+  ADD COLUMN recurrence VARCHAR(255) NOT NULL DEFAULT '',
+  ADD COLUMN series_id UUID,
+  ADD COLUMN occurrence INT NOT NULL DEFAULT 0,
+  ADD COLUMN next_occurrence_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_todos_series_id ON todos (series_id, occurrence);
+
+-- 重复系列共用标题,标题唯一索引只约束尚未生成下一次重复的待办事项
+DROP INDEX IF EXISTS uk_todos_title;
+CREATE UNIQUE INDEX IF NOT EXISTS uk_todos_title ON todos (LOWER(title)) WHERE next_occurrence_id IS NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS uk_todos_owner_title;
+CREATE UNIQUE INDEX IF NOT EXISTS uk_todos_title ON todos (LOWER(title)) WHERE next_occurrence_id IS NULL;
+
+DROP INDEX IF EXISTS idx_webhook_subscriptions_owner;
+DROP INDEX IF EXISTS idx_todos_owner_created_at_id;
+ALTER TABLE webhook_subscriptions DROP COLUMN tenant_id, DROP COLUMN owner_id;
+ALTER TABLE todos DROP COLUMN tenant_id, DROP COLUMN owner_id;
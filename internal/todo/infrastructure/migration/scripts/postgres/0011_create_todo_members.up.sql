@@ -0,0 +1,11 @@
+-- 待办事项的共享成员,创建者不在其中
+CREATE TABLE IF NOT EXISTS todo_members (
+  todo_id UUID NOT NULL,
+  user_id VARCHAR(255) NOT NULL,
+  role VARCHAR(16) NOT NULL,
+  created_at TIMESTAMPTZ(3) NOT NULL,
+  PRIMARY KEY (todo_id, user_id),
+  CONSTRAINT fk_todo_members_todo FOREIGN KEY (todo_id) REFERENCES todos (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_members_user_id ON todo_members (user_id);
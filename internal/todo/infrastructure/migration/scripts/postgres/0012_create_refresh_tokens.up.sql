@@ -0,0 +1,15 @@
+-- 刷新令牌,只保存令牌的 SHA-256 摘要
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+  id UUID NOT NULL,
+  family_id UUID NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  expires_at TIMESTAMPTZ(3) NOT NULL,
+  created_at TIMESTAMPTZ(3) NOT NULL,
+  rotated_at TIMESTAMPTZ(3) NULL,
+  revoked_at TIMESTAMPTZ(3) NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT uk_refresh_tokens_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
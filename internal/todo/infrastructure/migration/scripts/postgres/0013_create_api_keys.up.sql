@@ -0,0 +1,18 @@
+-- 个人 API Key,只保存明文的 SHA-256 摘要
+CREATE TABLE IF NOT EXISTS api_keys (
+  id UUID NOT NULL,
+  tenant_id VARCHAR(64) NOT NULL,
+  owner_id VARCHAR(255) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  prefix VARCHAR(16) NOT NULL,
+  key_hash CHAR(64) NOT NULL,
+  scope VARCHAR(16) NOT NULL,
+  expires_at TIMESTAMPTZ(3) NULL,
+  last_used_at TIMESTAMPTZ(3) NULL,
+  revoked_at TIMESTAMPTZ(3) NULL,
+  created_at TIMESTAMPTZ(3) NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT uk_api_keys_key_hash UNIQUE (key_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_owner ON api_keys (tenant_id, owner_id, created_at);
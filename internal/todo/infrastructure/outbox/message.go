@@ -0,0 +1,57 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+)
+
+// Message 发件箱消息,与聚合在同一事务中写入,由 Relay 异步投递
+type Message struct {
+	ID            int64      `json:"id" gorm:"column:id;primaryKey;autoIncrement"` // 自增序号,同一聚合按序号顺序投递
+	EventID       uuid.UUID  `json:"eventId" gorm:"column:event_id"`
+	EventName     string     `json:"eventName" gorm:"column:event_name"`
+	AggregateID   uuid.UUID  `json:"aggregateId" gorm:"column:aggregate_id"`
+	Payload       string     `json:"payload" gorm:"column:payload"` // 事件的 JSON
+	CreatedAt     time.Time  `json:"createdAt" gorm:"column:created_at"`
+	Attempts      int        `json:"-" gorm:"column:attempts"`        // 已投递次数
+	NextAttemptAt time.Time  `json:"-" gorm:"column:next_attempt_at"` // 下次可投递时间
+	LastError     *string    `json:"-" gorm:"column:last_error"`
+	PublishedAt   *time.Time `json:"-" gorm:"column:published_at"`
+	DeadAt        *time.Time `json:"-" gorm:"column:dead_at"` // 超过最大重试次数后放弃投递的时间
+}
+
+func (Message) TableName() string {
+	return "outbox"
+}
+
+// NewMessages 将领域事件转换为发件箱消息
+func NewMessages(events []todo.Event) ([]Message, error) {
+
+	messages := make([]Message, 0, len(events))
+
+	for _, event := range events {
+
+		payload, err := json.Marshal(event)
+
+		if err != nil {
+			return nil, err
+		}
+
+		meta := event.Metadata()
+
+		messages = append(messages, Message{
+			EventID:       meta.EventId,
+			EventName:     meta.EventName,
+			AggregateID:   event.AggregateID(),
+			Payload:       string(payload),
+			CreatedAt:     meta.Created,
+			NextAttemptAt: meta.Created,
+		})
+	}
+
+	return messages, nil
+}
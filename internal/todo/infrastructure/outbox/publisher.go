@@ -0,0 +1,148 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 发布者类型
+const (
+	PublisherLog    = "log"    // 仅记录日志
+	PublisherFile   = "file"   // 追加写入本地 JSON Lines 文件
+	PublisherMemory = "memory" // 保存在内存中,用于测试
+)
+
+// Publisher 消息发布者,返回错误时消息按退避策略重试
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+}
+
+// NewPublisher 根据 outbox.publisher 配置创建发布者,未配置时仅记录日志
+func NewPublisher(options Options, log *zap.Logger) (Publisher, error) {
+
+	switch options.Publisher {
+	case PublisherLog, "":
+		return NewLogPublisher(log), nil
+	case PublisherFile:
+		return NewFilePublisher(options.File), nil
+	case PublisherMemory:
+		return NewMemoryPublisher(), nil
+	default:
+		return nil, fmt.Errorf("invalid outbox publisher: %s", options.Publisher)
+	}
+}
+
+// envelope 对外发布的消息格式
+type envelope struct {
+	ID          int64           `json:"id"`
+	EventID     uuid.UUID       `json:"eventId"`
+	EventName   string          `json:"eventName"`
+	AggregateID uuid.UUID       `json:"aggregateId"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+func newEnvelope(message Message) envelope {
+	return envelope{
+		ID:          message.ID,
+		EventID:     message.EventID,
+		EventName:   message.EventName,
+		AggregateID: message.AggregateID,
+		CreatedAt:   message.CreatedAt,
+		Payload:     json.RawMessage(message.Payload),
+	}
+}
+
+// LogPublisher 将消息写入日志
+type LogPublisher struct {
+	log *zap.Logger
+}
+
+func NewLogPublisher(log *zap.Logger) *LogPublisher {
+	return &LogPublisher{
+		log: log,
+	}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, message Message) error {
+	p.log.Info("outbox message published",
+		zap.Int64("id", message.ID),
+		zap.String("event", message.EventName),
+		zap.Stringer("aggregate_id", message.AggregateID),
+		zap.String("payload", message.Payload))
+	return nil
+}
+
+// FilePublisher 将消息逐行追加到本地文件
+type FilePublisher struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFilePublisher(path string) *FilePublisher {
+	return &FilePublisher{
+		path: path,
+	}
+}
+
+func (p *FilePublisher) Publish(ctx context.Context, message Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	line, err := json.Marshal(newEnvelope(message))
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(p.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+
+	return err
+}
+
+// MemoryPublisher 将消息保存在内存中,用于测试
+type MemoryPublisher struct {
+	messages []Message
+	mu       sync.Mutex
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, message Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+// Messages 返回已发布的消息
+func (p *MemoryPublisher) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return slices.Clone(p.messages)
+}
@@ -0,0 +1,61 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFilePublisherAppendsEnvelopes(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "logs", "outbox.jsonl")
+	publisher := NewFilePublisher(path)
+
+	aggregateID := uuid.New()
+
+	for i, name := range []string{"todo.created", "todo.updated"} {
+
+		message := newMessage(aggregateID, name)
+		message.ID = int64(i + 1)
+		message.Payload = `{"title":"Buy milk"}`
+
+		if err := publisher.Publish(context.Background(), message); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	var lines []envelope
+
+	for scanner := bufio.NewScanner(file); scanner.Scan(); {
+
+		var line envelope
+
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+
+		lines = append(lines, line)
+	}
+
+	if len(lines) != 2 || lines[0].EventName != "todo.created" || lines[1].ID != 2 || lines[1].AggregateID != aggregateID {
+		t.Fatalf("unexpected envelopes %+v", lines)
+	}
+
+	// 事件内容原样嵌入,而不是转义后的字符串
+	if string(lines[0].Payload) != `{"title":"Buy milk"}` {
+		t.Fatalf("unexpected payload %s", lines[0].Payload)
+	}
+}
@@ -0,0 +1,198 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Options 发件箱配置,对应 application.yaml 中的 outbox 节点
+type Options struct {
+	Publisher   string        `mapstructure:"publisher"`    // 发布者类型: log, file, memory
+	File        string        `mapstructure:"file"`         // file 发布者的输出文件
+	Interval    time.Duration `mapstructure:"interval"`     // 轮询间隔
+	BatchSize   int           `mapstructure:"batch_size"`   // 每次轮询处理的消息数
+	MaxAttempts int           `mapstructure:"max_attempts"` // 最大投递次数,超过后放弃
+	BaseBackoff time.Duration `mapstructure:"base_backoff"` // 首次重试间隔,之后按指数增长
+	MaxBackoff  time.Duration `mapstructure:"max_backoff"`  // 重试间隔上限
+	Lease       time.Duration `mapstructure:"lease"`        // 领取消息的租约,应大于投递一批消息的耗时
+}
+
+func NewOptions(v *viper.Viper) (Options, error) {
+
+	options := Options{
+		Publisher:   PublisherLog,
+		File:        "./logs/outbox.jsonl",
+		Interval:    time.Second,
+		BatchSize:   100,
+		MaxAttempts: 10,
+		BaseBackoff: time.Second,
+		MaxBackoff:  5 * time.Minute,
+		Lease:       time.Minute,
+	}
+
+	if err := v.UnmarshalKey("outbox", &options); err != nil {
+		return Options{}, err
+	}
+
+	return options, nil
+}
+
+// Relay 轮询发件箱并投递消息。同一聚合的消息按序号顺序投递,
+// 前一条未成功前不会投递后续消息;多个实例可同时运行,投递语义为至少一次
+type Relay struct {
+	store     Store
+	publisher Publisher
+	options   Options
+	log       *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewRelay(store Store, publisher Publisher, options Options, log *zap.Logger) *Relay {
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		options:   options,
+		log:       log,
+	}
+}
+
+// Start 启动后台投递
+func (r *Relay) Start(ctx context.Context) error {
+
+	runCtx, cancel := context.WithCancel(context.Background())
+
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(runCtx)
+
+	return nil
+}
+
+// Stop 停止后台投递并等待当前批次完成
+func (r *Relay) Stop(ctx context.Context) error {
+
+	r.cancel()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Relay) run(ctx context.Context) {
+
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RelayOnce(ctx); err != nil {
+			r.log.Error("failed to relay outbox messages", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce 投递到期的消息。每个聚合每批只领取一条,
+// 有消息投递成功时继续领取,直到没有可投递的消息
+func (r *Relay) RelayOnce(ctx context.Context) error {
+
+	for ctx.Err() == nil {
+
+		published, err := r.relayBatch(ctx)
+
+		if err != nil || published == 0 {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+
+	claimed := time.Now()
+	messages, err := r.store.Claim(r.options.BatchSize, r.options.Lease)
+
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+
+	for _, message := range messages {
+
+		// 租约到期后剩余消息可能已被其他实例领取,留给下次领取
+		if ctx.Err() != nil || time.Since(claimed) >= r.options.Lease {
+			return published, nil
+		}
+
+		if err := r.publisher.Publish(ctx, message); err != nil {
+			r.fail(message, err)
+			continue
+		}
+
+		if err := r.store.MarkPublished(message.ID, time.Now()); err != nil {
+			return published, err
+		}
+
+		published++
+	}
+
+	return published, nil
+}
+
+// fail 记录失败并按指数退避安排下次投递,超过最大次数后放弃
+func (r *Relay) fail(message Message, err error) {
+
+	now := time.Now()
+	reason := err.Error()
+
+	message.Attempts++
+	message.LastError = &reason
+	message.NextAttemptAt = now.Add(r.backoff(message.Attempts))
+
+	if message.Attempts >= r.options.MaxAttempts {
+		message.DeadAt = &now
+		r.log.Error("outbox message dead",
+			zap.Int64("id", message.ID),
+			zap.String("event", message.EventName),
+			zap.Int("attempts", message.Attempts),
+			zap.Error(err))
+	} else {
+		r.log.Warn("failed to publish outbox message",
+			zap.Int64("id", message.ID),
+			zap.String("event", message.EventName),
+			zap.Int("attempts", message.Attempts),
+			zap.Error(err))
+	}
+
+	if err := r.store.MarkFailed(message); err != nil {
+		r.log.Error("failed to update outbox message", zap.Int64("id", message.ID), zap.Error(err))
+	}
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+
+	delay := r.options.BaseBackoff
+
+	for i := 1; i < attempts && delay < r.options.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, r.options.MaxBackoff)
+}
@@ -0,0 +1,294 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// failingPublisher 对指定聚合的消息投递失败,其余消息交给 MemoryPublisher
+type failingPublisher struct {
+	*MemoryPublisher
+	failing map[uuid.UUID]bool
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, message Message) error {
+
+	if p.failing[message.AggregateID] {
+		return errors.New("broker unavailable")
+	}
+
+	return p.MemoryPublisher.Publish(ctx, message)
+}
+
+func newRelay(store Store, publisher Publisher) *Relay {
+	return NewRelay(store, publisher, Options{
+		BatchSize:   100,
+		MaxAttempts: 3,
+		BaseBackoff: time.Minute,
+		MaxBackoff:  5 * time.Minute,
+		Lease:       time.Minute,
+	}, zap.NewNop())
+}
+
+func newMessage(aggregateID uuid.UUID, name string) Message {
+	now := time.Now()
+	return Message{EventID: uuid.New(), EventName: name, AggregateID: aggregateID, CreatedAt: now, NextAttemptAt: now}
+}
+
+// due 让所有等待重试的消息立即到期
+func (s *MemoryStore) due() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.messages {
+		s.messages[i].NextAttemptAt = time.Now()
+	}
+}
+
+// pending 返回尚未投递且未放弃的消息
+func (s *MemoryStore) pending() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.DeleteFunc(slices.Clone(s.messages), func(m Message) bool {
+		return m.DeadAt != nil
+	})
+}
+
+func eventNames(messages []Message) []string {
+
+	names := make([]string, len(messages))
+
+	for i, message := range messages {
+		names[i] = message.EventName
+	}
+
+	return names
+}
+
+func TestRelayKeepsPerAggregateOrder(t *testing.T) {
+
+	a, b := uuid.New(), uuid.New()
+
+	store := NewMemoryStore()
+	store.Append(newMessage(a, "a1"), newMessage(b, "b1"), newMessage(a, "a2"), newMessage(b, "b2"))
+
+	publisher := &failingPublisher{MemoryPublisher: NewMemoryPublisher(), failing: map[uuid.UUID]bool{a: true}}
+	relay := newRelay(store, publisher)
+
+	if err := relay.RelayOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// a1 失败后 a2 不能越过 a1 投递,其他聚合不受影响
+	if got := eventNames(publisher.Messages()); len(got) != 2 || got[0] != "b1" || got[1] != "b2" {
+		t.Fatalf("expected [b1 b2], got %v", got)
+	}
+
+	pending := store.pending()
+
+	if len(pending) != 2 || pending[0].EventName != "a1" || pending[0].Attempts != 1 || pending[1].Attempts != 0 {
+		t.Fatalf("expected a1 retried once and a2 untouched, got %+v", pending)
+	}
+
+	// 恢复后按原顺序投递
+	delete(publisher.failing, a)
+	store.due()
+
+	if err := relay.RelayOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := eventNames(publisher.Messages()); len(got) != 4 || got[2] != "a1" || got[3] != "a2" {
+		t.Fatalf("expected a1 before a2, got %v", got)
+	}
+}
+
+func TestRelayWaitsForBackoff(t *testing.T) {
+
+	a := uuid.New()
+
+	store := NewMemoryStore()
+	store.Append(newMessage(a, "a1"))
+
+	publisher := &failingPublisher{MemoryPublisher: NewMemoryPublisher(), failing: map[uuid.UUID]bool{a: true}}
+	relay := newRelay(store, publisher)
+
+	before := time.Now()
+
+	if err := relay.RelayOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	pending := store.pending()
+
+	if next := pending[0].NextAttemptAt; next.Before(before.Add(time.Minute)) {
+		t.Fatalf("expected next attempt after base backoff, got %s", next.Sub(before))
+	}
+
+	if pending[0].LastError == nil || *pending[0].LastError != "broker unavailable" {
+		t.Fatalf("expected last error to be recorded, got %v", pending[0].LastError)
+	}
+
+	// 未到期的消息不投递
+	delete(publisher.failing, a)
+
+	if err := relay.RelayOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := publisher.Messages(); len(got) != 0 {
+		t.Fatalf("expected no delivery before backoff elapsed, got %v", eventNames(got))
+	}
+}
+
+func TestRelayBackoffGrowsExponentially(t *testing.T) {
+
+	relay := newRelay(NewMemoryStore(), NewMemoryPublisher())
+
+	expected := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+
+	for i, want := range expected {
+		if got := relay.backoff(i + 1); got != want {
+			t.Errorf("attempt %d: expected %s, got %s", i+1, want, got)
+		}
+	}
+}
+
+func TestRelayGivesUpAfterMaxAttempts(t *testing.T) {
+
+	a := uuid.New()
+
+	store := NewMemoryStore()
+	store.Append(newMessage(a, "a1"))
+
+	publisher := &failingPublisher{MemoryPublisher: NewMemoryPublisher(), failing: map[uuid.UUID]bool{a: true}}
+	relay := newRelay(store, publisher)
+
+	for range relay.options.MaxAttempts {
+		store.due()
+
+		if err := relay.RelayOnce(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if pending := store.pending(); len(pending) != 0 {
+		t.Fatalf("expected dead message to leave the queue, got %+v", pending)
+	}
+
+	if dead := store.messages[0]; dead.DeadAt == nil || dead.Attempts != relay.options.MaxAttempts {
+		t.Fatalf("expected message dead after %d attempts, got %+v", relay.options.MaxAttempts, dead)
+	}
+}
+
+// 一批等待重试的消息不会挡住之后写入的其他聚合的消息
+func TestBackingOffMessagesDoNotStallNewerOnes(t *testing.T) {
+
+	failing := map[uuid.UUID]bool{}
+	store := NewMemoryStore()
+
+	for i := range 3 {
+		aggregateID := uuid.New()
+		failing[aggregateID] = true
+		store.Append(newMessage(aggregateID, fmt.Sprintf("failing%d", i)))
+	}
+
+	publisher := &failingPublisher{MemoryPublisher: NewMemoryPublisher(), failing: failing}
+	relay := newRelay(store, publisher)
+	relay.options.BatchSize = 2
+
+	if err := relay.RelayOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	store.Append(newMessage(uuid.New(), "fresh"))
+
+	if err := relay.RelayOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := eventNames(publisher.Messages()); len(got) != 1 || got[0] != "fresh" {
+		t.Fatalf("expected [fresh], got %v", got)
+	}
+}
+
+// 多个实例共享存储时每条消息只投递一次,同一聚合仍按顺序投递
+func TestConcurrentRelaysPublishEachMessageOnce(t *testing.T) {
+
+	aggregates := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	store := NewMemoryStore()
+
+	for i := range 30 {
+		aggregateID := aggregates[i%len(aggregates)]
+		store.Append(newMessage(aggregateID, fmt.Sprintf("%s/%02d", aggregateID, i)))
+	}
+
+	publisher := NewMemoryPublisher()
+
+	var wg sync.WaitGroup
+
+	for range 4 {
+		relay := newRelay(store, publisher)
+		relay.options.BatchSize = 2
+
+		wg.Go(func() {
+			if err := relay.RelayOnce(context.Background()); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	got := eventNames(publisher.Messages())
+
+	if len(got) != 30 {
+		t.Fatalf("expected 30 messages published once, got %d: %v", len(got), got)
+	}
+
+	last := map[string]string{}
+
+	for _, name := range got {
+
+		aggregateID, _, _ := strings.Cut(name, "/")
+
+		if name <= last[aggregateID] {
+			t.Fatalf("expected %s after %s", name, last[aggregateID])
+		}
+
+		last[aggregateID] = name
+	}
+}
+
+// 领取后未写回结果的消息在租约到期前不会被再次领取
+func TestClaimedMessageIsLeased(t *testing.T) {
+
+	store := NewMemoryStore()
+	store.Append(newMessage(uuid.New(), "a1"))
+
+	if claimed, _ := store.Claim(10, time.Minute); len(claimed) != 1 {
+		t.Fatalf("expected one claimed message, got %d", len(claimed))
+	}
+
+	if claimed, _ := store.Claim(10, time.Minute); len(claimed) != 0 {
+		t.Fatalf("expected leased message to be skipped, got %v", eventNames(claimed))
+	}
+
+	// 租约到期,视为领取的实例已退出
+	store.due()
+
+	if claimed, _ := store.Claim(10, time.Minute); len(claimed) != 1 {
+		t.Fatalf("expected message to be claimed again after the lease, got %d", len(claimed))
+	}
+}
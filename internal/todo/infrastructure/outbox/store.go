@@ -0,0 +1,171 @@
+package outbox
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Store 发件箱存储
+type Store interface {
+	// Claim 按序号升序领取已到期的消息,每个聚合只领取最早一条未完成的消息。
+	// 领取时将下次投递时间推迟 lease 作为租约,租约内其他实例不会重复领取,
+	// 投递结果写回时覆盖;实例退出后租约到期即可被重新领取
+	Claim(limit int, lease time.Duration) ([]Message, error)
+	// MarkPublished 标记消息已投递
+	MarkPublished(id int64, at time.Time) error
+	// MarkFailed 保存投递失败后的重试状态
+	MarkFailed(message Message) error
+}
+
+// GormStore 基于数据库的发件箱存储
+type GormStore struct {
+	db *gorm.DB
+}
+
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{
+		db: db,
+	}
+}
+
+// Append 在调用方的事务中写入消息
+func Append(tx *gorm.DB, messages []Message) error {
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return tx.Create(&messages).Error
+}
+
+func (s *GormStore) Claim(limit int, lease time.Duration) ([]Message, error) {
+
+	var messages []Message
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+
+		now := time.Now()
+
+		// 同一聚合存在更早的未完成消息时不领取,前一条投递成功前后续消息对所有实例不可见
+		earlier := tx.Table("outbox AS earlier").
+			Select("1").
+			Where("earlier.aggregate_id = outbox.aggregate_id AND earlier.id < outbox.id").
+			Where("earlier.published_at IS NULL AND earlier.dead_at IS NULL")
+
+		// SKIP LOCKED 跳过其他实例正在领取的行
+		err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+			Where("published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= ?", now).
+			Where("NOT EXISTS (?)", earlier).
+			Order("id ASC").
+			Limit(limit).
+			Find(&messages).Error
+
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]int64, len(messages))
+
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+
+		return tx.Model(&Message{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+
+	return messages, err
+}
+
+func (s *GormStore) MarkPublished(id int64, at time.Time) error {
+	return s.db.Model(&Message{}).Where("id = ?", id).Update("published_at", at).Error
+}
+
+func (s *GormStore) MarkFailed(message Message) error {
+	return s.db.Model(&Message{}).Where("id = ?", message.ID).Updates(map[string]any{
+		"attempts":        message.Attempts,
+		"next_attempt_at": message.NextAttemptAt,
+		"last_error":      message.LastError,
+		"dead_at":         message.DeadAt,
+	}).Error
+}
+
+// MemoryStore 基于内存的发件箱存储,投递成功的消息直接移除
+type MemoryStore struct {
+	messages []Message
+	nextID   int64
+	mu       sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Append(messages ...Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, message := range messages {
+		s.nextID++
+		message.ID = s.nextID
+		s.messages = append(s.messages, message)
+	}
+}
+
+func (s *MemoryStore) Claim(limit int, lease time.Duration) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	seen := make(map[uuid.UUID]bool)
+
+	var messages []Message
+
+	for i := range s.messages {
+
+		message := &s.messages[i]
+
+		if message.DeadAt != nil {
+			continue
+		}
+
+		// 消息按序号保存,每个聚合第一条未完成的消息才可领取
+		head := !seen[message.AggregateID]
+		seen[message.AggregateID] = true
+
+		if head && !message.NextAttemptAt.After(now) && len(messages) < limit {
+			messages = append(messages, *message)
+			message.NextAttemptAt = now.Add(lease)
+		}
+	}
+
+	return messages, nil
+}
+
+func (s *MemoryStore) MarkPublished(id int64, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = slices.DeleteFunc(s.messages, func(m Message) bool {
+		return m.ID == id
+	})
+
+	return nil
+}
+
+func (s *MemoryStore) MarkFailed(message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.messages {
+		if s.messages[i].ID == message.ID {
+			s.messages[i] = message
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package persistence
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"workit-sample/internal/todo/domain/identity"
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GormApiKeyRepository 基于 GORM 的 API Key 仓储
+type GormApiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormApiKeyRepository(db *gorm.DB) *GormApiKeyRepository {
+	return &GormApiKeyRepository{
+		db: db,
+	}
+}
+
+func (r *GormApiKeyRepository) Get(id uuid.UUID) (*identity.ApiKey, error) {
+	return r.first("id = ?", id)
+}
+
+func (r *GormApiKeyRepository) GetByHash(hash string) (*identity.ApiKey, error) {
+	return r.first("key_hash = ?", hash)
+}
+
+func (r *GormApiKeyRepository) Save(entity *identity.ApiKey) error {
+	return r.db.Save(entity).Error
+}
+
+func (r *GormApiKeyRepository) List(owner todo.Principal) ([]identity.ApiKey, error) {
+
+	var keys []identity.ApiKey
+
+	err := r.db.Where("tenant_id = ? AND owner_id = ?", owner.TenantID, owner.UserID).
+		Order("created_at DESC").
+		Order("id DESC").
+		Find(&keys).Error
+
+	return keys, err
+}
+
+func (r *GormApiKeyRepository) Touch(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&identity.ApiKey{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
+
+func (r *GormApiKeyRepository) first(query string, args ...any) (*identity.ApiKey, error) {
+
+	entity := identity.ApiKey{}
+
+	err := r.db.Where(query, args...).First(&entity).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, identity.ErrApiKeyNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity, nil
+}
+
+// MemoryApiKeyRepository 基于内存的 API Key 仓储,用于测试和本地运行
+type MemoryApiKeyRepository struct {
+	keys map[uuid.UUID]identity.ApiKey
+	mu   sync.RWMutex
+}
+
+func NewMemoryApiKeyRepository() *MemoryApiKeyRepository {
+	return &MemoryApiKeyRepository{
+		keys: make(map[uuid.UUID]identity.ApiKey),
+	}
+}
+
+func (r *MemoryApiKeyRepository) Get(id uuid.UUID) (*identity.ApiKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entity, ok := r.keys[id]
+	if !ok {
+		return nil, identity.ErrApiKeyNotFound
+	}
+
+	return &entity, nil
+}
+
+func (r *MemoryApiKeyRepository) GetByHash(hash string) (*identity.ApiKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, entity := range r.keys {
+		if entity.KeyHash == hash {
+			return &entity, nil
+		}
+	}
+
+	return nil, identity.ErrApiKeyNotFound
+}
+
+func (r *MemoryApiKeyRepository) Save(entity *identity.ApiKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[entity.ID] = *entity
+	return nil
+}
+
+func (r *MemoryApiKeyRepository) List(owner todo.Principal) ([]identity.ApiKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]identity.ApiKey, 0, len(r.keys))
+
+	for _, entity := range r.keys {
+		if !entity.Owner().Is(owner) {
+			continue
+		}
+
+		keys = append(keys, entity)
+	}
+
+	slices.SortFunc(keys, func(a, b identity.ApiKey) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID.String(), a.ID.String())
+	})
+
+	return keys, nil
+}
+
+func (r *MemoryApiKeyRepository) Touch(id uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entity, ok := r.keys[id]; ok {
+		entity.LastUsedAt = &at
+		r.keys[id] = entity
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package persistence
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"workit-sample/internal/todo/domain/identity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GormRefreshTokenRepository 基于 GORM 的刷新令牌仓储
+type GormRefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRefreshTokenRepository(db *gorm.DB) *GormRefreshTokenRepository {
+	return &GormRefreshTokenRepository{
+		db: db,
+	}
+}
+
+func (r *GormRefreshTokenRepository) GetByHash(hash string) (*identity.RefreshToken, error) {
+
+	entity := identity.RefreshToken{}
+
+	err := r.db.First(&entity, "token_hash = ?", hash).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, identity.ErrInvalidGrant
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity, nil
+}
+
+func (r *GormRefreshTokenRepository) Save(entity *identity.RefreshToken) error {
+	return r.db.Save(entity).Error
+}
+
+// Rotate 仅当当前令牌仍未轮换且未吊销时才标记轮换,并发使用同一令牌时只有一个请求成功
+func (r *GormRefreshTokenRepository) Rotate(current *identity.RefreshToken, next *identity.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+
+		result := tx.Model(&identity.RefreshToken{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", current.ID).
+			Update("rotated_at", current.RotatedAt)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return identity.ErrRefreshTokenReused
+		}
+
+		return tx.Create(next).Error
+	})
+}
+
+func (r *GormRefreshTokenRepository) RevokeFamily(familyID uuid.UUID, at time.Time) error {
+	return r.db.Model(&identity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+}
+
+// MemoryRefreshTokenRepository 基于内存的刷新令牌仓储,用于测试和本地运行
+type MemoryRefreshTokenRepository struct {
+	tokens map[uuid.UUID]identity.RefreshToken
+	hashes map[string]uuid.UUID
+	mu     sync.RWMutex
+}
+
+func NewMemoryRefreshTokenRepository() *MemoryRefreshTokenRepository {
+	return &MemoryRefreshTokenRepository{
+		tokens: make(map[uuid.UUID]identity.RefreshToken),
+		hashes: make(map[string]uuid.UUID),
+	}
+}
+
+func (r *MemoryRefreshTokenRepository) GetByHash(hash string) (*identity.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entity, ok := r.tokens[r.hashes[hash]]
+	if !ok {
+		return nil, identity.ErrInvalidGrant
+	}
+
+	return &entity, nil
+}
+
+func (r *MemoryRefreshTokenRepository) Save(entity *identity.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.save(entity)
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) Rotate(current *identity.RefreshToken, next *identity.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.tokens[current.ID]
+	if !ok || stored.RotatedAt != nil || stored.RevokedAt != nil {
+		return identity.ErrRefreshTokenReused
+	}
+
+	r.save(current)
+	r.save(next)
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) RevokeFamily(familyID uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, entity := range r.tokens {
+		if entity.FamilyID == familyID && entity.RevokedAt == nil {
+			entity.RevokedAt = &at
+			r.tokens[id] = entity
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) save(entity *identity.RefreshToken) {
+	r.tokens[entity.ID] = *entity
+	r.hashes[entity.TokenHash] = entity.ID
+}
@@ -0,0 +1,66 @@
+package persistence
+
+import (
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reminderRecord 已发送的截止提醒
+type reminderRecord struct {
+	Key    string    `gorm:"column:reminder_key;primaryKey"`
+	SentAt time.Time `gorm:"column:sent_at"`
+}
+
+func (reminderRecord) TableName() string {
+	return "todo_reminders"
+}
+
+// GormReminderLog 基于 GORM 的提醒记录,依靠主键冲突保证多实例下只发送一次
+type GormReminderLog struct {
+	db *gorm.DB
+}
+
+func NewGormReminderLog(db *gorm.DB) *GormReminderLog {
+	return &GormReminderLog{
+		db: db,
+	}
+}
+
+func (l *GormReminderLog) MarkSent(key string, at time.Time) (bool, error) {
+
+	result := l.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&reminderRecord{Key: key, SentAt: at})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// MemoryReminderLog 基于内存的提醒记录,用于测试和本地运行
+type MemoryReminderLog struct {
+	sent map[string]time.Time
+	mu   sync.Mutex
+}
+
+func NewMemoryReminderLog() *MemoryReminderLog {
+	return &MemoryReminderLog{
+		sent: make(map[string]time.Time),
+	}
+}
+
+func (l *MemoryReminderLog) MarkSent(key string, at time.Time) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.sent[key]; ok {
+		return false, nil
+	}
+
+	l.sent[key] = at
+	return true, nil
+}
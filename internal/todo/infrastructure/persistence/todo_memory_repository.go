@@ -0,0 +1,217 @@
+package persistence
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+)
+
+// MemoryTodoRepository 基于内存的待办事项仓储,用于测试和本地运行
+type MemoryTodoRepository struct {
+	todos map[uuid.UUID]todo.Todo
+	mu    sync.RWMutex
+}
+
+func NewMemoryTodoRepository() *MemoryTodoRepository {
+	return &MemoryTodoRepository{
+		todos: make(map[uuid.UUID]todo.Todo),
+	}
+}
+
+func (r *MemoryTodoRepository) Get(id uuid.UUID) (*todo.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entity, ok := r.todos[id]
+	if !ok {
+		return nil, todo.ErrTodoNotFound
+	}
+
+	entity = cloneTodo(entity)
+	return &entity, nil
+}
+
+func (r *MemoryTodoRepository) Save(entity *todo.Todo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 不存在时零值版本为 0,与未持久化的聚合一致
+	if stored := r.todos[entity.ID]; stored.Version != entity.Version {
+		return todo.ErrTodoVersionConflict
+	}
+
+	// 与数据库的唯一索引一致,重复系列中只有最新一次参与比较
+	if entity.NextOccurrenceID == nil && r.titleTaken(entity.Owner(), entity.Title, entity.ID) {
+		return todo.ErrTodoAlreadyExists
+	}
+
+	entity.Version++
+	r.todos[entity.ID] = cloneTodo(*entity)
+	return nil
+}
+
+func (r *MemoryTodoRepository) Delete(entity *todo.Todo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.todos[entity.ID]; !ok || stored.Version != entity.Version {
+		return todo.ErrTodoVersionConflict
+	}
+
+	delete(r.todos, entity.ID)
+	return nil
+}
+
+func (r *MemoryTodoRepository) ExistsByTitle(owner todo.Principal, title string, excludeID uuid.UUID) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.titleTaken(owner, title, excludeID), nil
+}
+
+// titleTaken 判断 owner 除 excludeID 外尚未生成下一次重复的待办事项中是否存在相同标题,调用方需持有锁
+func (r *MemoryTodoRepository) titleTaken(owner todo.Principal, title string, excludeID uuid.UUID) bool {
+
+	for id, entity := range r.todos {
+		if id != excludeID && entity.NextOccurrenceID == nil && entity.Owner().Is(owner) && strings.EqualFold(entity.Title, title) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *MemoryTodoRepository) List(spec todo.TodoSpecification) ([]todo.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	todos := r.filter(spec)
+
+	slices.SortFunc(todos, func(a, b todo.Todo) int {
+		return compareSortKey(sortKeyOf(b), sortKeyOf(a))
+	})
+
+	if spec.After != nil {
+		todos = slices.DeleteFunc(todos, func(t todo.Todo) bool {
+			return compareSortKey(sortKeyOf(t), *spec.After) >= 0
+		})
+	}
+
+	// 向前翻页时按升序取最接近游标的数据,返回前再还原为倒序
+	if spec.Before != nil {
+		todos = slices.DeleteFunc(todos, func(t todo.Todo) bool {
+			return compareSortKey(sortKeyOf(t), *spec.Before) <= 0
+		})
+		slices.Reverse(todos)
+	}
+
+	if spec.Offset > 0 {
+		todos = todos[min(spec.Offset, len(todos)):]
+	}
+
+	if spec.Limit > 0 && len(todos) > spec.Limit {
+		todos = todos[:spec.Limit]
+	}
+
+	if spec.Before != nil {
+		slices.Reverse(todos)
+	}
+
+	return todos, nil
+}
+
+func (r *MemoryTodoRepository) Count(spec todo.TodoSpecification) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.filter(spec))), nil
+}
+
+func (r *MemoryTodoRepository) ListDue(from, to time.Time) ([]todo.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	inRange := func(at *time.Time) bool {
+		return at != nil && !at.Before(from) && at.Before(to)
+	}
+
+	var todos []todo.Todo
+
+	for _, entity := range r.todos {
+
+		if entity.Completed {
+			continue
+		}
+
+		due := inRange(entity.DueAt)
+
+		for _, task := range entity.Tasks {
+			due = due || (!task.Completed && inRange(task.DueAt))
+		}
+
+		if due {
+			todos = append(todos, cloneTodo(entity))
+		}
+	}
+
+	return todos, nil
+}
+
+// filter 只查询规约中用户创建或被共享的数据(管理员为同一租户内的所有数据),关键词同时匹配标题和描述,不区分大小写,返回的数据不包含任务
+func (r *MemoryTodoRepository) filter(spec todo.TodoSpecification) []todo.Todo {
+
+	keyword := strings.ToLower(strings.TrimSpace(spec.Keyword))
+
+	todos := make([]todo.Todo, 0, len(r.todos))
+
+	for _, entity := range r.todos {
+		if entity.Authorize(spec.Principal, todo.RoleViewer) != nil {
+			continue
+		}
+
+		if keyword != "" && !strings.Contains(strings.ToLower(entity.Title), keyword) &&
+			(entity.Description == nil || !strings.Contains(strings.ToLower(*entity.Description), keyword)) {
+			continue
+		}
+
+		if (spec.DueFrom != nil || spec.DueTo != nil) && (entity.Completed || entity.DueAt == nil) {
+			continue
+		}
+
+		if (spec.DueFrom != nil && entity.DueAt.Before(*spec.DueFrom)) ||
+			(spec.DueTo != nil && !entity.DueAt.Before(*spec.DueTo)) {
+			continue
+		}
+
+		entity.Tasks = nil
+		entity.Members = slices.Clone(entity.Members)
+		todos = append(todos, entity)
+	}
+
+	return todos
+}
+
+func sortKeyOf(t todo.Todo) todo.SortKey {
+	return todo.SortKey{CreatedAt: t.CreatedAt, ID: t.ID}
+}
+
+func compareSortKey(a, b todo.SortKey) int {
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID.String(), b.ID.String())
+}
+
+// cloneTodo 复制聚合,避免调用方修改仓储内部状态
+func cloneTodo(t todo.Todo) todo.Todo {
+	t.Tasks = slices.Clone(t.Tasks)
+	t.Members = slices.Clone(t.Members)
+	// 未分发的领域事件不随聚合存储
+	t.ClearEvents()
+	return t
+}
@@ -0,0 +1,188 @@
+package persistence
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+)
+
+var alice = todo.Principal{TenantID: todo.DefaultTenant, UserID: "alice"}
+
+func newTodo(t *testing.T, title string) *todo.Todo {
+	t.Helper()
+
+	entity, err := todo.NewTodo(uuid.New(), alice, title)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return entity
+}
+
+// 两个请求都通过了 ExistsByTitle 检查时,后保存的请求仍然失败
+func TestSaveRejectsDuplicateTitle(t *testing.T) {
+
+	repo := NewMemoryTodoRepository()
+
+	if err := repo.Save(newTodo(t, "Buy milk")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Save(newTodo(t, "BUY MILK")); !errors.Is(err, todo.ErrTodoAlreadyExists) {
+		t.Fatalf("expected ErrTodoAlreadyExists, got %v", err)
+	}
+
+	renamed := newTodo(t, "Buy eggs")
+
+	if err := repo.Save(renamed); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := renamed.UpdateTitle("Buy milk"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Save(renamed); !errors.Is(err, todo.ErrTodoAlreadyExists) {
+		t.Fatalf("expected ErrTodoAlreadyExists, got %v", err)
+	}
+}
+
+func TestSaveAllowsRecurringSeriesToShareTitle(t *testing.T) {
+
+	repo := NewMemoryTodoRepository()
+
+	current := newTodo(t, "Weekly review")
+	due := time.Now().Add(time.Hour)
+
+	if err := current.SetDue(&due, "UTC"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := current.SetRecurrence("weekly"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Save(current); err != nil {
+		t.Fatal(err)
+	}
+
+	current.UpdateCompleted(true)
+
+	next, err := current.NextOccurrence(uuid.New())
+
+	if err != nil || next == nil {
+		t.Fatalf("next occurrence: %v, %v", next, err)
+	}
+
+	if err := repo.Save(current); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Save(next); err != nil {
+		t.Fatalf("next occurrence shares the title: %v", err)
+	}
+
+	if exists, _ := repo.ExistsByTitle(alice, "Weekly review", next.ID); exists {
+		t.Fatal("earlier occurrences must not hold the title")
+	}
+}
+
+// 截止时间范围与 ListDue 一致,都包含下限不含上限
+func TestDueWindowBoundaries(t *testing.T) {
+
+	repo := NewMemoryTodoRepository()
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	save := func(title string, due *time.Time, completed bool) {
+
+		entity := newTodo(t, title)
+
+		if err := entity.SetDue(due, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		entity.Completed = completed
+
+		if err := repo.Save(entity); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	at := func(d time.Time) *time.Time { return &d }
+
+	save("before from", at(from.Add(-time.Nanosecond)), false)
+	save("at from", at(from), false)
+	save("before to", at(to.Add(-time.Nanosecond)), false)
+	save("at to", at(to), false)
+	save("completed", at(from), true)
+	save("no due", nil, false)
+
+	// 自身不在范围内,但未完成任务的截止时间在范围内
+	withTask := newTodo(t, "task at from")
+
+	if err := withTask.SetDue(at(to.Add(time.Hour)), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := withTask.AddTask(uuid.New(), "Pack", nil, at(from), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Save(withTask); err != nil {
+		t.Fatal(err)
+	}
+
+	titles := func(todos []todo.Todo) map[string]bool {
+		m := make(map[string]bool, len(todos))
+		for _, entity := range todos {
+			m[entity.Title] = true
+		}
+		return m
+	}
+
+	due, err := repo.ListDue(from, to)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listed, err := repo.List(todo.TodoSpecification{Principal: alice, DueFrom: &from, DueTo: &to})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		title    string
+		due      bool // ListDue 返回
+		filtered bool // DueFrom/DueTo 返回
+	}{
+		{"before from", false, false},
+		{"at from", true, true},
+		{"before to", true, true},
+		{"at to", false, false},
+		{"completed", false, false},
+		{"no due", false, false},
+		{"task at from", true, false},
+	}
+
+	dueTitles, listedTitles := titles(due), titles(listed)
+
+	for _, tt := range cases {
+
+		if dueTitles[tt.title] != tt.due {
+			t.Errorf("ListDue %q: expected %v", tt.title, tt.due)
+		}
+
+		if listedTitles[tt.title] != tt.filtered {
+			t.Errorf("List %q: expected %v", tt.title, tt.filtered)
+		}
+	}
+}
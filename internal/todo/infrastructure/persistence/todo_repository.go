@@ -0,0 +1,305 @@
+package persistence
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GormTodoRepository 基于 GORM 的待办事项仓储
+type GormTodoRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTodoRepository(db *gorm.DB) *GormTodoRepository {
+	return &GormTodoRepository{
+		db: db,
+	}
+}
+
+func (r *GormTodoRepository) Get(id uuid.UUID) (*todo.Todo, error) {
+
+	entity := todo.Todo{}
+
+	// 使用 Preload 加载关联的 Tasks 与 Members
+	err := r.db.Preload("Tasks").Preload("Members").First(&entity, "id = ?", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, todo.ErrTodoNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity, nil
+}
+
+func (r *GormTodoRepository) Save(entity *todo.Todo) error {
+
+	expected := entity.Version
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		entity.Version = expected + 1
+
+		// 未持久化的聚合直接插入,任务随关联一并创建
+		if expected == 0 {
+			return titleConflict(tx, tx.Create(entity).Error)
+		}
+
+		// 仅当存储中的版本与聚合一致时才更新,否则说明已被其他请求修改
+		result := tx.Model(&todo.Todo{}).
+			Where("id = ? AND version = ?", entity.ID, expected).
+			Updates(map[string]any{
+				"title":              entity.Title,
+				"description":        entity.Description,
+				"completed":          entity.Completed,
+				"due_at":             entity.DueAt,
+				"due_time_zone":      entity.DueTimeZone,
+				"recurrence":         entity.Recurrence,
+				"series_id":          entity.SeriesID,
+				"occurrence":         entity.Occurrence,
+				"next_occurrence_id": entity.NextOccurrenceID,
+				"version":            entity.Version,
+			})
+
+		if result.Error != nil {
+			return titleConflict(tx, result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return todo.ErrTodoVersionConflict
+		}
+
+		// 删除已从聚合中移除的任务
+		orphans := tx.Where("todo_id = ?", entity.ID)
+
+		if len(entity.Tasks) > 0 {
+			ids := make([]uuid.UUID, len(entity.Tasks))
+			for i, task := range entity.Tasks {
+				ids[i] = task.ID
+			}
+			orphans = orphans.Where("id NOT IN ?", ids)
+		}
+
+		if err := orphans.Delete(&todo.Task{}).Error; err != nil {
+			return err
+		}
+
+		if len(entity.Tasks) > 0 {
+			if err := tx.Save(&entity.Tasks).Error; err != nil {
+				return err
+			}
+		}
+
+		return saveMembers(tx, entity)
+	})
+
+	// 失败时还原版本号,避免聚合与存储不一致
+	if err != nil {
+		entity.Version = expected
+	}
+
+	return err
+}
+
+// titleConflict 将违反标题唯一索引的错误转换为 ErrTodoAlreadyExists。
+// 并发创建或改名时,事务内的 ExistsByTitle 检查无法阻止相同标题写入,由唯一索引兜底;
+// 待办事项表上除主键外只有标题唯一索引,主键为新生成的 UUID,不会冲突
+func titleConflict(db *gorm.DB, err error) error {
+
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok && errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey) {
+		return todo.ErrTodoAlreadyExists
+	}
+
+	return err
+}
+
+// saveMembers 删除已从聚合中移除的成员并保存其余成员
+func saveMembers(tx *gorm.DB, entity *todo.Todo) error {
+
+	removed := tx.Where("todo_id = ?", entity.ID)
+
+	if len(entity.Members) > 0 {
+		userIDs := make([]string, len(entity.Members))
+		for i, member := range entity.Members {
+			userIDs[i] = member.UserID
+		}
+		removed = removed.Where("user_id NOT IN ?", userIDs)
+	}
+
+	if err := removed.Delete(&todo.Member{}).Error; err != nil {
+		return err
+	}
+
+	if len(entity.Members) == 0 {
+		return nil
+	}
+
+	return tx.Save(&entity.Members).Error
+}
+
+func (r *GormTodoRepository) Delete(entity *todo.Todo) error {
+
+	// 在同一事务中删除任务与待办事项
+	return r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Where("todo_id = ?", entity.ID).Delete(&todo.Task{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("todo_id = ?", entity.ID).Delete(&todo.Member{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ? AND version = ?", entity.ID, entity.Version).Delete(&todo.Todo{})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return todo.ErrTodoVersionConflict
+		}
+
+		return nil
+	})
+}
+
+func (r *GormTodoRepository) ExistsByTitle(owner todo.Principal, title string, excludeID uuid.UUID) (bool, error) {
+
+	var count int64
+
+	// 统一按不区分大小写比较,与 MySQL 默认排序规则的行为保持一致;
+	// 与唯一索引一致,重复系列中已生成下一次重复的待办事项不参与比较
+	db := r.db.Model(&todo.Todo{}).
+		Where("tenant_id = ? AND owner_id = ?", owner.TenantID, owner.UserID).
+		Where("LOWER(title) = LOWER(?) AND next_occurrence_id IS NULL", title)
+
+	if excludeID != uuid.Nil {
+		db = db.Where("id <> ?", excludeID)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count != 0, nil
+}
+
+func (r *GormTodoRepository) List(spec todo.TodoSpecification) ([]todo.Todo, error) {
+
+	db := r.filter(spec)
+
+	if spec.After != nil {
+		db = db.Where("created_at < ? OR (created_at = ? AND id < ?)", spec.After.CreatedAt, spec.After.CreatedAt, spec.After.ID)
+	}
+
+	// 向前翻页时按升序取最近的数据,返回前再还原为倒序
+	if spec.Before != nil {
+		db = db.Where("created_at > ? OR (created_at = ? AND id > ?)", spec.Before.CreatedAt, spec.Before.CreatedAt, spec.Before.ID).
+			Order("created_at ASC, id ASC")
+	} else {
+		db = db.Order("created_at DESC, id DESC")
+	}
+
+	if spec.Offset > 0 {
+		db = db.Offset(spec.Offset)
+	}
+
+	if spec.Limit > 0 {
+		db = db.Limit(spec.Limit)
+	}
+
+	var todos []todo.Todo
+
+	if err := db.Preload("Members").Find(&todos).Error; err != nil {
+		return nil, err
+	}
+
+	if spec.Before != nil {
+		slices.Reverse(todos)
+	}
+
+	return todos, nil
+}
+
+func (r *GormTodoRepository) Count(spec todo.TodoSpecification) (int64, error) {
+
+	var total int64
+
+	if err := r.filter(spec).Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *GormTodoRepository) ListDue(from, to time.Time) ([]todo.Todo, error) {
+
+	tasks := r.db.Model(&todo.Task{}).
+		Select("todo_id").
+		Where("completed = ? AND due_at >= ? AND due_at < ?", false, from, to)
+
+	var todos []todo.Todo
+
+	err := r.db.Preload("Tasks").Preload("Members").
+		Where("completed = ?", false).
+		Where("(due_at >= ? AND due_at < ?) OR id IN (?)", from, to, tasks).
+		Find(&todos).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+// filter 只查询规约中用户创建或被共享的数据(管理员为同一租户内的所有数据),关键词同时匹配标题和描述,不区分大小写
+func (r *GormTodoRepository) filter(spec todo.TodoSpecification) *gorm.DB {
+
+	shared := r.db.Model(&todo.Member{}).
+		Select("todo_id").
+		Where("user_id = ?", spec.Principal.UserID)
+
+	db := r.db.Model(&todo.Todo{}).
+		Where("tenant_id = ?", spec.Principal.TenantID)
+
+	// 与 Todo.Authorize 一致,管理员可查看同一租户内的所有待办事项
+	if !spec.Principal.Admin {
+		db = db.Where("owner_id = ? OR id IN (?)", spec.Principal.UserID, shared)
+	}
+
+	// PostgreSQL 的 LIKE 区分大小写,统一转为小写后比较
+	if keyword := strings.TrimSpace(spec.Keyword); keyword != "" {
+		pattern := "%" + escapeLike(strings.ToLower(keyword)) + "%"
+		db = db.Where("LOWER(title) LIKE ? OR LOWER(description) LIKE ?", pattern, pattern)
+	}
+
+	if spec.DueFrom != nil || spec.DueTo != nil {
+		db = db.Where("completed = ? AND due_at IS NOT NULL", false)
+	}
+
+	if spec.DueFrom != nil {
+		db = db.Where("due_at >= ?", *spec.DueFrom)
+	}
+
+	if spec.DueTo != nil {
+		db = db.Where("due_at < ?", *spec.DueTo)
+	}
+
+	return db
+}
+
+// escapeLike 转义 LIKE 通配符,避免关键词中的 % 和 _ 被当作通配符
+// MySQL 与 PostgreSQL 的 LIKE 默认转义字符均为反斜杠
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
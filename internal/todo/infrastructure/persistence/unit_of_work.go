@@ -0,0 +1,172 @@
+package persistence
+
+import (
+	"maps"
+	"sync"
+
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+	"workit-sample/internal/todo/infrastructure/outbox"
+
+	"gorm.io/gorm"
+)
+
+// GormUnitOfWork 基于数据库事务的工作单元,聚合产生的领域事件在同一事务中写入发件箱并生成 Webhook 投递记录,
+// 提交后再分发给进程内处理器
+type GormUnitOfWork struct {
+	db         *gorm.DB
+	dispatcher todo.EventDispatcher
+}
+
+func NewGormUnitOfWork(db *gorm.DB, dispatcher todo.EventDispatcher) *GormUnitOfWork {
+	return &GormUnitOfWork{
+		db:         db,
+		dispatcher: dispatcher,
+	}
+}
+
+func (u *GormUnitOfWork) Execute(fn func(repo todo.TodoRepository) error) error {
+
+	var events []todo.Event
+
+	// 仓储内部的事务在此嵌套为保存点
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+
+		repo := &trackedRepository{TodoRepository: NewGormTodoRepository(tx)}
+
+		if err := fn(repo); err != nil {
+			return err
+		}
+
+		events = repo.clearEvents()
+
+		messages, err := outbox.NewMessages(events)
+
+		if err != nil {
+			return err
+		}
+
+		if err := outbox.Append(tx, messages); err != nil {
+			return err
+		}
+
+		return webhook.Fanout(NewGormSubscriptionRepository(tx), NewGormDeliveryRepository(tx), events...)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	u.dispatcher.Dispatch(events...)
+
+	return nil
+}
+
+// MemoryUnitOfWork 基于内存仓储的工作单元,工作单元之间串行执行,
+// 写入先落在副本上,成功后与发件箱消息、Webhook 投递记录一起生效
+type MemoryUnitOfWork struct {
+	repo          *MemoryTodoRepository
+	outbox        *outbox.MemoryStore
+	subscriptions webhook.SubscriptionRepository
+	deliveries    webhook.DeliveryRepository
+	dispatcher    todo.EventDispatcher
+	mu            sync.Mutex
+}
+
+func NewMemoryUnitOfWork(repo *MemoryTodoRepository, store *outbox.MemoryStore, subscriptions webhook.SubscriptionRepository, deliveries webhook.DeliveryRepository, dispatcher todo.EventDispatcher) *MemoryUnitOfWork {
+	return &MemoryUnitOfWork{
+		repo:          repo,
+		outbox:        store,
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		dispatcher:    dispatcher,
+	}
+}
+
+func (u *MemoryUnitOfWork) Execute(fn func(repo todo.TodoRepository) error) error {
+
+	events, err := u.commit(fn)
+
+	if err != nil {
+		return err
+	}
+
+	// 在释放工作单元锁之后分发,处理器可以开启新的工作单元
+	u.dispatcher.Dispatch(events...)
+
+	return nil
+}
+
+func (u *MemoryUnitOfWork) commit(fn func(repo todo.TodoRepository) error) ([]todo.Event, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// 仓储中保存的都是克隆值,浅拷贝即可隔离
+	u.repo.mu.RLock()
+	staged := &MemoryTodoRepository{todos: maps.Clone(u.repo.todos)}
+	u.repo.mu.RUnlock()
+
+	repo := &trackedRepository{TodoRepository: staged}
+
+	if err := fn(repo); err != nil {
+		return nil, err
+	}
+
+	events := repo.clearEvents()
+
+	messages, err := outbox.NewMessages(events)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := webhook.Fanout(u.subscriptions, u.deliveries, events...); err != nil {
+		return nil, err
+	}
+
+	u.repo.mu.Lock()
+	u.repo.todos = staged.todos
+	u.repo.mu.Unlock()
+
+	u.outbox.Append(messages...)
+
+	return events, nil
+}
+
+// trackedRepository 记录工作单元内保存或删除的聚合,用于提交后收集领域事件
+type trackedRepository struct {
+	todo.TodoRepository
+	aggregates []*todo.Todo
+}
+
+func (r *trackedRepository) Save(entity *todo.Todo) error {
+
+	if err := r.TodoRepository.Save(entity); err != nil {
+		return err
+	}
+
+	r.aggregates = append(r.aggregates, entity)
+	return nil
+}
+
+func (r *trackedRepository) Delete(entity *todo.Todo) error {
+
+	if err := r.TodoRepository.Delete(entity); err != nil {
+		return err
+	}
+
+	r.aggregates = append(r.aggregates, entity)
+	return nil
+}
+
+// clearEvents 按保存顺序收集并清空聚合的领域事件,同一聚合多次保存时只收集一次
+func (r *trackedRepository) clearEvents() []todo.Event {
+
+	var events []todo.Event
+
+	for _, aggregate := range r.aggregates {
+		events = append(events, aggregate.ClearEvents()...)
+	}
+
+	return events
+}
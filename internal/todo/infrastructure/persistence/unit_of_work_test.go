@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"errors"
+	"testing"
+
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+	"workit-sample/internal/todo/infrastructure/outbox"
+
+	"github.com/google/uuid"
+)
+
+// crashingDispatcher 模拟提交后、进程内分发前进程退出
+type crashingDispatcher struct{}
+
+func (crashingDispatcher) Dispatch(events ...todo.Event) {
+	panic("process exited")
+}
+
+func newWebhookUnitOfWork(t *testing.T, dispatcher todo.EventDispatcher) (*MemoryUnitOfWork, *MemoryDeliveryRepository, *webhook.Subscription) {
+	t.Helper()
+
+	deliveries := NewMemoryDeliveryRepository()
+	subscriptions := NewMemorySubscriptionRepository(deliveries)
+
+	subscription, err := webhook.NewSubscription(uuid.New(), alice, "https://example.com/hook", nil, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := subscriptions.Save(subscription); err != nil {
+		t.Fatal(err)
+	}
+
+	uow := NewMemoryUnitOfWork(NewMemoryTodoRepository(), outbox.NewMemoryStore(), subscriptions, deliveries, dispatcher)
+
+	return uow, deliveries, subscription
+}
+
+// 投递记录随提交一起生效,不依赖提交后的进程内分发
+func TestDeliverySurvivesFailureAfterCommit(t *testing.T) {
+
+	uow, deliveries, subscription := newWebhookUnitOfWork(t, crashingDispatcher{})
+
+	func() {
+		defer func() { _ = recover() }()
+
+		_ = uow.Execute(func(repo todo.TodoRepository) error {
+			return repo.Save(newTodo(t, "Buy milk"))
+		})
+	}()
+
+	pending, err := deliveries.ListBySubscription(subscription.ID, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 1 || pending[0].EventName != todo.EventTodoCreated || pending[0].Status != webhook.DeliveryPending {
+		t.Fatalf("expected one pending todo.created delivery, got %+v", pending)
+	}
+}
+
+func TestFailedUnitOfWorkCreatesNoDelivery(t *testing.T) {
+
+	uow, deliveries, subscription := newWebhookUnitOfWork(t, crashingDispatcher{})
+
+	err := uow.Execute(func(repo todo.TodoRepository) error {
+
+		if err := repo.Save(newTodo(t, "Buy milk")); err != nil {
+			return err
+		}
+
+		return errors.New("rollback")
+	})
+
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	if pending, _ := deliveries.ListBySubscription(subscription.ID, 0); len(pending) != 0 {
+		t.Fatalf("expected no delivery for a rolled back unit of work, got %d", len(pending))
+	}
+}
@@ -0,0 +1,192 @@
+package persistence
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+
+	"github.com/google/uuid"
+)
+
+// MemorySubscriptionRepository 基于内存的 Webhook 订阅仓储
+type MemorySubscriptionRepository struct {
+	subscriptions map[uuid.UUID]webhook.Subscription
+	deliveries    *MemoryDeliveryRepository
+	mu            sync.RWMutex
+}
+
+func NewMemorySubscriptionRepository(deliveries *MemoryDeliveryRepository) *MemorySubscriptionRepository {
+	return &MemorySubscriptionRepository{
+		subscriptions: make(map[uuid.UUID]webhook.Subscription),
+		deliveries:    deliveries,
+	}
+}
+
+func (r *MemorySubscriptionRepository) Get(id uuid.UUID) (*webhook.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entity, ok := r.subscriptions[id]
+	if !ok {
+		return nil, webhook.ErrWebhookNotFound
+	}
+
+	entity.Events = slices.Clone(entity.Events)
+	return &entity, nil
+}
+
+func (r *MemorySubscriptionRepository) Save(entity *webhook.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *entity
+	stored.Events = slices.Clone(entity.Events)
+	r.subscriptions[entity.ID] = stored
+	return nil
+}
+
+func (r *MemorySubscriptionRepository) Delete(entity *webhook.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.subscriptions, entity.ID)
+	r.deliveries.deleteBySubscription(entity.ID)
+	return nil
+}
+
+func (r *MemorySubscriptionRepository) List(owner todo.Principal) ([]webhook.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscriptions := make([]webhook.Subscription, 0, len(r.subscriptions))
+
+	for _, entity := range r.subscriptions {
+		if !entity.Owner().Is(owner) {
+			continue
+		}
+
+		entity.Events = slices.Clone(entity.Events)
+		subscriptions = append(subscriptions, entity)
+	}
+
+	slices.SortFunc(subscriptions, func(a, b webhook.Subscription) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID.String(), a.ID.String())
+	})
+
+	return subscriptions, nil
+}
+
+// MemoryDeliveryRepository 基于内存的投递记录仓储
+type MemoryDeliveryRepository struct {
+	deliveries map[uuid.UUID]webhook.Delivery
+	mu         sync.RWMutex
+}
+
+func NewMemoryDeliveryRepository() *MemoryDeliveryRepository {
+	return &MemoryDeliveryRepository{
+		deliveries: make(map[uuid.UUID]webhook.Delivery),
+	}
+}
+
+func (r *MemoryDeliveryRepository) Get(id uuid.UUID) (*webhook.Delivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entity, ok := r.deliveries[id]
+	if !ok {
+		return nil, webhook.ErrDeliveryNotFound
+	}
+
+	return &entity, nil
+}
+
+func (r *MemoryDeliveryRepository) Save(entity *webhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deliveries[entity.ID] = *entity
+	return nil
+}
+
+func (r *MemoryDeliveryRepository) ListBySubscription(subscriptionID uuid.UUID, limit int) ([]webhook.Delivery, error) {
+
+	deliveries := r.filter(func(d webhook.Delivery) bool {
+		return d.SubscriptionID == subscriptionID
+	})
+
+	slices.SortFunc(deliveries, func(a, b webhook.Delivery) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID.String(), a.ID.String())
+	})
+
+	if limit > 0 && len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+func (r *MemoryDeliveryRepository) Claim(now time.Time, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deliveries []webhook.Delivery
+
+	for _, entity := range r.deliveries {
+		if entity.Status == webhook.DeliveryPending && !entity.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, entity)
+		}
+	}
+
+	slices.SortFunc(deliveries, func(a, b webhook.Delivery) int {
+		if c := a.NextAttemptAt.Compare(b.NextAttemptAt); c != 0 {
+			return c
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	for _, delivery := range deliveries {
+		delivery.NextAttemptAt = now.Add(lease)
+		r.deliveries[delivery.ID] = delivery
+	}
+
+	return deliveries, nil
+}
+
+func (r *MemoryDeliveryRepository) filter(match func(webhook.Delivery) bool) []webhook.Delivery {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var deliveries []webhook.Delivery
+
+	for _, entity := range r.deliveries {
+		if match(entity) {
+			deliveries = append(deliveries, entity)
+		}
+	}
+
+	return deliveries
+}
+
+func (r *MemoryDeliveryRepository) deleteBySubscription(subscriptionID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	maps.DeleteFunc(r.deliveries, func(_ uuid.UUID, d webhook.Delivery) bool {
+		return d.SubscriptionID == subscriptionID
+	})
+}
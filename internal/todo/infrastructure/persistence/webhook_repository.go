@@ -0,0 +1,141 @@
+package persistence
+
+import (
+	"errors"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormSubscriptionRepository 基于 GORM 的 Webhook 订阅仓储
+type GormSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormSubscriptionRepository(db *gorm.DB) *GormSubscriptionRepository {
+	return &GormSubscriptionRepository{
+		db: db,
+	}
+}
+
+func (r *GormSubscriptionRepository) Get(id uuid.UUID) (*webhook.Subscription, error) {
+
+	entity := webhook.Subscription{}
+
+	err := r.db.First(&entity, "id = ?", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, webhook.ErrWebhookNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity, nil
+}
+
+func (r *GormSubscriptionRepository) Save(entity *webhook.Subscription) error {
+	return r.db.Save(entity).Error
+}
+
+func (r *GormSubscriptionRepository) Delete(entity *webhook.Subscription) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Where("subscription_id = ?", entity.ID).Delete(&webhook.Delivery{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(entity).Error
+	})
+}
+
+func (r *GormSubscriptionRepository) List(owner todo.Principal) ([]webhook.Subscription, error) {
+
+	var subscriptions []webhook.Subscription
+
+	err := r.db.Where("tenant_id = ? AND owner_id = ?", owner.TenantID, owner.UserID).Order("created_at DESC, id DESC").Find(&subscriptions).Error
+
+	return subscriptions, err
+}
+
+// GormDeliveryRepository 基于 GORM 的投递记录仓储
+type GormDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewGormDeliveryRepository(db *gorm.DB) *GormDeliveryRepository {
+	return &GormDeliveryRepository{
+		db: db,
+	}
+}
+
+func (r *GormDeliveryRepository) Get(id uuid.UUID) (*webhook.Delivery, error) {
+
+	entity := webhook.Delivery{}
+
+	err := r.db.First(&entity, "id = ?", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, webhook.ErrDeliveryNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity, nil
+}
+
+func (r *GormDeliveryRepository) Save(entity *webhook.Delivery) error {
+	return r.db.Save(entity).Error
+}
+
+func (r *GormDeliveryRepository) ListBySubscription(subscriptionID uuid.UUID, limit int) ([]webhook.Delivery, error) {
+
+	var deliveries []webhook.Delivery
+
+	query := r.db.Where("subscription_id = ?", subscriptionID).Order("created_at DESC, id DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	err := query.Find(&deliveries).Error
+
+	return deliveries, err
+}
+
+func (r *GormDeliveryRepository) Claim(now time.Time, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+
+	var deliveries []webhook.Delivery
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		// SKIP LOCKED 跳过其他实例正在领取的行
+		err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+			Where("status = ? AND next_attempt_at <= ?", webhook.DeliveryPending, now).
+			Order("next_attempt_at ASC, created_at ASC").
+			Limit(limit).
+			Find(&deliveries).Error
+
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(deliveries))
+
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+
+		return tx.Model(&webhook.Delivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+
+	return deliveries, err
+}
@@ -0,0 +1,156 @@
+package reminder
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Options 截止提醒配置,对应 application.yaml 中的 reminder 节点
+type Options struct {
+	Interval time.Duration   `mapstructure:"interval"` // 扫描间隔
+	Offsets  []time.Duration `mapstructure:"offsets"`  // 截止时间前的提醒时间点,0 表示到期时提醒
+	CatchUp  time.Duration   `mapstructure:"catch_up"` // 启动时补发最近这段时间内错过的提醒
+}
+
+func NewOptions(v *viper.Viper) (Options, error) {
+
+	options := Options{
+		Interval: time.Minute,
+		Offsets:  []time.Duration{24 * time.Hour, time.Hour, 0},
+		CatchUp:  time.Hour,
+	}
+
+	if err := v.UnmarshalKey("reminder", &options); err != nil {
+		return Options{}, err
+	}
+
+	return options, nil
+}
+
+// Scheduler 定期扫描即将到期的待办事项与任务,在提醒时间点生成 Webhook 投递记录并分发截止提醒事件。
+// 提醒由存储中的截止时间推导,不写入发件箱;先记录再分发,同一提醒最多发送一次
+type Scheduler struct {
+	repo          todo.TodoRepository
+	sent          todo.ReminderLog
+	subscriptions webhook.SubscriptionRepository
+	deliveries    webhook.DeliveryRepository
+	dispatcher    todo.EventDispatcher
+	options       Options
+	log           *zap.Logger
+
+	last   time.Time // 上一次扫描的截止位置
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewScheduler(repo todo.TodoRepository, sent todo.ReminderLog, subscriptions webhook.SubscriptionRepository, deliveries webhook.DeliveryRepository, dispatcher todo.EventDispatcher, options Options, log *zap.Logger) *Scheduler {
+	return &Scheduler{
+		repo:          repo,
+		sent:          sent,
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		dispatcher:    dispatcher,
+		options:       options,
+		log:           log,
+	}
+}
+
+// Start 启动后台扫描
+func (s *Scheduler) Start(ctx context.Context) error {
+
+	runCtx, cancel := context.WithCancel(context.Background())
+
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	s.last = time.Now().Add(-s.options.CatchUp)
+
+	go s.run(runCtx)
+
+	return nil
+}
+
+// Stop 停止后台扫描并等待当前扫描完成
+func (s *Scheduler) Stop(ctx context.Context) error {
+
+	s.cancel()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+
+		if err := s.RemindOnce(ctx, s.last, now); err != nil {
+			s.log.Error("failed to send due reminders", zap.Error(err))
+		} else {
+			s.last = now
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RemindOnce 分发提醒时间落在 [from, to) 内的截止提醒,相邻两次扫描的范围首尾相接
+func (s *Scheduler) RemindOnce(ctx context.Context, from, to time.Time) error {
+
+	if len(s.options.Offsets) == 0 || !to.After(from) {
+		return nil
+	}
+
+	// 提醒时间 = 截止时间 - 提前量,据此换算出需要加载的截止时间范围
+	todos, err := s.repo.ListDue(from.Add(slices.Min(s.options.Offsets)), to.Add(slices.Max(s.options.Offsets)))
+
+	if err != nil {
+		return err
+	}
+
+	for i := range todos {
+		for _, reminder := range todos[i].DueReminders(from, to, s.options.Offsets) {
+
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			first, err := s.sent.MarkSent(reminder.ReminderKey(), time.Now())
+
+			if err != nil {
+				return err
+			}
+
+			if !first {
+				continue
+			}
+
+			if err := webhook.Fanout(s.subscriptions, s.deliveries, reminder); err != nil {
+				s.log.Error("failed to save webhook deliveries for reminder", zap.String("key", reminder.ReminderKey()), zap.Error(err))
+			}
+
+			s.dispatcher.Dispatch(reminder)
+		}
+	}
+
+	return nil
+}
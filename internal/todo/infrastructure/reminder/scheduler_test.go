@@ -0,0 +1,129 @@
+package reminder
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+	"workit-sample/internal/todo/infrastructure/persistence"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var alice = todo.Principal{TenantID: todo.DefaultTenant, UserID: "alice"}
+
+// recorder 记录分发的提醒
+type recorder struct {
+	keys []string
+	mu   sync.Mutex
+}
+
+func (r *recorder) Dispatch(events ...todo.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, event := range events {
+		r.keys = append(r.keys, event.(todo.ReminderEvent).ReminderKey())
+	}
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.keys)
+}
+
+// newScheduler 创建 due 到期、提前 1 小时与到期时各提醒一次的调度器
+func newScheduler(t *testing.T, due time.Time) (*Scheduler, *recorder, *persistence.MemoryDeliveryRepository, uuid.UUID) {
+	t.Helper()
+
+	repo := persistence.NewMemoryTodoRepository()
+
+	entity, err := todo.NewTodo(uuid.New(), alice, "Submit report")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := entity.SetDue(&due, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Save(entity); err != nil {
+		t.Fatal(err)
+	}
+
+	deliveries := persistence.NewMemoryDeliveryRepository()
+	subscriptions := persistence.NewMemorySubscriptionRepository(deliveries)
+
+	subscription, err := webhook.NewSubscription(uuid.New(), alice, "https://example.com/hook", nil, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := subscriptions.Save(subscription); err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher := &recorder{}
+	options := Options{Interval: time.Minute, Offsets: []time.Duration{time.Hour, 0}}
+
+	scheduler := NewScheduler(repo, persistence.NewMemoryReminderLog(), subscriptions, deliveries, dispatcher, options, zap.NewNop())
+
+	return scheduler, dispatcher, deliveries, subscription.ID
+}
+
+// 重叠的扫描范围(如重启后补发)不会重复发送已发送的提醒
+func TestSecondRunDoesNotResendReminders(t *testing.T) {
+
+	due := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	scheduler, dispatcher, deliveries, subscriptionID := newScheduler(t, due)
+
+	for run := 1; run <= 2; run++ {
+
+		if err := scheduler.RemindOnce(context.Background(), due.Add(-2*time.Hour), due.Add(time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+
+		if count := dispatcher.count(); count != 2 {
+			t.Fatalf("run %d: expected 2 reminders, got %d", run, count)
+		}
+	}
+
+	if saved, _ := deliveries.ListBySubscription(subscriptionID, 0); len(saved) != 2 {
+		t.Fatalf("expected 2 webhook deliveries, got %d", len(saved))
+	}
+}
+
+// 相邻扫描范围首尾相接,恰好落在边界上的提醒只发送一次
+func TestAdjacentRunsShareBoundaryOnce(t *testing.T) {
+
+	due := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	scheduler, dispatcher, _, _ := newScheduler(t, due)
+
+	windows := []struct {
+		from, to time.Time
+		total    int
+	}{
+		{due.Add(-2 * time.Hour), due.Add(-time.Hour), 0},
+		{due.Add(-time.Hour), due, 1},
+		{due, due.Add(time.Minute), 2},
+	}
+
+	for _, w := range windows {
+
+		if err := scheduler.RemindOnce(context.Background(), w.from, w.to); err != nil {
+			t.Fatal(err)
+		}
+
+		if count := dispatcher.count(); count != w.total {
+			t.Fatalf("[%s, %s): expected %d reminders in total, got %d", w.from.Format(time.TimeOnly), w.to.Format(time.TimeOnly), w.total, count)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"syscall"
+	"time"
+
+	"workit-sample/internal/todo/domain/webhook"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// 请求头
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature-256" // sha256=<HMAC-SHA256(secret, body) 的十六进制>
+)
+
+// Options Webhook 投递配置,对应 application.yaml 中的 webhook 节点
+type Options struct {
+	Interval    time.Duration `mapstructure:"interval"`     // 轮询间隔
+	BatchSize   int           `mapstructure:"batch_size"`   // 每次轮询处理的投递数
+	Timeout     time.Duration `mapstructure:"timeout"`      // 单次请求超时
+	MaxAttempts int           `mapstructure:"max_attempts"` // 最大投递次数,超过后标记失败
+	BaseBackoff time.Duration `mapstructure:"base_backoff"` // 首次重试间隔,之后按指数增长
+	MaxBackoff  time.Duration `mapstructure:"max_backoff"`  // 重试间隔上限
+	Concurrency int           `mapstructure:"concurrency"`  // 同时投递的订阅数
+	Lease       time.Duration `mapstructure:"lease"`        // 领取投递的租约,应大于投递一批的耗时
+}
+
+func NewOptions(v *viper.Viper) (Options, error) {
+
+	options := Options{
+		Interval:    time.Second,
+		BatchSize:   100,
+		Timeout:     10 * time.Second,
+		MaxAttempts: 8,
+		BaseBackoff: 10 * time.Second,
+		MaxBackoff:  time.Hour,
+		Concurrency: 10,
+		Lease:       2 * time.Minute,
+	}
+
+	if err := v.UnmarshalKey("webhook", &options); err != nil {
+		return Options{}, err
+	}
+
+	return options, nil
+}
+
+// Sign 计算请求体签名,接收方使用相同密钥计算后比较 X-Webhook-Signature-256
+func Sign(secret string, body []byte) string {
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Worker 轮询到期的投递记录并发送,对方返回 2xx 视为成功,否则按指数退避重试。
+// 多个实例可同时运行,每条投递只会被一个实例领取
+type Worker struct {
+	subscriptions webhook.SubscriptionRepository
+	deliveries    webhook.DeliveryRepository
+	client        *http.Client
+	options       Options
+	log           *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewWorker(subscriptions webhook.SubscriptionRepository, deliveries webhook.DeliveryRepository, options Options, log *zap.Logger) *Worker {
+	return &Worker{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		client:        newClient(options.Timeout),
+		options:       options,
+		log:           log,
+	}
+}
+
+// ErrDestinationNotAllowed 目标地址解析到本机或内网地址
+var ErrDestinationNotAllowed = errors.New("webhook destination is not a public address")
+
+// newClient 创建投递使用的 HTTP 客户端。订阅创建后域名解析结果可能改变,
+// 因此在建立每个连接(包括重定向)时校验实际连接的地址;不使用环境变量中的代理,否则校验的是代理地址
+func newClient(timeout time.Duration) *http.Client {
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+
+			host, _, err := net.SplitHostPort(address)
+
+			if err != nil {
+				return err
+			}
+
+			if addr, err := netip.ParseAddr(host); err != nil || !webhook.PublicAddress(addr) {
+				return fmt.Errorf("%w: %s", ErrDestinationNotAllowed, host)
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// WithClient 返回使用指定 HTTP 客户端的副本
+func (w *Worker) WithClient(client *http.Client) *Worker {
+	return &Worker{
+		subscriptions: w.subscriptions,
+		deliveries:    w.deliveries,
+		client:        client,
+		options:       w.options,
+		log:           w.log,
+	}
+}
+
+// Start 启动后台投递
+func (w *Worker) Start(ctx context.Context) error {
+
+	runCtx, cancel := context.WithCancel(context.Background())
+
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go w.run(runCtx)
+
+	return nil
+}
+
+// Stop 停止后台投递并等待当前批次完成
+func (w *Worker) Stop(ctx context.Context) error {
+
+	w.cancel()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) run(ctx context.Context) {
+
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.DeliverOnce(ctx); err != nil {
+			w.log.Error("failed to deliver webhooks", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverOnce 领取一批到期的投递并发送。不同订阅的投递并发发送,
+// 同一订阅的投递依次发送,慢速或不可用的接收方不影响其他订阅
+func (w *Worker) DeliverOnce(ctx context.Context) error {
+
+	claimed := time.Now()
+	deliveries, err := w.deliveries.Claim(claimed, w.options.BatchSize, w.options.Lease)
+
+	if err != nil {
+		return err
+	}
+
+	var subscriptions []uuid.UUID
+
+	grouped := make(map[uuid.UUID][]webhook.Delivery)
+
+	for _, delivery := range deliveries {
+
+		if _, ok := grouped[delivery.SubscriptionID]; !ok {
+			subscriptions = append(subscriptions, delivery.SubscriptionID)
+		}
+
+		grouped[delivery.SubscriptionID] = append(grouped[delivery.SubscriptionID], delivery)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	slots := make(chan struct{}, max(w.options.Concurrency, 1))
+
+	for _, subscriptionID := range subscriptions {
+
+		slots <- struct{}{}
+
+		wg.Go(func() {
+			defer func() { <-slots }()
+
+			if err := w.deliverAll(ctx, claimed, grouped[subscriptionID]); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		})
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// deliverAll 依次发送同一订阅的投递。发送失败后不再发送其余投递,
+// 避免逐条等待超时,其余投递在租约到期后重新领取
+func (w *Worker) deliverAll(ctx context.Context, claimed time.Time, deliveries []webhook.Delivery) error {
+
+	for i := range deliveries {
+
+		// 租约到期后剩余投递可能已被其他实例领取
+		if ctx.Err() != nil || time.Since(claimed) >= w.options.Lease {
+			return nil
+		}
+
+		sent, err := w.deliver(ctx, &deliveries[i])
+
+		if err != nil || !sent {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deliver 发送并保存投递结果,返回是否可以继续发送同一订阅的投递
+func (w *Worker) deliver(ctx context.Context, delivery *webhook.Delivery) (bool, error) {
+
+	subscription, err := w.subscriptions.Get(delivery.SubscriptionID)
+
+	if err != nil && !errors.Is(err, webhook.ErrWebhookNotFound) {
+		return false, err
+	}
+
+	// 订阅已删除或停用时不再投递
+	if subscription == nil || !subscription.Active {
+		delivery.Fail(nil, "webhook is inactive", nil)
+		return true, w.deliveries.Save(delivery)
+	}
+
+	statusCode, err := w.send(ctx, subscription, delivery)
+
+	if err == nil {
+		delivery.Succeed(statusCode, time.Now())
+		return true, w.deliveries.Save(delivery)
+	}
+
+	var code *int
+
+	if statusCode != 0 {
+		code = &statusCode
+	}
+
+	var next *time.Time
+
+	if delivery.Attempts+1 < w.options.MaxAttempts {
+		at := time.Now().Add(w.backoff(delivery.Attempts + 1))
+		next = &at
+	}
+
+	delivery.Fail(code, err.Error(), next)
+
+	w.log.Warn("failed to deliver webhook",
+		zap.Stringer("delivery_id", delivery.ID),
+		zap.Stringer("webhook_id", subscription.ID),
+		zap.String("event", delivery.EventName),
+		zap.Int("attempts", delivery.Attempts),
+		zap.String("status", delivery.Status),
+		zap.Error(err))
+
+	return false, w.deliveries.Save(delivery)
+}
+
+// send 发送请求,返回响应状态码;非 2xx 响应同样返回错误
+func (w *Worker) send(ctx context.Context, subscription *webhook.Subscription, delivery *webhook.Delivery) (int, error) {
+
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.EventName)
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, body))
+
+	resp, err := w.client.Do(req)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	// 读完响应体以便复用连接
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (w *Worker) backoff(attempts int) time.Duration {
+
+	delay := w.options.BaseBackoff
+
+	for i := 1; i < attempts && delay < w.options.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, w.options.MaxBackoff)
+}
@@ -0,0 +1,391 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+	"workit-sample/internal/todo/infrastructure/persistence"
+
+	"github.com/google/uuid"
+	"github.com/xiaohangshuhub/go-workit/pkg/ddd"
+	"go.uber.org/zap"
+)
+
+const secret = "s3cr3t"
+
+// receiver 记录收到的请求并按 status 响应
+type receiver struct {
+	*httptest.Server
+	status   atomic.Int32
+	requests atomic.Int32
+	last     atomic.Pointer[http.Request]
+	body     atomic.Pointer[[]byte]
+}
+
+func newReceiver(t *testing.T, status int) *receiver {
+	t.Helper()
+
+	r := &receiver{}
+	r.status.Store(int32(status))
+
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.body.Store(&body)
+		r.last.Store(req)
+		r.requests.Add(1)
+		w.WriteHeader(int(r.status.Load()))
+	}))
+
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+type fixture struct {
+	worker        *Worker
+	subscriptions *persistence.MemorySubscriptionRepository
+	deliveries    *persistence.MemoryDeliveryRepository
+	subscription  uuid.UUID
+	delivery      *webhook.Delivery
+}
+
+// newFixture 创建指向 receiver 的订阅与一条待投递记录。
+// 测试服务器监听本机地址,使用测试服务器的客户端绕过地址校验
+func newFixture(t *testing.T, r *receiver, options Options) *fixture {
+	t.Helper()
+
+	deliveries := persistence.NewMemoryDeliveryRepository()
+	subscriptions := persistence.NewMemorySubscriptionRepository(deliveries)
+
+	f := &fixture{
+		worker:        NewWorker(subscriptions, deliveries, options, zap.NewNop()).WithClient(r.Client()),
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+	}
+
+	f.subscription = f.subscribe(t, r.URL+"/hook")
+	f.delivery = f.enqueue(t, f.subscription)
+
+	return f
+}
+
+// subscribe 创建指向 url 的订阅
+func (f *fixture) subscribe(t *testing.T, url string) uuid.UUID {
+	t.Helper()
+
+	subscription := &webhook.Subscription{
+		BaseAggregateRoot: ddd.NewBaseAggregateRoot(uuid.New()),
+		TenantID:          todo.DefaultTenant,
+		OwnerID:           "alice",
+		URL:               url,
+		Secret:            secret,
+		Active:            true,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+
+	if err := f.subscriptions.Save(subscription); err != nil {
+		t.Fatal(err)
+	}
+
+	return subscription.ID
+}
+
+// enqueue 为订阅创建一条待投递记录
+func (f *fixture) enqueue(t *testing.T, subscriptionID uuid.UUID) *webhook.Delivery {
+	t.Helper()
+
+	delivery := webhook.NewDelivery(subscriptionID, uuid.New(), todo.EventTodoCreated, `{"title":"Buy milk"}`)
+
+	if err := f.deliveries.Save(delivery); err != nil {
+		t.Fatal(err)
+	}
+
+	return delivery
+}
+
+func (f *fixture) deliverOnce(t *testing.T) *webhook.Delivery {
+	t.Helper()
+
+	if err := f.worker.DeliverOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	delivery, err := f.deliveries.Get(f.delivery.ID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return delivery
+}
+
+// due 让投递立即到期,模拟退避时间已过
+func (f *fixture) due(t *testing.T, delivery *webhook.Delivery) {
+	t.Helper()
+
+	delivery.NextAttemptAt = time.Now()
+
+	if err := f.deliveries.Save(delivery); err != nil {
+		t.Fatal(err)
+	}
+}
+
+var testOptions = Options{
+	BatchSize:   10,
+	Timeout:     time.Second,
+	MaxAttempts: 3,
+	BaseBackoff: time.Minute,
+	MaxBackoff:  time.Hour,
+	Concurrency: 4,
+	Lease:       time.Minute,
+}
+
+func TestDeliverySignsPayload(t *testing.T) {
+
+	r := newReceiver(t, http.StatusNoContent)
+	f := newFixture(t, r, testOptions)
+
+	delivery := f.deliverOnce(t)
+
+	if delivery.Status != webhook.DeliverySucceeded || *delivery.LastStatusCode != http.StatusNoContent {
+		t.Fatalf("expected delivery to succeed, got %s %v", delivery.Status, delivery.LastStatusCode)
+	}
+
+	req, body := r.last.Load(), *r.body.Load()
+
+	// 接收方按文档独立计算签名
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := req.Header.Get(HeaderSignature); got != expected {
+		t.Fatalf("expected signature %s, got %s", expected, got)
+	}
+
+	if string(body) != `{"title":"Buy milk"}` {
+		t.Fatalf("unexpected body %s", body)
+	}
+
+	if req.Header.Get(HeaderEvent) != todo.EventTodoCreated || req.Header.Get(HeaderDelivery) != delivery.ID.String() {
+		t.Fatalf("unexpected headers %v", req.Header)
+	}
+}
+
+func TestDeliveryRetriesWithBackoff(t *testing.T) {
+
+	r := newReceiver(t, http.StatusInternalServerError)
+	f := newFixture(t, r, testOptions)
+
+	before := time.Now()
+	delivery := f.deliverOnce(t)
+
+	if delivery.Status != webhook.DeliveryPending || delivery.Attempts != 1 || *delivery.LastStatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected pending retry after first failure, got %+v", delivery)
+	}
+
+	if wait := delivery.NextAttemptAt.Sub(before); wait < time.Minute || wait > time.Minute+5*time.Second {
+		t.Fatalf("expected first retry after base backoff, got %s", wait)
+	}
+
+	// 退避期间不重试
+	f.deliverOnce(t)
+
+	if n := r.requests.Load(); n != 1 {
+		t.Fatalf("expected 1 request during backoff, got %d", n)
+	}
+
+	f.due(t, delivery)
+
+	before = time.Now()
+	delivery = f.deliverOnce(t)
+
+	if wait := delivery.NextAttemptAt.Sub(before); delivery.Attempts != 2 || wait < 2*time.Minute || wait > 2*time.Minute+5*time.Second {
+		t.Fatalf("expected second retry after doubled backoff, got attempts %d, wait %s", delivery.Attempts, wait)
+	}
+
+	// 对方恢复后投递成功
+	r.status.Store(http.StatusOK)
+	f.due(t, delivery)
+
+	if delivery = f.deliverOnce(t); delivery.Status != webhook.DeliverySucceeded || delivery.LastError != nil {
+		t.Fatalf("expected delivery to succeed after recovery, got %+v", delivery)
+	}
+}
+
+func TestDeliveryFailsAfterMaxAttempts(t *testing.T) {
+
+	r := newReceiver(t, http.StatusBadGateway)
+	f := newFixture(t, r, testOptions)
+
+	var delivery *webhook.Delivery
+
+	for range testOptions.MaxAttempts {
+		delivery = f.deliverOnce(t)
+		f.due(t, delivery)
+	}
+
+	if delivery.Status != webhook.DeliveryFailed || delivery.Attempts != testOptions.MaxAttempts {
+		t.Fatalf("expected failed after %d attempts, got %s after %d", testOptions.MaxAttempts, delivery.Status, delivery.Attempts)
+	}
+
+	f.deliverOnce(t)
+
+	if n := r.requests.Load(); n != int32(testOptions.MaxAttempts) {
+		t.Fatalf("expected no more requests after giving up, got %d", n)
+	}
+}
+
+// 接收方失败后本批次不再发送同一订阅的其余投递,其余投递在租约到期前不会被再次领取
+func TestFailingReceiverSkipsRestOfBatch(t *testing.T) {
+
+	r := newReceiver(t, http.StatusInternalServerError)
+	f := newFixture(t, r, testOptions)
+
+	rest := []*webhook.Delivery{f.enqueue(t, f.subscription), f.enqueue(t, f.subscription)}
+
+	f.deliverOnce(t)
+	f.deliverOnce(t)
+
+	if n := r.requests.Load(); n != 1 {
+		t.Fatalf("expected 1 request to a failing receiver, got %d", n)
+	}
+
+	for _, delivery := range rest {
+
+		delivery, err := f.deliveries.Get(delivery.ID)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if delivery.Status != webhook.DeliveryPending || delivery.Attempts != 0 {
+			t.Fatalf("expected untouched pending delivery, got %+v", delivery)
+		}
+	}
+}
+
+// 慢速接收方不影响其他订阅的投递
+func TestSlowReceiverDoesNotBlockOthers(t *testing.T) {
+
+	fast := newReceiver(t, http.StatusOK)
+
+	options := testOptions
+	options.Timeout = 10 * time.Second
+
+	f := newFixture(t, fast, options)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+	}))
+
+	t.Cleanup(slow.Close)
+
+	// 慢速订阅的投递先到期
+	delivery := f.enqueue(t, f.subscribe(t, slow.URL+"/hook"))
+	delivery.NextAttemptAt = delivery.NextAttemptAt.Add(-time.Minute)
+	f.due(t, delivery)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- f.worker.DeliverOnce(context.Background())
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+
+	for fast.requests.Load() == 0 {
+
+		if time.Now().After(deadline) {
+			t.Fatal("expected fast receiver to be called while the slow one is pending")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	release <- struct{}{}
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 多个实例共享存储时每条投递只发送一次
+func TestWorkersClaimEachDeliveryOnce(t *testing.T) {
+
+	r := newReceiver(t, http.StatusOK)
+	f := newFixture(t, r, testOptions)
+
+	for range 3 {
+
+		subscriptionID := f.subscribe(t, r.URL+"/hook")
+
+		for range 5 {
+			f.enqueue(t, subscriptionID)
+		}
+	}
+
+	options := testOptions
+	options.BatchSize = 3
+
+	var wg sync.WaitGroup
+
+	for range 3 {
+
+		worker := NewWorker(f.subscriptions, f.deliveries, options, zap.NewNop()).WithClient(r.Client())
+
+		wg.Go(func() {
+			for range 3 {
+				if err := worker.DeliverOnce(context.Background()); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if n := r.requests.Load(); n != 16 {
+		t.Fatalf("expected each of 16 deliveries sent once, got %d requests", n)
+	}
+}
+
+// 域名解析到本机或内网地址时,在建立连接前拒绝
+func TestClientRefusesPrivateDestinations(t *testing.T) {
+
+	r := newReceiver(t, http.StatusOK)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, r.URL, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = newClient(time.Second).Do(req)
+
+	if !errors.Is(err, ErrDestinationNotAllowed) {
+		t.Fatalf("expected ErrDestinationNotAllowed, got %v", err)
+	}
+
+	if n := r.requests.Load(); n != 0 {
+		t.Fatalf("request must not reach a loopback server, got %d", n)
+	}
+}
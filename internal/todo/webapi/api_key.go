@@ -0,0 +1,133 @@
+package webapi
+
+import (
+	"workit-sample/internal/todo/application/auth"
+	"workit-sample/internal/todo/domain/identity"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func RegisterApiKeyRoutes(
+	router *gin.Engine, //gin
+	log *zap.Logger, // 日志
+	command *auth.ApiKeyCommandHandler, // 创建与吊销
+	list *auth.ApiKeyListQueryHandler, // 列表
+) {
+
+	group := router.Group("/api-keys", rejectApiKey())
+
+	group.POST("", CreateApiKeyHandler(command, log))
+	group.GET("", ApiKeyListQueryHandler(list, log))
+	group.DELETE("/:id", RevokeApiKeyHandler(command, log))
+}
+
+// rejectApiKey 只允许通过登录令牌管理 API Key,避免泄露的 API Key 用于创建新的 API Key
+func rejectApiKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		if viaApiKey(c) {
+			FailWithError(c, actionAuthorize, identity.ErrApiKeyNotAllowed)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CreateApiKeyHandler godoc
+// @Summary 创建API Key
+// @Description 为当前用户创建个人 API Key,供脚本与 CI 调用接口。请求时放在 X-API-Key 请求头中,
+// @Description 身份为当前用户,每次请求按当前用户最新的角色授权,用户被移除后 API Key 随之失效;read 只能发起查询请求,write 可以修改。明文仅在创建时返回
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param data body auth.CreateApiKeyCommand true "请求参数"
+// @Success 200 {object} Response[auth.ApiKeyDTO]
+// @Failure 400 {object} Response[any]
+// @Failure 403 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /api-keys [post]
+func CreateApiKeyHandler(handler *auth.ApiKeyCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var cmd auth.CreateApiKeyCommand
+
+		if err := c.ShouldBindJSON(&cmd); err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.Principal = userOf(c)
+
+		result, err := handler.Create(cmd)
+
+		if err != nil {
+			log.Error("create api key error", zap.Error(err))
+			FailWithError(c, actionCreate, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// ApiKeyListQueryHandler godoc
+// @Summary 查询API Key列表
+// @Description 查询当前用户的所有 API Key,包括已吊销的,不返回明文
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Success 200 {object} Response[[]auth.ApiKeyDTO]
+// @Failure 403 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /api-keys [get]
+func ApiKeyListQueryHandler(handler *auth.ApiKeyListQueryHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		result, err := handler.Handle(userOf(c))
+		if err != nil {
+			log.Error("query api keys error", zap.Error(err))
+			FailWithError(c, actionQuery, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// RevokeApiKeyHandler godoc
+// @Summary 吊销API Key
+// @Description 吊销当前用户的 API Key,吊销后立即失效
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path string true "API Key ID"
+// @Success 200 {object} Response[auth.ApiKeyDTO]
+// @Failure 400 {object} Response[any]
+// @Failure 403 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /api-keys/{id} [delete]
+func RevokeApiKeyHandler(handler *auth.ApiKeyCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var cmd auth.RevokeApiKeyCommand
+
+		if err := c.ShouldBindUri(&cmd); err != nil {
+			log.Error("uri bind error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.Principal = userOf(c)
+
+		result, err := handler.Revoke(cmd)
+		if err != nil {
+			log.Error("revoke api key error", zap.Error(err))
+			FailWithError(c, actionRevokeApiKey, err)
+			return
+		}
+		Success(c, result)
+	}
+}
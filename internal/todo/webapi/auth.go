@@ -0,0 +1,126 @@
+package webapi
+
+import (
+	"net/http"
+
+	"workit-sample/internal/todo/application/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshuhub/go-workit/pkg/workit"
+	"go.uber.org/zap"
+)
+
+// AnonymousRoutes 令牌接口,无需携带访问令牌
+var AnonymousRoutes = []workit.Route{
+	{Path: "/auth/token", Methods: []workit.RequestMethod{workit.POST}},
+	{Path: "/auth/revoke", Methods: []workit.RequestMethod{workit.POST}},
+	{Path: "/.well-known/jwks.json", Methods: []workit.RequestMethod{workit.GET}},
+}
+
+func RegisterAuthRoutes(
+	router *gin.Engine, //gin
+	log *zap.Logger, // 日志
+	token *auth.TokenCommandHandler, // 签发令牌
+	revoke *auth.RevokeTokenCommandHandler, // 吊销令牌
+	jwks *auth.JwksQueryHandler, // 校验公钥
+) {
+
+	group := router.Group("/auth")
+
+	group.POST("/token", TokenHandler(token, log))
+	group.POST("/revoke", RevokeTokenHandler(revoke, log))
+
+	router.GET("/.well-known/jwks.json", JwksHandler(jwks))
+}
+
+// TokenHandler godoc
+// @Summary 签发令牌
+// @Description 支持 password、client_credentials 与 refresh_token 三种授权类型,请求体可为 JSON 或表单。
+// @Description 客户端凭据也可通过 Basic 认证传递。刷新令牌每次使用后轮换,旧令牌再次使用时吊销同一次登录签发的所有令牌
+// @Tags Auth
+// @Accept json,x-www-form-urlencoded
+// @Produce json
+// @Param data body auth.TokenCommand true "请求参数"
+// @Success 200 {object} Response[auth.TokenDTO]
+// @Failure 400 {object} Response[any]
+// @Failure 401 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /auth/token [post]
+func TokenHandler(handler *auth.TokenCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var cmd auth.TokenCommand
+
+		if err := c.ShouldBind(&cmd); err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		if id, secret, ok := c.Request.BasicAuth(); ok && cmd.ClientID == "" {
+			cmd.ClientID, cmd.ClientSecret = id, secret
+		}
+
+		result, err := handler.Handle(cmd)
+
+		if err != nil {
+			log.Error("issue token error", zap.Error(err))
+			FailWithError(c, actionIssueToken, err)
+			return
+		}
+
+		// 令牌响应不允许缓存 (RFC 6749 5.1)
+		c.Header("Cache-Control", "no-store")
+		Success(c, result)
+	}
+}
+
+// RevokeTokenHandler godoc
+// @Summary 吊销刷新令牌
+// @Description 吊销刷新令牌及同一次登录签发的所有刷新令牌,令牌无效时同样返回成功
+// @Tags Auth
+// @Accept json,x-www-form-urlencoded
+// @Produce json
+// @Param data body auth.RevokeTokenCommand true "请求参数"
+// @Success 200 {object} Response[bool]
+// @Failure 400 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /auth/revoke [post]
+func RevokeTokenHandler(handler *auth.RevokeTokenCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var cmd auth.RevokeTokenCommand
+
+		if err := c.ShouldBind(&cmd); err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		result, err := handler.Handle(cmd)
+
+		if err != nil {
+			log.Error("revoke token error", zap.Error(err))
+			FailWithError(c, actionRevokeToken, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// JwksHandler godoc
+// @Summary 查询校验公钥
+// @Description 以 JWKS (RFC 7517) 格式返回访问令牌的校验公钥,供其他服务按令牌头部的 kid 校验令牌。
+// @Description 响应不使用统一的响应结构,以便 JWT 库直接读取;使用 HS256 签名时返回空集合
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} auth.JSONWebKeySet
+// @Router /.well-known/jwks.json [get]
+func JwksHandler(handler *auth.JwksQueryHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		// 密钥轮换后校验方需要及时获取新公钥,缓存时间不宜过长
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, handler.Handle())
+	}
+}
@@ -0,0 +1,48 @@
+package webapi
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// 校验错误中的字段名使用请求中的参数名,而非结构体字段名
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+// requestFieldName 依次取 json, form, uri 标签中的参数名
+func requestFieldName(field reflect.StructField) string {
+
+	for _, tag := range []string{"json", "form", "uri"} {
+
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// shouldBindUriAndJSON 同时绑定路径参数与 JSON 请求体, 并在两者都绑定完成后统一校验
+func shouldBindUriAndJSON(c *gin.Context, obj any) error {
+
+	params := make(map[string][]string, len(c.Params))
+
+	for _, p := range c.Params {
+		params[p.Key] = []string{p.Value}
+	}
+
+	if err := binding.MapFormWithTag(obj, params, "uri"); err != nil {
+		return err
+	}
+
+	return c.ShouldBindJSON(obj)
+}
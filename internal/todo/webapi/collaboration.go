@@ -0,0 +1,247 @@
+package webapi
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"workit-sample/internal/todo/application/todo"
+	"workit-sample/internal/todo/domain/identity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	// collaborationWriteTimeout 单条消息的写超时
+	collaborationWriteTimeout = 10 * time.Second
+	// collaborationPongTimeout 超过该时间未收到客户端响应视为断开
+	collaborationPongTimeout = 60 * time.Second
+	// collaborationPingInterval 心跳间隔,需小于 collaborationPongTimeout
+	collaborationPingInterval = 30 * time.Second
+	// collaborationMaxMessageSize 客户端单条消息的最大字节数
+	collaborationMaxMessageSize = 64 << 10
+)
+
+// AllowedOrigins 允许跨域访问的前端来源,对应 application.yaml 中的 web.allowed_origins
+type AllowedOrigins []string
+
+// allow WebSocket 不受跨域策略限制,握手时校验 Origin:
+// 未携带 Origin 的非浏览器客户端与同源请求放行,其余来源必须在配置中
+func (o AllowedOrigins) allow(r *http.Request) bool {
+
+	origin := r.Header.Get("Origin")
+
+	if origin == "" {
+		return true
+	}
+
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+
+	return slices.ContainsFunc(o, func(allowed string) bool {
+		return strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin)
+	})
+}
+
+func RegisterCollaborationRoutes(
+	router *gin.Engine, //gin
+	log *zap.Logger, // 日志
+	origins AllowedOrigins, // 允许的前端来源
+	hub *todo.CollaborationHub, // 协作房间
+	collaborate *todo.CollaborationCommandHandler, // 协作操作
+	todoQuery *todo.TodoQueryHandler, // 查询
+) {
+
+	upgrader := &websocket.Upgrader{
+		ReadBufferSize:  4096,
+		WriteBufferSize: 4096,
+		Subprotocols:    []string{identity.WebSocketProtocol},
+		CheckOrigin:     origins.allow,
+	}
+
+	router.GET("/todos/:id/collaborate", CollaborationHandler(upgrader, hub, collaborate, todoQuery, log))
+}
+
+// CollaborationHandler godoc
+// @Summary 协作编辑Todo
+// @Description 升级为 WebSocket 连接,加入指定待办事项的协作房间。
+// @Description 连接后推送 snapshot(当前状态),之后推送 presence(在线用户)与 event(领域事件,包括通过 HTTP 接口产生的变更)。
+// @Description 客户端发送 todo.CollaborationOperation,服务端以 ack 或 rejected 回应,与当前状态冲突或角色不足(viewer 只能查看)的操作被拒绝。
+// @Description 浏览器无法为 WebSocket 设置 Authorization 请求头,握手时通过子协议传递访问令牌:new WebSocket(url, ["bearer", accessToken]),
+// @Description 即请求头 Sec-WebSocket-Protocol: bearer, <access_token>,服务端回应 bearer 子协议。跨域握手的 Origin 必须在 web.allowed_origins 中
+// @Tags Todos
+// @Param id path string true "待办事项ID"
+// @Param Sec-WebSocket-Protocol header string false "bearer, <access_token>,用于无法设置 Authorization 请求头的浏览器"
+// @Success 101 {object} todo.CollaborationMessage "切换为 WebSocket"
+// @Failure 400 {object} Response[any]
+// @Failure 403 {string} string "Origin 不在允许的来源中"
+// @Failure 404 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /todos/{id}/collaborate [get]
+func CollaborationHandler(upgrader *websocket.Upgrader, hub *todo.CollaborationHub, collaborate *todo.CollaborationCommandHandler, todoQuery *todo.TodoQueryHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var query todo.TodoQuery
+
+		if err := c.ShouldBindUri(&query); err != nil {
+			log.Error("uri bind error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		query.Principal = userOf(c)
+
+		// 握手前确认待办事项存在且当前用户可以访问,否则按普通接口返回错误
+		snapshot, err := todoQuery.Handle(query)
+
+		if err != nil {
+			log.Error("query error", zap.Error(err))
+			FailWithError(c, actionQuery, err)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+
+		if err != nil {
+			// Upgrade 已向客户端返回错误
+			log.Error("websocket upgrade error", zap.Error(err))
+			return
+		}
+
+		defer conn.Close()
+
+		participant := hub.Join(snapshot, viewerOf(c))
+		defer hub.Leave(participant)
+
+		go writeCollaboration(conn, participant, log)
+
+		readCollaboration(c, conn, participant, collaborate, todoQuery, query, log)
+	}
+}
+
+// viewerOf 取当前用户作为在线用户,未认证时为匿名用户
+func viewerOf(c *gin.Context) todo.Viewer {
+
+	if principal := principalOf(c); principal != nil && principal.Subject != "" {
+		return todo.Viewer{UserID: principal.Subject, Name: principal.Name}
+	}
+
+	id := "anonymous-" + uuid.NewString()
+
+	return todo.Viewer{UserID: id, Name: "anonymous"}
+}
+
+// readCollaboration 读取并执行客户端操作,连接断开时返回
+func readCollaboration(c *gin.Context, conn *websocket.Conn, participant *todo.Participant, collaborate *todo.CollaborationCommandHandler, todoQuery *todo.TodoQueryHandler, query todo.TodoQuery, log *zap.Logger) {
+
+	conn.SetReadLimit(collaborationMaxMessageSize)
+	_ = conn.SetReadDeadline(time.Now().Add(collaborationPongTimeout))
+
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(collaborationPongTimeout))
+	})
+
+	todoID := uuid.MustParse(query.ID)
+
+	for {
+		var op todo.CollaborationOperation
+
+		if err := conn.ReadJSON(&op); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Warn("websocket read error", zap.Error(err))
+			}
+			return
+		}
+
+		if err := binding.Validator.ValidateStruct(&op); err != nil {
+			participant.Send(todo.CollaborationMessage{
+				Type:      todo.MessageRejected,
+				RequestID: op.RequestID,
+				Code:      ErrorCodeInvalidArgument,
+				Message:   localizeValidation(c, err),
+			})
+			continue
+		}
+
+		op.Principal = query.Principal
+
+		if op.Type == todo.OperationSnapshot {
+
+			snapshot, err := todoQuery.Handle(query)
+
+			if err != nil {
+				log.Error("query error", zap.Error(err))
+				participant.Send(rejected(c, op, err))
+				continue
+			}
+
+			participant.Send(todo.CollaborationMessage{Type: todo.MessageSnapshot, RequestID: op.RequestID, Todo: snapshot})
+			continue
+		}
+
+		if err := collaborate.Handle(todoID, op); err != nil {
+			log.Warn("collaboration operation rejected", zap.String("type", op.Type), zap.Error(err))
+			participant.Send(rejected(c, op, err))
+			continue
+		}
+
+		participant.Send(todo.CollaborationMessage{Type: todo.MessageAck, RequestID: op.RequestID})
+	}
+}
+
+func rejected(c *gin.Context, op todo.CollaborationOperation, err error) todo.CollaborationMessage {
+
+	_, errorCode := translateError(err)
+
+	return todo.CollaborationMessage{
+		Type:      todo.MessageRejected,
+		RequestID: op.RequestID,
+		Code:      errorCode,
+		Message:   localizeError(c, err),
+	}
+}
+
+// writeCollaboration 发送房间消息与心跳,房间关闭该连接后结束并关闭连接
+func writeCollaboration(conn *websocket.Conn, participant *todo.Participant, log *zap.Logger) {
+
+	ping := time.NewTicker(collaborationPingInterval)
+
+	defer func() {
+		ping.Stop()
+		// 关闭连接以结束读取
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-participant.Messages():
+
+			_ = conn.SetWriteDeadline(time.Now().Add(collaborationWriteTimeout))
+
+			if !ok {
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+				return
+			}
+
+			if err := conn.WriteJSON(message); err != nil {
+				log.Warn("websocket write error", zap.Error(err))
+				return
+			}
+
+		case <-ping.C:
+
+			_ = conn.SetWriteDeadline(time.Now().Add(collaborationWriteTimeout))
+
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package webapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"workit-sample/internal/todo/application/todo"
+	domain "workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/infrastructure/eventbus"
+	"workit-sample/internal/todo/infrastructure/outbox"
+	"workit-sample/internal/todo/infrastructure/persistence"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// newCollaborationServer 以 alice 身份提供协作接口,返回 alice 与 bob 各自的待办事项
+func newCollaborationServer(t *testing.T) (server *httptest.Server, own, others uuid.UUID) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	log := zap.NewNop()
+	repo := persistence.NewMemoryTodoRepository()
+	deliveries := persistence.NewMemoryDeliveryRepository()
+	uow := persistence.NewMemoryUnitOfWork(repo, outbox.NewMemoryStore(), persistence.NewMemorySubscriptionRepository(deliveries), deliveries, eventbus.NewInProcessDispatcher(eventbus.DispatcherParams{Log: log}))
+
+	save := func(owner domain.Principal) uuid.UUID {
+
+		entity, err := domain.NewTodo(uuid.New(), owner, "Groceries")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.Save(entity); err != nil {
+			t.Fatal(err)
+		}
+
+		return entity.ID
+	}
+
+	own = save(alice)
+	others = save(domain.Principal{TenantID: domain.DefaultTenant, UserID: "bob"})
+
+	collaborate := todo.NewCollaborationCommandHandler(
+		repo,
+		todo.NewAddTodoTaskCommandHandler(uow, log),
+		todo.NewMarkAsCompletedCommandHandler(uow, log),
+		todo.NewRemoveTodoTaskCommandHandler(uow, log),
+		log,
+	)
+
+	router := gin.New()
+	router.Use(withUser(alice))
+
+	RegisterCollaborationRoutes(router, log, AllowedOrigins{"http://app.example.com"}, todo.NewCollaborationHub(log), collaborate, todo.NewTodoQueryHandler(repo, log))
+
+	server = httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server, own, others
+}
+
+func TestCollaborationHandshake(t *testing.T) {
+
+	server, own, others := newCollaborationServer(t)
+
+	base := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	cases := []struct {
+		name   string
+		todoID uuid.UUID
+		origin string
+		status int // 握手失败时的状态码, 0 表示成功
+	}{
+		{"allowed origin", own, "http://app.example.com", 0},
+		{"same origin", own, server.URL, 0},
+		{"no origin", own, "", 0},
+		{"disallowed origin", own, "http://evil.example.com", http.StatusForbidden},
+		{"todo of another user", others, "http://app.example.com", http.StatusNotFound},
+	}
+
+	for _, tt := range cases {
+
+		header := http.Header{}
+
+		if tt.origin != "" {
+			header.Set("Origin", tt.origin)
+		}
+
+		conn, resp, err := websocket.DefaultDialer.Dial(base+"/todos/"+tt.todoID.String()+"/collaborate", header)
+
+		if tt.status != 0 {
+			if err == nil {
+				conn.Close()
+				t.Errorf("%s: expected handshake to fail", tt.name)
+			} else if resp == nil || resp.StatusCode != tt.status {
+				t.Errorf("%s: expected status %d, got %v", tt.name, tt.status, resp)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+
+		var message todo.CollaborationMessage
+
+		if err := conn.ReadJSON(&message); err != nil || message.Type != todo.MessageSnapshot || message.Todo.ID != own {
+			t.Errorf("%s: expected snapshot first, got %+v, %v", tt.name, message, err)
+		}
+
+		conn.Close()
+	}
+}
@@ -0,0 +1,65 @@
+package webapi
+
+import (
+	"errors"
+	"net/http"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"gorm.io/gorm"
+)
+
+// 通用错误码,领域错误使用 TodoError 自带的错误码
+const (
+	ErrorCodeInvalidArgument = "INVALID_ARGUMENT"
+	ErrorCodeUnauthorized    = "UNAUTHORIZED"
+	ErrorCodeNotFound        = "NOT_FOUND"
+	ErrorCodeForbidden       = "FORBIDDEN"
+	ErrorCodeConflict        = "CONFLICT"
+	ErrorCodeInternal        = "INTERNAL_ERROR"
+)
+
+// translateError 将领域错误与数据访问错误翻译为 HTTP 状态码和错误码,未识别的错误视为 500
+func translateError(err error) (int, string) {
+
+	var todoErr todo.TodoError
+
+	if errors.As(err, &todoErr) {
+		switch todoErr.Kind {
+		case todo.KindValidation:
+			return http.StatusBadRequest, todoErr.Code
+		case todo.KindNotFound:
+			return http.StatusNotFound, todoErr.Code
+		case todo.KindConflict:
+			return http.StatusConflict, todoErr.Code
+		case todo.KindForbidden:
+			return http.StatusForbidden, todoErr.Code
+		case todo.KindUnauthorized:
+			return http.StatusUnauthorized, todoErr.Code
+		}
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusNotFound, ErrorCodeNotFound
+	}
+
+	return http.StatusInternalServerError, ErrorCodeInternal
+}
+
+// statusErrorCode 按状态码返回通用错误码
+func statusErrorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrorCodeInvalidArgument
+	case http.StatusUnauthorized:
+		return ErrorCodeUnauthorized
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusForbidden:
+		return ErrorCodeForbidden
+	case http.StatusConflict:
+		return ErrorCodeConflict
+	default:
+		return ErrorCodeInternal
+	}
+}
@@ -0,0 +1,89 @@
+package webapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"workit-sample/internal/todo/application/todo"
+	"workit-sample/internal/todo/domain/identity"
+	domain "workit-sample/internal/todo/domain/todo"
+	"workit-sample/internal/todo/domain/webhook"
+
+	"gorm.io/gorm"
+)
+
+// domainErrors 所有对外返回的领域错误及其期望的状态码
+var domainErrors = []struct {
+	err    domain.TodoError
+	status int
+}{
+	{domain.ErrEmptyTodoTitle, http.StatusBadRequest},
+	{domain.ErrTodoAlreadyExists, http.StatusConflict},
+	{domain.ErrTodoNotFound, http.StatusNotFound},
+	{domain.ErrEmptyTaskTitle, http.StatusBadRequest},
+	{domain.ErrTaskNotFound, http.StatusNotFound},
+	{domain.ErrTaskTitleExists, http.StatusConflict},
+	{domain.ErrInvalidTimeZone, http.StatusBadRequest},
+	{domain.ErrTaskDueAfterTodo, http.StatusBadRequest},
+	{domain.ErrInvalidRecurrence, http.StatusBadRequest},
+	{domain.ErrRecurrenceRequiresDue, http.StatusBadRequest},
+	{domain.ErrTaskAlreadyCompleted, http.StatusConflict},
+	{domain.ErrTodoForbidden, http.StatusForbidden},
+	{domain.ErrInvalidRole, http.StatusBadRequest},
+	{domain.ErrMemberUserEmpty, http.StatusBadRequest},
+	{domain.ErrMemberAlreadyExists, http.StatusConflict},
+	{domain.ErrMemberNotFound, http.StatusNotFound},
+	{domain.ErrOwnerRoleImmutable, http.StatusBadRequest},
+	{domain.ErrTodoVersionConflict, http.StatusConflict},
+	{todo.ErrInvalidCursor, http.StatusBadRequest},
+	{errInvalidIfMatch, http.StatusBadRequest},
+	{webhook.ErrWebhookNotFound, http.StatusNotFound},
+	{webhook.ErrInvalidWebhookURL, http.StatusBadRequest},
+	{webhook.ErrUnknownEvent, http.StatusBadRequest},
+	{webhook.ErrDeliveryNotFound, http.StatusNotFound},
+	{webhook.ErrWebhookURLNotAllowed, http.StatusBadRequest},
+	{identity.ErrInvalidCredentials, http.StatusUnauthorized},
+	{identity.ErrInvalidClient, http.StatusUnauthorized},
+	{identity.ErrInvalidGrant, http.StatusBadRequest},
+	{identity.ErrUserNotFound, http.StatusNotFound},
+	{identity.ErrClientNotFound, http.StatusNotFound},
+	{identity.ErrRefreshTokenReused, http.StatusBadRequest},
+	{identity.ErrApiKeyNotFound, http.StatusNotFound},
+	{identity.ErrApiKeyNameEmpty, http.StatusBadRequest},
+	{identity.ErrInvalidScope, http.StatusBadRequest},
+	{identity.ErrInvalidApiKeyExpiry, http.StatusBadRequest},
+	{identity.ErrInsufficientScope, http.StatusForbidden},
+	{identity.ErrApiKeyNotAllowed, http.StatusForbidden},
+}
+
+func TestTranslateError(t *testing.T) {
+
+	for _, tt := range domainErrors {
+
+		// 包装后的错误同样按领域错误翻译
+		for _, err := range []error{tt.err, fmt.Errorf("save todo: %w", tt.err)} {
+			if status, code := translateError(err); status != tt.status || code != tt.err.Code {
+				t.Errorf("%v: expected %d %s, got %d %s", err, tt.status, tt.err.Code, status, code)
+			}
+		}
+	}
+
+	others := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{gorm.ErrRecordNotFound, http.StatusNotFound, ErrorCodeNotFound},
+		{fmt.Errorf("query: %w", gorm.ErrRecordNotFound), http.StatusNotFound, ErrorCodeNotFound},
+		{errors.New("connection refused"), http.StatusInternalServerError, ErrorCodeInternal},
+		{domain.TodoError{Code: "UNKNOWN_KIND"}, http.StatusInternalServerError, ErrorCodeInternal},
+	}
+
+	for _, tt := range others {
+		if status, code := translateError(tt.err); status != tt.status || code != tt.code {
+			t.Errorf("%v: expected %d %s, got %d %s", tt.err, tt.status, tt.code, status, code)
+		}
+	}
+}
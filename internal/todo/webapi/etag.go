@@ -0,0 +1,36 @@
+package webapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidIfMatch = todo.TodoError{Code: "INVALID_IF_MATCH", Kind: todo.KindValidation, Message: "If-Match 请求头格式错误"}
+
+// ifMatchVersion 解析 If-Match 请求头中的版本号,未携带或为 * 时返回 nil
+func ifMatchVersion(c *gin.Context) (*int64, error) {
+
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(value, "W/"), `"`), 10, 64)
+
+	if err != nil {
+		return nil, errInvalidIfMatch
+	}
+
+	return &version, nil
+}
+
+// setETag 以版本号作为 ETag 返回
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
@@ -0,0 +1,141 @@
+package webapi
+
+import (
+	"errors"
+	"strings"
+
+	"workit-sample/internal/todo/domain/todo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
+	"golang.org/x/text/language"
+)
+
+// 支持的语言,未匹配时使用简体中文
+const (
+	LocaleZhCN = "zh-CN"
+	LocaleEn   = "en"
+)
+
+const localeContextKey = "locale"
+
+var (
+	// 与 supportedLocales 顺序一致,第一个为默认语言
+	localeMatcher    = language.NewMatcher([]language.Tag{language.SimplifiedChinese, language.English})
+	supportedLocales = []string{LocaleZhCN, LocaleEn}
+
+	// 各语言的校验错误翻译器
+	validatorTranslators = map[string]ut.Translator{}
+)
+
+func init() {
+
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+
+	if !ok {
+		return
+	}
+
+	uni := ut.New(zh.New(), zh.New(), en.New())
+
+	zhTrans, _ := uni.GetTranslator("zh")
+	enTrans, _ := uni.GetTranslator("en")
+
+	if err := zh_translations.RegisterDefaultTranslations(v, zhTrans); err != nil {
+		panic(err)
+	}
+
+	if err := en_translations.RegisterDefaultTranslations(v, enTrans); err != nil {
+		panic(err)
+	}
+
+	validatorTranslators[LocaleZhCN] = zhTrans
+	validatorTranslators[LocaleEn] = enTrans
+}
+
+// localeOf 根据 Accept-Language 解析请求语言,结果缓存在请求上下文中
+func localeOf(c *gin.Context) string {
+
+	if locale := c.GetString(localeContextKey); locale != "" {
+		return locale
+	}
+
+	tags, _, _ := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+
+	_, index, confidence := localeMatcher.Match(tags...)
+
+	// 不支持的语言可能被近似匹配到英文,此时仍使用默认语言
+	if confidence == language.No {
+		index = 0
+	}
+
+	locale := supportedLocales[index]
+
+	c.Set(localeContextKey, locale)
+
+	return locale
+}
+
+// translate 按请求语言取消息,缺失时回退到默认语言,仍缺失时返回 key
+func translate(c *gin.Context, key string) string {
+
+	if message, ok := messages[localeOf(c)][key]; ok {
+		return message
+	}
+
+	if message, ok := messages[LocaleZhCN][key]; ok {
+		return message
+	}
+
+	return key
+}
+
+// localizeError 领域错误按错误码翻译,其余错误原样返回
+func localizeError(c *gin.Context, err error) string {
+
+	var todoErr todo.TodoError
+
+	if errors.As(err, &todoErr) {
+		if message, ok := messages[localeOf(c)][todoErr.Code]; ok {
+			return message
+		}
+		return todoErr.Message
+	}
+
+	return err.Error()
+}
+
+// localizeValidation 翻译绑定错误,校验错误逐字段翻译
+func localizeValidation(c *gin.Context, err error) string {
+
+	var validationErrs validator.ValidationErrors
+
+	if !errors.As(err, &validationErrs) {
+		return localizeError(c, err)
+	}
+
+	trans := validatorTranslators[localeOf(c)]
+
+	parts := make([]string, len(validationErrs))
+
+	for i, fe := range validationErrs {
+		parts[i] = translateFieldError(fe, trans)
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func translateFieldError(fe validator.FieldError, trans ut.Translator) string {
+
+	if trans == nil {
+		return fe.Error()
+	}
+
+	return fe.Translate(trans)
+}
@@ -0,0 +1,87 @@
+package webapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+func TestLocalizedMessages(t *testing.T) {
+
+	router := newQueryRouter()
+	missing := "/todos/" + uuid.NewString()
+
+	cases := []struct {
+		name     string
+		path     string
+		language string
+		want     string // 消息前缀
+		chinese  bool
+	}{
+		{"default", missing, "", "查询失败: 待办事项未找到", true},
+		{"zh-CN", missing, "zh-CN", "查询失败: 待办事项未找到", true},
+		{"en", missing, "en", "query failed: todo not found", false},
+		{"en-US with fallback", missing, "en-US,en;q=0.9,zh;q=0.5", "query failed: todo not found", false},
+		{"zh preferred", missing, "zh;q=0.9,en;q=0.8", "查询失败: 待办事项未找到", true},
+		{"unsupported", missing, "fr-FR", "查询失败: 待办事项未找到", true},
+		{"validation zh-CN", "/todos?size=-1", "zh-CN", "参数错误: ", true},
+		{"validation en", "/todos?size=-1", "en", "invalid argument: ", false},
+	}
+
+	for _, tt := range cases {
+
+		w := get(router, tt.path, "Accept-Language", tt.language)
+
+		var response Response[any]
+
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if !strings.HasPrefix(response.Message, tt.want) || containsHan(response.Message) != tt.chinese {
+			t.Errorf("%s: expected message starting with %q, got %q", tt.name, tt.want, response.Message)
+		}
+	}
+
+	// problem+json 的详情与字段错误同样按请求语言翻译
+	w := get(router, "/todos?size=-1", "Accept", MIMEProblemJSON, "Accept-Language", "en")
+
+	var problem ProblemDetails
+
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(problem.Errors) != 1 || containsHan(problem.Detail) || containsHan(problem.Errors[0].Message) {
+		t.Errorf("expected english problem details, got %+v", problem)
+	}
+}
+
+// 每种语言都翻译了相同的消息键
+func TestMessageCatalogsAreComplete(t *testing.T) {
+
+	for key := range messages[LocaleZhCN] {
+		if _, ok := messages[LocaleEn][key]; !ok {
+			t.Errorf("missing %s message for %s", LocaleEn, key)
+		}
+	}
+
+	for key := range messages[LocaleEn] {
+		if _, ok := messages[LocaleZhCN][key]; !ok {
+			t.Errorf("missing %s message for %s", LocaleZhCN, key)
+		}
+	}
+
+	for _, tt := range domainErrors {
+		if _, ok := messages[LocaleEn][tt.err.Code]; !ok {
+			t.Errorf("missing %s message for %s", LocaleEn, tt.err.Code)
+		}
+	}
+}
+
+func containsHan(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return unicode.Is(unicode.Han, r) }) >= 0
+}
@@ -0,0 +1,199 @@
+package webapi
+
+import (
+	"workit-sample/internal/todo/application/todo"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func RegisterMemberRoutes(
+	router *gin.Engine, //gin
+	log *zap.Logger, // 日志
+	members *todo.MemberCommandHandler, // 成员管理
+	memberList *todo.MemberListQueryHandler, // 成员列表
+) {
+
+	group := router.Group("/todos/:id/members")
+
+	group.GET("", MemberListQueryHandler(memberList, log))
+	group.POST("", InviteMemberHandler(members, log))
+	group.PUT("/:userId", ChangeMemberRoleHandler(members, log))
+	group.DELETE("/:userId", RevokeMemberHandler(members, log))
+}
+
+// MemberListQueryHandler godoc
+// @Summary 查询Todo成员
+// @Description 返回创建者与共享成员,创建者排在最前。viewer 及以上角色可以查询
+// @Tags Members
+// @Produce json
+// @Param id path string true "待办事项ID"
+// @Success 200 {object} Response[[]todo.MemberDTO]
+// @Failure 400 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /todos/{id}/members [get]
+func MemberListQueryHandler(handler *todo.MemberListQueryHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var query todo.MemberListQuery
+
+		if err := c.ShouldBindUri(&query); err != nil {
+			log.Error("uri bind error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		query.Principal = userOf(c)
+
+		result, err := handler.Handle(query)
+		if err != nil {
+			log.Error("query members error", zap.Error(err))
+			FailWithError(c, actionQuery, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// InviteMemberHandler godoc
+// @Summary 邀请成员
+// @Description 将待办事项共享给同一租户内的用户。viewer 可以查看,editor 可以修改待办事项与任务,owner 还可以删除待办事项与管理成员。
+// @Description 只有 owner 可以邀请
+// @Tags Members
+// @Accept json
+// @Produce json
+// @Param id path string true "待办事项ID"
+// @Param data body todo.InviteMemberCommand true "请求参数"
+// @Param If-Match header string false "期望的版本号,取自查询返回的 ETag"
+// @Success 200 {object} Response[bool]
+// @Failure 400 {object} Response[any]
+// @Failure 403 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 409 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /todos/{id}/members [post]
+func InviteMemberHandler(handler *todo.MemberCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var cmd todo.InviteMemberCommand
+
+		if err := shouldBindUriAndJSON(c, &cmd); err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		version, err := ifMatchVersion(c)
+
+		if err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.ExpectedVersion = version
+		cmd.Principal = userOf(c)
+
+		result, err := handler.Invite(cmd)
+		if err != nil {
+			log.Error("invite member error", zap.Error(err))
+			FailWithError(c, actionInviteMember, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// ChangeMemberRoleHandler godoc
+// @Summary 修改成员角色
+// @Description 修改共享成员的角色,创建者的角色不能修改。只有 owner 可以修改
+// @Tags Members
+// @Accept json
+// @Produce json
+// @Param id path string true "待办事项ID"
+// @Param userId path string true "成员的用户ID"
+// @Param data body todo.ChangeMemberRoleCommand true "请求参数"
+// @Param If-Match header string false "期望的版本号,取自查询返回的 ETag"
+// @Success 200 {object} Response[bool]
+// @Failure 400 {object} Response[any]
+// @Failure 403 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 409 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /todos/{id}/members/{userId} [put]
+func ChangeMemberRoleHandler(handler *todo.MemberCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var cmd todo.ChangeMemberRoleCommand
+
+		if err := shouldBindUriAndJSON(c, &cmd); err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		version, err := ifMatchVersion(c)
+
+		if err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.ExpectedVersion = version
+		cmd.Principal = userOf(c)
+
+		result, err := handler.ChangeRole(cmd)
+		if err != nil {
+			log.Error("change member role error", zap.Error(err))
+			FailWithError(c, actionChangeRole, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// RevokeMemberHandler godoc
+// @Summary 移除成员
+// @Description 取消对该用户的共享,创建者不能被移除。只有 owner 可以移除
+// @Tags Members
+// @Produce json
+// @Param id path string true "待办事项ID"
+// @Param userId path string true "成员的用户ID"
+// @Param If-Match header string false "期望的版本号,取自查询返回的 ETag"
+// @Success 200 {object} Response[bool]
+// @Failure 400 {object} Response[any]
+// @Failure 403 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 409 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /todos/{id}/members/{userId} [delete]
+func RevokeMemberHandler(handler *todo.MemberCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var cmd todo.RevokeMemberCommand
+
+		if err := c.ShouldBindUri(&cmd); err != nil {
+			log.Error("uri bind error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		version, err := ifMatchVersion(c)
+
+		if err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.ExpectedVersion = version
+		cmd.Principal = userOf(c)
+
+		result, err := handler.Revoke(cmd)
+		if err != nil {
+			log.Error("revoke member error", zap.Error(err))
+			FailWithError(c, actionRevokeMember, err)
+			return
+		}
+		Success(c, result)
+	}
+}
@@ -0,0 +1,158 @@
+package webapi
+
+// 失败动作的消息键,与错误详情拼接为 "<动作>: <原因>"
+const (
+	actionCreate          = "action.create"
+	actionQuery           = "action.query"
+	actionAddTask         = "action.add_task"
+	actionMarkAsCompleted = "action.mark_as_completed"
+	actionUpdate          = "action.update"
+	actionDelete          = "action.delete"
+	actionRemoveTask      = "action.remove_task"
+	actionRedeliver       = "action.redeliver"
+	actionInviteMember    = "action.invite_member"
+	actionChangeRole      = "action.change_member_role"
+	actionRevokeMember    = "action.revoke_member"
+	actionIssueToken      = "action.issue_token"
+	actionRevokeToken     = "action.revoke_token"
+	actionAuthorize       = "action.authorize"
+	actionRevokeApiKey    = "action.revoke_api_key"
+)
+
+// messageTypeMismatch 字段类型错误,参数为期望的类型
+const messageTypeMismatch = "field.type_mismatch"
+
+// messages 消息目录,按语言和消息键(错误码或动作)组织
+var messages = map[string]map[string]string{
+	LocaleZhCN: {
+		// 领域错误
+		"TODO_TITLE_EMPTY":        "待办事项标题不能为空",
+		"TODO_ALREADY_EXISTS":     "待办事项已存在",
+		"TODO_NOT_FOUND":          "待办事项未找到",
+		"TASK_TITLE_EMPTY":        "任务标题不能为空",
+		"TASK_NOT_FOUND":          "任务未找到",
+		"TASK_TITLE_EXISTS":       "任务标题已存在",
+		"TASK_ALREADY_COMPLETED":  "任务已完成",
+		"INVALID_TIME_ZONE":       "无效的时区",
+		"TASK_DUE_AFTER_TODO":     "任务截止时间不能晚于待办事项截止时间",
+		"INVALID_RECURRENCE":      "无效的重复规则",
+		"RECURRENCE_REQUIRES_DUE": "重复的待办事项必须设置截止时间",
+		"TODO_VERSION_CONFLICT":   "待办事项已被修改,请刷新后重试",
+		"TODO_FORBIDDEN":          "没有权限执行该操作",
+		"INVALID_ROLE":            "无效的成员角色",
+		"MEMBER_USER_EMPTY":       "成员用户不能为空",
+		"MEMBER_ALREADY_EXISTS":   "用户已是成员",
+		"MEMBER_NOT_FOUND":        "成员未找到",
+		"OWNER_ROLE_IMMUTABLE":    "不能修改或移除创建者",
+		"INVALID_CURSOR":          "游标格式错误",
+		"INVALID_IF_MATCH":        "If-Match 请求头格式错误",
+		"WEBHOOK_NOT_FOUND":       "Webhook 未找到",
+		"INVALID_WEBHOOK_URL":     "Webhook 地址必须是 http 或 https 绝对地址",
+		"WEBHOOK_URL_NOT_ALLOWED": "Webhook 地址不能指向本机或内网地址",
+		"UNKNOWN_WEBHOOK_EVENT":   "不支持的 Webhook 事件",
+		"DELIVERY_NOT_FOUND":      "投递记录未找到",
+		"INVALID_CREDENTIALS":     "用户名或密码错误",
+		"INVALID_CLIENT":          "客户端认证失败",
+		"INVALID_GRANT":           "刷新令牌无效、已过期或已吊销",
+		"REFRESH_TOKEN_REUSED":    "刷新令牌已被使用",
+		"USER_NOT_FOUND":          "用户未找到",
+		"CLIENT_NOT_FOUND":        "客户端未找到",
+		"API_KEY_NOT_FOUND":       "API Key 未找到",
+		"API_KEY_NAME_EMPTY":      "API Key 名称不能为空",
+		"INVALID_SCOPE":           "无效的权限范围",
+		"INVALID_API_KEY_EXPIRY":  "过期时间必须晚于当前时间",
+		"INSUFFICIENT_SCOPE":      "API Key 只有读取权限",
+		"API_KEY_NOT_ALLOWED":     "不能使用 API Key 管理 API Key",
+
+		// 通用错误
+		ErrorCodeInvalidArgument: "参数错误",
+		ErrorCodeUnauthorized:    "未认证",
+		ErrorCodeNotFound:        "资源不存在",
+		ErrorCodeForbidden:       "没有权限",
+		ErrorCodeConflict:        "数据冲突",
+		ErrorCodeInternal:        "服务器内部错误",
+
+		// 动作
+		actionCreate:          "创建失败",
+		actionQuery:           "查询失败",
+		actionAddTask:         "添加任务失败",
+		actionMarkAsCompleted: "标记完成失败",
+		actionUpdate:          "更新失败",
+		actionDelete:          "删除失败",
+		actionRemoveTask:      "删除任务失败",
+		actionRedeliver:       "重新投递失败",
+		actionInviteMember:    "邀请成员失败",
+		actionChangeRole:      "修改成员角色失败",
+		actionRevokeMember:    "移除成员失败",
+		actionIssueToken:      "签发令牌失败",
+		actionRevokeToken:     "吊销令牌失败",
+		actionAuthorize:       "授权失败",
+		actionRevokeApiKey:    "吊销 API Key 失败",
+
+		messageTypeMismatch: "类型错误,应为 %s",
+	},
+	LocaleEn: {
+		"TODO_TITLE_EMPTY":        "todo title must not be empty",
+		"TODO_ALREADY_EXISTS":     "todo already exists",
+		"TODO_NOT_FOUND":          "todo not found",
+		"TASK_TITLE_EMPTY":        "task title must not be empty",
+		"TASK_NOT_FOUND":          "task not found",
+		"TASK_TITLE_EXISTS":       "task title already exists",
+		"TASK_ALREADY_COMPLETED":  "task is already completed",
+		"INVALID_TIME_ZONE":       "invalid time zone",
+		"TASK_DUE_AFTER_TODO":     "task due date must not be after the todo's due date",
+		"INVALID_RECURRENCE":      "invalid recurrence rule",
+		"RECURRENCE_REQUIRES_DUE": "a recurring todo must have a due date",
+		"TODO_VERSION_CONFLICT":   "todo has been modified, please refresh and retry",
+		"TODO_FORBIDDEN":          "you do not have permission to perform this operation",
+		"INVALID_ROLE":            "invalid member role",
+		"MEMBER_USER_EMPTY":       "member user must not be empty",
+		"MEMBER_ALREADY_EXISTS":   "user is already a member",
+		"MEMBER_NOT_FOUND":        "member not found",
+		"OWNER_ROLE_IMMUTABLE":    "the creator's role cannot be changed or revoked",
+		"INVALID_CURSOR":          "invalid cursor",
+		"INVALID_IF_MATCH":        "invalid If-Match header",
+		"WEBHOOK_NOT_FOUND":       "webhook not found",
+		"INVALID_WEBHOOK_URL":     "webhook url must be an absolute http or https url",
+		"WEBHOOK_URL_NOT_ALLOWED": "webhook url must not point to a loopback or private address",
+		"UNKNOWN_WEBHOOK_EVENT":   "unsupported webhook event",
+		"DELIVERY_NOT_FOUND":      "delivery not found",
+		"INVALID_CREDENTIALS":     "invalid username or password",
+		"INVALID_CLIENT":          "client authentication failed",
+		"INVALID_GRANT":           "refresh token is invalid, expired or revoked",
+		"REFRESH_TOKEN_REUSED":    "refresh token has already been used",
+		"USER_NOT_FOUND":          "user not found",
+		"CLIENT_NOT_FOUND":        "client not found",
+		"API_KEY_NOT_FOUND":       "api key not found",
+		"API_KEY_NAME_EMPTY":      "api key name must not be empty",
+		"INVALID_SCOPE":           "invalid scope",
+		"INVALID_API_KEY_EXPIRY":  "expiry must be in the future",
+		"INSUFFICIENT_SCOPE":      "the api key only has read access",
+		"API_KEY_NOT_ALLOWED":     "api keys cannot be used to manage api keys",
+
+		ErrorCodeInvalidArgument: "invalid argument",
+		ErrorCodeUnauthorized:    "unauthorized",
+		ErrorCodeNotFound:        "resource not found",
+		ErrorCodeForbidden:       "forbidden",
+		ErrorCodeConflict:        "conflict",
+		ErrorCodeInternal:        "internal server error",
+
+		actionCreate:          "create failed",
+		actionQuery:           "query failed",
+		actionAddTask:         "add task failed",
+		actionMarkAsCompleted: "mark as completed failed",
+		actionUpdate:          "update failed",
+		actionDelete:          "delete failed",
+		actionRemoveTask:      "remove task failed",
+		actionRedeliver:       "redeliver failed",
+		actionInviteMember:    "invite member failed",
+		actionChangeRole:      "change member role failed",
+		actionRevokeMember:    "revoke member failed",
+		actionIssueToken:      "issue token failed",
+		actionRevokeToken:     "revoke token failed",
+		actionAuthorize:       "authorization failed",
+		actionRevokeApiKey:    "revoke api key failed",
+
+		messageTypeMismatch: "must be of type %s",
+	},
+}
@@ -0,0 +1,121 @@
+package webapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshuhub/go-workit/pkg/workit"
+
+	"workit-sample/internal/todo/domain/identity"
+	"workit-sample/internal/todo/domain/todo"
+)
+
+const (
+	// claimsContextKey 认证中间件保存身份信息的上下文键
+	claimsContextKey = "claims"
+	// tenantClaim 身份信息中的租户声明
+	tenantClaim = "tenant_id"
+	// userContextKey 当前用户的上下文键
+	userContextKey = "todo.user"
+)
+
+// SupportPolicy 判断当前用户是否为技术支持管理员,管理员可绕过共享权限访问同一租户内的待办事项
+type SupportPolicy func(principal *workit.ClaimsPrincipal) bool
+
+// PrincipalMiddleware 解析当前用户并保存到上下文,需在认证中间件之后注册
+type PrincipalMiddleware struct {
+	support SupportPolicy
+}
+
+func NewPrincipalMiddleware(support SupportPolicy) *PrincipalMiddleware {
+	return &PrincipalMiddleware{
+		support: support,
+	}
+}
+
+func (m *PrincipalMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		principal := principalOf(c)
+		user := resolveUser(principal)
+
+		if principal != nil && m.support != nil {
+			user.Admin = m.support(principal)
+		}
+
+		// 只读 API Key 只能发起查询请求
+		if user.ReadOnly && !safeMethod(c.Request.Method) {
+			FailWithError(c, actionAuthorize, identity.ErrInsufficientScope)
+			c.Abort()
+			return
+		}
+
+		c.Set(userContextKey, user)
+		c.Next()
+	}
+}
+
+func (m *PrincipalMiddleware) ShouldSkip(path string, method string) bool {
+	return false
+}
+
+// principalOf 返回当前请求的身份信息,未认证时返回 nil
+func principalOf(c *gin.Context) *workit.ClaimsPrincipal {
+
+	value, ok := c.Get(claimsContextKey)
+
+	if !ok {
+		return nil
+	}
+
+	principal, _ := value.(*workit.ClaimsPrincipal)
+
+	return principal
+}
+
+// userOf 返回当前用户,用于确定数据归属与访问范围
+func userOf(c *gin.Context) todo.Principal {
+
+	if user, ok := c.Get(userContextKey); ok {
+		return user.(todo.Principal)
+	}
+
+	return resolveUser(principalOf(c))
+}
+
+// resolveUser 由身份信息得到当前用户,身份信息中没有租户时使用默认租户
+func resolveUser(principal *workit.ClaimsPrincipal) todo.Principal {
+
+	user := todo.Principal{TenantID: todo.DefaultTenant}
+
+	if principal == nil {
+		return user
+	}
+
+	user.UserID = principal.Subject
+
+	if tenant, ok := principal.FindFirst(tenantClaim); ok {
+		if tenantID, ok := tenant.(string); ok && tenantID != "" {
+			user.TenantID = tenantID
+		}
+	}
+
+	if scope, ok := principal.FindFirst(identity.ClaimScope); ok && principal.AuthenticationMethod == identity.MethodApiKey {
+		user.ReadOnly = scope != identity.ScopeWrite
+	}
+
+	return user
+}
+
+// viaApiKey 当前请求是否使用 API Key 认证
+func viaApiKey(c *gin.Context) bool {
+
+	principal := principalOf(c)
+
+	return principal != nil && principal.AuthenticationMethod == identity.MethodApiKey
+}
+
+// safeMethod 是否为不修改数据的请求方法
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,90 @@
+package webapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// MIMEProblemJSON RFC 7807 问题详情的媒体类型
+	MIMEProblemJSON = "application/problem+json"
+
+	problemTypePrefix = "urn:todo:problem:"
+)
+
+// ProblemDetails RFC 7807 问题详情
+type ProblemDetails struct {
+	Type     string       `json:"type" example:"urn:todo:problem:TODO_NOT_FOUND"`                 // 问题类型
+	Title    string       `json:"title" example:"Not Found"`                                      // 问题概述
+	Status   int          `json:"status" example:"404"`                                           // HTTP 状态码
+	Detail   string       `json:"detail" example:"查询失败: 待办事项未找到"`                                 // 问题详情
+	Instance string       `json:"instance" example:"/todos/b19e6f4c-3d51-4f7e-9a6e-f32d28a3f111"` // 出错的请求路径
+	Code     string       `json:"code" example:"TODO_NOT_FOUND"`                                  // 稳定的错误码
+	Errors   []FieldError `json:"errors,omitempty"`                                               // 字段校验错误
+}
+
+// FieldError 字段级校验错误
+type FieldError struct {
+	Field   string `json:"field" example:"size"`           // 字段名
+	Message string `json:"message" example:"size必须大于或等于0"` // 错误说明
+}
+
+// wantsProblem 客户端通过 Accept 请求 problem+json 时返回 true
+func wantsProblem(c *gin.Context) bool {
+	return c.NegotiateFormat(binding.MIMEJSON, MIMEProblemJSON) == MIMEProblemJSON
+}
+
+// problem 以 problem+json 格式返回失败
+func problem(c *gin.Context, status int, errorCode, detail string, fields []FieldError) {
+
+	c.Header("Content-Type", MIMEProblemJSON)
+
+	c.JSON(status, ProblemDetails{
+		Type:     problemTypePrefix + errorCode,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Code:     errorCode,
+		Errors:   fields,
+	})
+}
+
+// fieldErrors 从绑定错误中提取字段级错误,说明按请求语言翻译
+func fieldErrors(c *gin.Context, err error) []FieldError {
+
+	var validationErrs validator.ValidationErrors
+
+	if errors.As(err, &validationErrs) {
+
+		trans := validatorTranslators[localeOf(c)]
+
+		fields := make([]FieldError, len(validationErrs))
+
+		for i, fe := range validationErrs {
+			fields[i] = FieldError{
+				Field:   fe.Field(),
+				Message: translateFieldError(fe, trans),
+			}
+		}
+
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+
+	if errors.As(err, &typeErr) {
+		return []FieldError{{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf(translate(c, messageTypeMismatch), typeErr.Type),
+		}}
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package webapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"workit-sample/internal/todo/application/todo"
+	"workit-sample/internal/todo/infrastructure/persistence"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// newQueryRouter 提供待办事项查询接口,仓储为空
+func newQueryRouter() *gin.Engine {
+
+	gin.SetMode(gin.TestMode)
+
+	log := zap.NewNop()
+	repo := persistence.NewMemoryTodoRepository()
+
+	router := gin.New()
+	router.Use(withUser(alice))
+	router.GET("/todos", TodoListQueryHandler(todo.NewTodoListQueryHandler(repo, log), log))
+	router.GET("/todos/:id", TodoQueryHandler(todo.NewTodoQueryHandler(repo, log), log))
+
+	return router
+}
+
+// get 发起请求, headers 依次为名称与值
+func get(router *gin.Engine, path string, headers ...string) *httptest.ResponseRecorder {
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestProblemDetails(t *testing.T) {
+
+	router := newQueryRouter()
+	missing := "/todos/" + uuid.NewString()
+
+	cases := []struct {
+		name   string
+		path   string
+		status int
+		code   string
+		fields bool // 是否附带字段级错误
+	}{
+		{"domain error", missing, http.StatusNotFound, "TODO_NOT_FOUND", false},
+		{"invalid cursor", "/todos?cursor=garbage", http.StatusBadRequest, "INVALID_CURSOR", false},
+		{"invalid uri", "/todos/not-a-uuid", http.StatusBadRequest, ErrorCodeInvalidArgument, true},
+		{"invalid query", "/todos?size=-1", http.StatusBadRequest, ErrorCodeInvalidArgument, true},
+	}
+
+	for _, tt := range cases {
+
+		w := get(router, tt.path, "Accept", MIMEProblemJSON)
+
+		if w.Code != tt.status || w.Header().Get("Content-Type") != MIMEProblemJSON {
+			t.Errorf("%s: expected %d %s, got %d %s", tt.name, tt.status, MIMEProblemJSON, w.Code, w.Header().Get("Content-Type"))
+			continue
+		}
+
+		var problem ProblemDetails
+
+		if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		instance, _, _ := strings.Cut(tt.path, "?")
+
+		if problem.Type != problemTypePrefix+tt.code || problem.Code != tt.code || problem.Status != tt.status ||
+			problem.Title != http.StatusText(tt.status) || problem.Detail == "" || problem.Instance != instance {
+			t.Errorf("%s: unexpected problem %+v", tt.name, problem)
+		}
+
+		if (len(problem.Errors) > 0) != tt.fields {
+			t.Errorf("%s: expected field errors %v, got %+v", tt.name, tt.fields, problem.Errors)
+		}
+	}
+}
+
+// 未请求 problem+json 时保持原有的响应格式
+func TestProblemDetailsOnlyWhenAccepted(t *testing.T) {
+
+	router := newQueryRouter()
+	missing := "/todos/" + uuid.NewString()
+
+	for _, accept := range []string{"", "application/json", "*/*", "application/json, application/problem+json;q=0.5"} {
+
+		w := get(router, missing, "Accept", accept)
+
+		var response Response[any]
+
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%q: %v", accept, err)
+		}
+
+		if w.Code != http.StatusNotFound || w.Header().Get("Content-Type") == MIMEProblemJSON ||
+			response.Code != http.StatusNotFound || response.ErrorCode != "TODO_NOT_FOUND" {
+			t.Errorf("%q: unexpected response %d %s", accept, w.Code, w.Body.String())
+		}
+	}
+
+	// 成功响应不受 Accept 影响
+	if w := get(router, "/todos", "Accept", MIMEProblemJSON); w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d %s", w.Code, w.Body.String())
+	}
+}
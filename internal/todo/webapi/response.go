@@ -8,9 +8,10 @@ import (
 
 // ResponseWithData 用来在Swagger里指定Data的具体类型
 type Response[T any] struct {
-	Code    int    `json:"code"`    // 响应码
-	Message string `json:"message"` // 响应消息
-	Data    T      `json:"data"`    // 响应数据
+	Code      int    `json:"code"`                // 响应码
+	ErrorCode string `json:"errorCode,omitempty"` // 稳定的错误码,仅失败时返回
+	Message   string `json:"message"`             // 响应消息
+	Data      T      `json:"data"`                // 响应数据
 
 }
 
@@ -23,11 +24,51 @@ func Success[T any](c *gin.Context, data T) {
 	})
 }
 
-// Fail 返回失败
+// Fail 返回失败,错误码按状态码取通用值
 func Fail(c *gin.Context, code int, message string) {
+
+	if wantsProblem(c) {
+		problem(c, code, statusErrorCode(code), message, nil)
+		return
+	}
+
 	c.JSON(code, Response[any]{
-		Code:    code,
-		Message: message,
-		Data:    nil,
+		Code:      code,
+		ErrorCode: statusErrorCode(code),
+		Message:   message,
+		Data:      nil,
+	})
+}
+
+// FailWithError 将错误翻译为状态码与错误码后返回失败, action 为动作的消息键
+func FailWithError(c *gin.Context, action string, err error) {
+
+	status, errorCode := translateError(err)
+
+	message := translate(c, action) + ": " + localizeError(c, err)
+
+	if wantsProblem(c) {
+		problem(c, status, errorCode, message, nil)
+		return
+	}
+
+	c.JSON(status, Response[any]{
+		Code:      status,
+		ErrorCode: errorCode,
+		Message:   message,
+		Data:      nil,
 	})
 }
+
+// FailWithValidation 返回参数绑定或校验失败, problem+json 格式下附带字段级错误
+func FailWithValidation(c *gin.Context, err error) {
+
+	message := translate(c, ErrorCodeInvalidArgument) + ": " + localizeValidation(c, err)
+
+	if wantsProblem(c) {
+		problem(c, http.StatusBadRequest, ErrorCodeInvalidArgument, message, fieldErrors(c, err))
+		return
+	}
+
+	Fail(c, http.StatusBadRequest, message)
+}
@@ -14,7 +14,11 @@ func RegisterTodoRoutes(
 	todoList *todo.TodoListQueryHandler, //列表
 	addTask *todo.AddTodoTaskCommandHandler, //添加任务
 	todoQuery *todo.TodoQueryHandler, //查询
-	markAsCompleted *todo.MarkAsCompletedCommandHandler, //查询
+	markAsCompleted *todo.MarkAsCompletedCommandHandler, //标记完成
+	update *todo.UpdateTodoCommandHandler, //更新
+	deleteTodo *todo.DeleteTodoCommandHandler, //删除
+	removeTask *todo.RemoveTodoTaskCommandHandler, //删除任务
+	removeTasks *todo.RemoveTodoTasksCommandHandler, //批量删除任务
 ) {
 
 	// 创建路由组
@@ -26,6 +30,10 @@ func RegisterTodoRoutes(
 	group.POST("/task", AddTodoTaskHandler(addTask, log))
 	group.GET("/:id", TodoQueryHandler(todoQuery, log))
 	group.POST("/completed", MarkAsCompletedHandler(markAsCompleted, log))
+	group.PUT("/:id", UpdateTodoHandler(update, log))
+	group.DELETE("/:id", DeleteTodoHandler(deleteTodo, log))
+	group.DELETE("/:id/tasks/:taskId", RemoveTodoTaskHandler(removeTask, log))
+	group.DELETE("/:id/tasks", RemoveTodoTasksHandler(removeTasks, log))
 }
 
 // CreateTodoHandler godoc
@@ -37,6 +45,7 @@ func RegisterTodoRoutes(
 // @Param data body todo.CreateTodoCommand true "请求参数"
 // @Success 200 {object} Response[todo.CreateTodoResult]
 // @Failure 400 {object} Response[any]
+// @Failure 409 {object} Response[any]
 // @Failure 500 {object} Response[any]
 // @Router /todos [post]
 func CreateTodoHandler(handler *todo.CreateTodoCommandHandler, log *zap.Logger) gin.HandlerFunc {
@@ -46,15 +55,17 @@ func CreateTodoHandler(handler *todo.CreateTodoCommandHandler, log *zap.Logger)
 
 		if err := c.ShouldBindJSON(&cmd); err != nil {
 			log.Error("params error", zap.Error(err))
-			Fail(c, 400, "参数错误: "+err.Error())
+			FailWithValidation(c, err)
 			return
 		}
 
+		cmd.Principal = userOf(c)
+
 		result, err := handler.Handle(cmd)
 
 		if err != nil {
 			log.Error("create error", zap.Error(err))
-			Fail(c, 500, "创建失败: "+err.Error())
+			FailWithError(c, actionCreate, err)
 			return
 		}
 		Success(c, result)
@@ -63,14 +74,18 @@ func CreateTodoHandler(handler *todo.CreateTodoCommandHandler, log *zap.Logger)
 
 // TodoListQueryHandler godoc
 // @Summary 查询Todo列表
-// @Description 查询所有匹配条件的待办事项
+// @Description 查询当前用户创建或被共享的、所有匹配条件的待办事项
 // @Tags Todos
 // @Accept json
 // @Produce json
-// @Param title query string false "任务标题"
-// @Param page query int false "页码"
-// @Param size query int false "每页大小"
-// @Success 200 {object} Response[[]todo.TodoDTO]
+// @Param title query string false "标题或描述关键词"
+// @Param page query int false "页码,默认1"
+// @Param size query int false "每页大小,默认10,最大100"
+// @Param mode query string false "分页模式" Enums(offset, cursor)
+// @Param cursor query string false "游标,取自上一次响应的 nextCursor 或 prevCursor"
+// @Param due query string false "截止时间筛选,只返回未完成的数据" Enums(overdue, today, week)
+// @Param tz query string false "计算今天与本周所用的 IANA 时区,默认 UTC"
+// @Success 200 {object} Response[todo.PagedResult[todo.TodoDTO]]
 // @Failure 400 {object} Response[any]
 // @Failure 500 {object} Response[any]
 // @Router /todos [get]
@@ -80,14 +95,16 @@ func TodoListQueryHandler(handler *todo.TodoListQueryHandler, log *zap.Logger) g
 
 		if err := c.ShouldBindQuery(&query); err != nil {
 			log.Error("params error", zap.Error(err))
-			Fail(c, 400, "参数错误: "+err.Error())
+			FailWithValidation(c, err)
 			return
 		}
 
+		query.Principal = userOf(c)
+
 		result, err := handler.Handle(query)
 		if err != nil {
 			log.Error("query error", zap.Error(err))
-			Fail(c, 500, "查询失败: "+err.Error())
+			FailWithError(c, actionQuery, err)
 			return
 		}
 		Success(c, result)
@@ -101,8 +118,12 @@ func TodoListQueryHandler(handler *todo.TodoListQueryHandler, log *zap.Logger) g
 // @Accept json
 // @Produce json
 // @Param data body todo.AddTodoTaskCommand true "请求参数"
+// @Param If-Match header string false "期望的版本号,取自查询返回的 ETag"
 // @Success 200 {object} Response[bool]
 // @Failure 400 {object} Response[any]
+// @Failure 403 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 409 {object} Response[any]
 // @Failure 500 {object} Response[any]
 // @Router /todos/task [post]
 func AddTodoTaskHandler(handler *todo.AddTodoTaskCommandHandler, log *zap.Logger) gin.HandlerFunc {
@@ -111,14 +132,25 @@ func AddTodoTaskHandler(handler *todo.AddTodoTaskCommandHandler, log *zap.Logger
 
 		if err := c.ShouldBindJSON(&cmd); err != nil {
 			log.Error("params error", zap.Error(err))
-			Fail(c, 400, "参数错误: "+err.Error())
+			FailWithValidation(c, err)
 			return
 		}
 
+		version, err := ifMatchVersion(c)
+
+		if err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.ExpectedVersion = version
+		cmd.Principal = userOf(c)
+
 		result, err := handler.Handle(cmd)
 		if err != nil {
 			log.Error("add task error", zap.Error(err))
-			Fail(c, 500, "添加任务失败: "+err.Error())
+			FailWithError(c, actionAddTask, err)
 			return
 		}
 		Success(c, result)
@@ -133,7 +165,9 @@ func AddTodoTaskHandler(handler *todo.AddTodoTaskCommandHandler, log *zap.Logger
 // @Produce json
 // @Param id path string true "待办事项ID"
 // @Success 200 {object} Response[todo.TodoDTO]
+// @Header 200 {string} ETag "版本号,修改时通过 If-Match 携带"
 // @Failure 400 {object} Response[any]
+// @Failure 404 {object} Response[any]
 // @Failure 500 {object} Response[any]
 // @Router /todos/{id} [get]
 func TodoQueryHandler(handler *todo.TodoQueryHandler, log *zap.Logger) gin.HandlerFunc {
@@ -143,16 +177,19 @@ func TodoQueryHandler(handler *todo.TodoQueryHandler, log *zap.Logger) gin.Handl
 
 		if err := c.ShouldBindUri(&query); err != nil {
 			log.Error("uri bind error", zap.Error(err))
-			Fail(c, 400, "参数错误: "+err.Error())
+			FailWithValidation(c, err)
 			return
 		}
 
+		query.Principal = userOf(c)
+
 		result, err := handler.Handle(query)
 		if err != nil {
 			log.Error("query error", zap.Error(err))
-			Fail(c, 500, "查询失败: "+err.Error())
+			FailWithError(c, actionQuery, err)
 			return
 		}
+		setETag(c, result.Version)
 		Success(c, result)
 	}
 }
@@ -164,8 +201,12 @@ func TodoQueryHandler(handler *todo.TodoQueryHandler, log *zap.Logger) gin.Handl
 // @Accept json
 // @Produce json
 // @Param data body todo.MarkAsCompletedCommand true "请求参数"
+// @Param If-Match header string false "期望的版本号,取自查询返回的 ETag"
 // @Success 200 {object} Response[bool]
 // @Failure 400 {object} Response[any]
+// @Failure 403 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 409 {object} Response[any]
 // @Failure 500 {object} Response[any]
 // @Router /todos/completed [post]
 func MarkAsCompletedHandler(handler *todo.MarkAsCompletedCommandHandler, log *zap.Logger) gin.HandlerFunc {
@@ -174,14 +215,212 @@ func MarkAsCompletedHandler(handler *todo.MarkAsCompletedCommandHandler, log *za
 
 		if err := c.ShouldBindJSON(&cmd); err != nil {
 			log.Error("params error", zap.Error(err))
-			Fail(c, 400, "参数错误: "+err.Error())
+			FailWithValidation(c, err)
+			return
+		}
+
+		version, err := ifMatchVersion(c)
+
+		if err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
 			return
 		}
 
+		cmd.ExpectedVersion = version
+		cmd.Principal = userOf(c)
+
 		result, err := handler.Handle(cmd)
 		if err != nil {
 			log.Error("mark as completed error", zap.Error(err))
-			Fail(c, 500, "标记完成失败: "+err.Error())
+			FailWithError(c, actionMarkAsCompleted, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// UpdateTodoHandler godoc
+// @Summary 更新Todo
+// @Description 更新指定ID的待办事项的标题、描述或完成状态
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param id path string true "待办事项ID"
+// @Param data body todo.UpdateTodoCommand true "请求参数"
+// @Param If-Match header string false "期望的版本号,取自查询返回的 ETag"
+// @Success 200 {object} Response[bool]
+// @Failure 400 {object} Response[any]
+// @Failure 403 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 409 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /todos/{id} [put]
+func UpdateTodoHandler(handler *todo.UpdateTodoCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var cmd todo.UpdateTodoCommand
+
+		if err := shouldBindUriAndJSON(c, &cmd); err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		version, err := ifMatchVersion(c)
+
+		if err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.ExpectedVersion = version
+		cmd.Principal = userOf(c)
+
+		result, err := handler.Handle(cmd)
+		if err != nil {
+			log.Error("update error", zap.Error(err))
+			FailWithError(c, actionUpdate, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// DeleteTodoHandler godoc
+// @Summary 删除Todo
+// @Description 删除指定ID的待办事项及其所有任务,需要 owner 角色
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param id path string true "待办事项ID"
+// @Param If-Match header string false "期望的版本号,取自查询返回的 ETag"
+// @Success 200 {object} Response[bool]
+// @Failure 400 {object} Response[any]
+// @Failure 403 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 409 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /todos/{id} [delete]
+func DeleteTodoHandler(handler *todo.DeleteTodoCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var cmd todo.DeleteTodoCommand
+
+		if err := c.ShouldBindUri(&cmd); err != nil {
+			log.Error("uri bind error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		version, err := ifMatchVersion(c)
+
+		if err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.ExpectedVersion = version
+		cmd.Principal = userOf(c)
+
+		result, err := handler.Handle(cmd)
+		if err != nil {
+			log.Error("delete error", zap.Error(err))
+			FailWithError(c, actionDelete, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// RemoveTodoTaskHandler godoc
+// @Summary 删除任务
+// @Description 删除指定待办事项中的单个任务
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param id path string true "待办事项ID"
+// @Param taskId path string true "任务ID"
+// @Param If-Match header string false "期望的版本号,取自查询返回的 ETag"
+// @Success 200 {object} Response[bool]
+// @Failure 400 {object} Response[any]
+// @Failure 403 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 409 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /todos/{id}/tasks/{taskId} [delete]
+func RemoveTodoTaskHandler(handler *todo.RemoveTodoTaskCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var cmd todo.RemoveTodoTaskCommand
+
+		if err := c.ShouldBindUri(&cmd); err != nil {
+			log.Error("uri bind error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		version, err := ifMatchVersion(c)
+
+		if err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.ExpectedVersion = version
+		cmd.Principal = userOf(c)
+
+		result, err := handler.Handle(cmd)
+		if err != nil {
+			log.Error("remove task error", zap.Error(err))
+			FailWithError(c, actionRemoveTask, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// RemoveTodoTasksHandler godoc
+// @Summary 批量删除任务
+// @Description 批量删除指定待办事项中的任务
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param id path string true "待办事项ID"
+// @Param data body todo.RemoveTodoTasksCommand true "请求参数"
+// @Param If-Match header string false "期望的版本号,取自查询返回的 ETag"
+// @Success 200 {object} Response[bool]
+// @Failure 400 {object} Response[any]
+// @Failure 403 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 409 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /todos/{id}/tasks [delete]
+func RemoveTodoTasksHandler(handler *todo.RemoveTodoTasksCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var cmd todo.RemoveTodoTasksCommand
+
+		if err := shouldBindUriAndJSON(c, &cmd); err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		version, err := ifMatchVersion(c)
+
+		if err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.ExpectedVersion = version
+		cmd.Principal = userOf(c)
+
+		result, err := handler.Handle(cmd)
+		if err != nil {
+			log.Error("remove tasks error", zap.Error(err))
+			FailWithError(c, actionRemoveTask, err)
 			return
 		}
 		Success(c, result)
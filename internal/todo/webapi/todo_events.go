@@ -0,0 +1,180 @@
+package webapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"workit-sample/internal/todo/application/todo"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	// eventStreamHeartbeat 心跳间隔,防止代理因空闲断开连接
+	eventStreamHeartbeat = 15 * time.Second
+	// eventStreamWriteTimeout 单次写入超时,覆盖服务器默认的整体写超时
+	eventStreamWriteTimeout = 10 * time.Second
+	// eventStreamRetry 建议客户端的重连间隔(毫秒)
+	eventStreamRetry = 3000
+	// eventReset 无法续传时发送的事件,客户端收到后应重新加载数据
+	eventReset = "reset"
+)
+
+// TodoEventsQuery 事件流参数
+type TodoEventsQuery struct {
+	TodoID      string `form:"todoId" binding:"omitempty,uuid"` // 只接收指定待办事项的事件
+	LastEventID string `form:"lastEventId"`                     // 续传位置,优先使用 Last-Event-ID 请求头
+}
+
+func RegisterTodoEventRoutes(
+	router *gin.Engine, //gin
+	lc fx.Lifecycle, // 生命周期
+	log *zap.Logger, // 日志
+	stream *todo.EventStream, // 事件流
+) {
+
+	// 框架先关闭 HTTP 服务再停止应用,关闭 HTTP 服务时会等待进行中的请求结束,
+	// 因此在 HTTP 服务开始关闭时就结束长连接,否则会阻塞优雅关闭
+	var once sync.Once
+
+	router.GET("/todos/events", closeOnShutdown(&once, stream.Close), TodoEventsHandler(stream, log))
+
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			stream.Close()
+			return nil
+		},
+	})
+}
+
+// closeOnShutdown 在第一个请求到达时向处理该请求的 HTTP 服务注册关闭回调
+func closeOnShutdown(once *sync.Once, fn func()) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		once.Do(func() {
+			if server, ok := c.Request.Context().Value(http.ServerContextKey).(*http.Server); ok {
+				server.RegisterOnShutdown(fn)
+			}
+		})
+
+		c.Next()
+	}
+}
+
+// TodoEventsHandler godoc
+// @Summary 订阅Todo变更
+// @Description 以 Server-Sent Events 推送当前用户的待办事项与任务的变更,事件名为领域事件名称(如 todo.created),数据为事件内容。
+// @Description 断线重连时通过 Last-Event-ID 续传,无法续传时推送 reset 事件,客户端应重新加载数据
+// @Tags Todos
+// @Produce text/event-stream
+// @Param todoId query string false "只接收指定待办事项的事件"
+// @Param Last-Event-ID header string false "最后收到的事件ID"
+// @Param lastEventId query string false "最后收到的事件ID,用于无法设置请求头的客户端"
+// @Success 200 {string} string "事件流"
+// @Failure 400 {object} Response[any]
+// @Router /todos/events [get]
+func TodoEventsHandler(stream *todo.EventStream, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var query TodoEventsQuery
+
+		if err := c.ShouldBindQuery(&query); err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		todoID := uuid.Nil
+
+		if query.TodoID != "" {
+			todoID = uuid.MustParse(query.TodoID)
+		}
+
+		lastEventID := c.GetHeader("Last-Event-ID")
+
+		if lastEventID == "" {
+			lastEventID = query.LastEventID
+		}
+
+		subscription, missed, resetID := stream.Subscribe(userOf(c), todoID, lastEventID)
+		defer subscription.Close()
+
+		c.Header("Content-Type", sse.ContentType)
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+
+		rc := http.NewResponseController(c.Writer)
+
+		// write 每次写入前延长写超时,返回 false 表示连接已不可用
+		write := func(fn func() error) bool {
+
+			if err := rc.SetWriteDeadline(time.Now().Add(eventStreamWriteTimeout)); err != nil {
+				log.Error("failed to set write deadline", zap.Error(err))
+				return false
+			}
+
+			if err := fn(); err != nil {
+				return false
+			}
+
+			return rc.Flush() == nil
+		}
+
+		render := func(event todo.StreamEvent) bool {
+			return write(func() error {
+				return sse.Encode(c.Writer, sse.Event{
+					Id:    event.ID,
+					Event: event.Event.Metadata().EventName,
+					Data:  event.Event,
+				})
+			})
+		}
+
+		ok := write(func() error {
+
+			// 只含 retry 字段的块不会触发客户端的事件
+			if _, err := c.Writer.WriteString("retry: " + strconv.Itoa(eventStreamRetry) + "\n\n"); err != nil {
+				return err
+			}
+
+			if resetID != "" {
+				return sse.Encode(c.Writer, sse.Event{Id: resetID, Event: eventReset, Data: lastEventID})
+			}
+
+			return nil
+		})
+
+		for _, event := range missed {
+			ok = ok && render(event)
+		}
+
+		heartbeat := time.NewTicker(eventStreamHeartbeat)
+		defer heartbeat.Stop()
+
+		for ok {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case event, open := <-subscription.Events():
+				if !open {
+					return
+				}
+				ok = render(event)
+			case <-heartbeat.C:
+				ok = write(func() error {
+					_, err := c.Writer.WriteString(": ping\n\n")
+					return err
+				})
+			}
+		}
+	}
+}
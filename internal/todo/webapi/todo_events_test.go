@@ -0,0 +1,188 @@
+package webapi
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"workit-sample/internal/todo/application/todo"
+	domain "workit-sample/internal/todo/domain/todo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var alice = domain.Principal{TenantID: domain.DefaultTenant, UserID: "alice"}
+
+// withUser 代替认证中间件设置当前用户
+func withUser(user domain.Principal) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(userContextKey, user)
+		c.Next()
+	}
+}
+
+func newEventServer(t *testing.T) (*httptest.Server, *todo.EventStream) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	stream := todo.NewEventStream(zap.NewNop())
+
+	var once sync.Once
+
+	router := gin.New()
+	router.GET("/todos/events", withUser(alice), closeOnShutdown(&once, stream.Close), TodoEventsHandler(stream, zap.NewNop()))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server, stream
+}
+
+// subscribe 建立事件流连接,读取首个 retry 块后返回,此时订阅已生效
+func subscribe(t *testing.T, server *httptest.Server, lastEventID string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/todos/events", nil)
+
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := server.Client().Do(req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("unexpected response %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	r := bufio.NewReader(resp.Body)
+
+	if block := readBlock(t, r); block["retry"] == "" {
+		t.Fatalf("expected retry block first, got %v", block)
+	}
+
+	return resp, r
+}
+
+// readBlock 读取一个以空行结束的事件块,返回字段与值
+func readBlock(t *testing.T, r *bufio.Reader) map[string]string {
+	t.Helper()
+
+	block := make(map[string]string)
+
+	for {
+		line, err := r.ReadString('\n')
+
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+
+		line = strings.TrimRight(line, "\n")
+
+		if line == "" {
+			return block
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		block[field] = strings.TrimSpace(value)
+	}
+}
+
+func publishCreated(t *testing.T, stream *todo.EventStream) {
+	t.Helper()
+
+	entity, err := domain.NewTodo(uuid.New(), alice, "Buy milk")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, event := range entity.ClearEvents() {
+		if err := stream.Handle(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTodoEventsStreamAndResume(t *testing.T) {
+
+	server, stream := newEventServer(t)
+
+	_, r := subscribe(t, server, "")
+
+	publishCreated(t, stream)
+
+	first := readBlock(t, r)
+
+	if first["event"] != domain.EventTodoCreated || first["id"] == "" || !strings.Contains(first["data"], `"title":"Buy milk"`) {
+		t.Fatalf("unexpected event %v", first)
+	}
+
+	// 断线期间产生的事件在重连时通过 Last-Event-ID 补发
+	publishCreated(t, stream)
+
+	_, r = subscribe(t, server, first["id"])
+
+	if missed := readBlock(t, r); missed["event"] != domain.EventTodoCreated || missed["id"] == first["id"] {
+		t.Fatalf("expected the missed event, got %v", missed)
+	}
+
+	// 无法续传时推送 reset
+	_, r = subscribe(t, server, "unknown-1")
+
+	if reset := readBlock(t, r); reset["event"] != eventReset || reset["data"] != "unknown-1" {
+		t.Fatalf("expected reset event, got %v", reset)
+	}
+}
+
+// HTTP 服务开始关闭时结束事件流,不会阻塞优雅关闭
+func TestTodoEventsEndOnShutdown(t *testing.T) {
+
+	server, _ := newEventServer(t)
+
+	resp, r := subscribe(t, server, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- server.Config.Shutdown(ctx)
+	}()
+
+	ended := make(chan error, 1)
+
+	go func() {
+		_, err := io.ReadAll(r)
+		ended <- err
+	}()
+
+	select {
+	case err := <-ended:
+		if err != nil {
+			t.Fatalf("expected stream to end cleanly, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		resp.Body.Close()
+		t.Fatal("expected stream to end when the server shuts down")
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
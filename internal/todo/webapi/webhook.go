@@ -0,0 +1,273 @@
+package webapi
+
+import (
+	"workit-sample/internal/todo/application/webhook"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func RegisterWebhookRoutes(
+	router *gin.Engine, //gin
+	log *zap.Logger, // 日志
+	create *webhook.CreateWebhookCommandHandler, // 创建
+	list *webhook.WebhookListQueryHandler, // 列表
+	query *webhook.WebhookQueryHandler, // 查询
+	update *webhook.UpdateWebhookCommandHandler, // 更新
+	deleteWebhook *webhook.DeleteWebhookCommandHandler, // 删除
+	deliveries *webhook.DeliveryListQueryHandler, // 投递记录
+	redeliver *webhook.RedeliverCommandHandler, // 重新投递
+) {
+
+	group := router.Group("/webhooks")
+
+	group.POST("", CreateWebhookHandler(create, log))
+	group.GET("", WebhookListQueryHandler(list, log))
+	group.GET("/:id", WebhookQueryHandler(query, log))
+	group.PUT("/:id", UpdateWebhookHandler(update, log))
+	group.DELETE("/:id", DeleteWebhookHandler(deleteWebhook, log))
+	group.GET("/:id/deliveries", DeliveryListQueryHandler(deliveries, log))
+	group.POST("/:id/deliveries/:deliveryId/redeliver", RedeliverHandler(redeliver, log))
+}
+
+// CreateWebhookHandler godoc
+// @Summary 创建Webhook
+// @Description 订阅当前用户待办事项与任务的生命周期事件,事件发生后向目标地址 POST 签名的 JSON。
+// @Description 签名为 X-Webhook-Signature-256: sha256=<HMAC-SHA256(secret, body)>,密钥仅在创建时返回
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param data body webhook.CreateWebhookCommand true "请求参数"
+// @Success 200 {object} Response[webhook.WebhookDTO]
+// @Failure 400 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /webhooks [post]
+func CreateWebhookHandler(handler *webhook.CreateWebhookCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var cmd webhook.CreateWebhookCommand
+
+		if err := c.ShouldBindJSON(&cmd); err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.Principal = userOf(c)
+
+		result, err := handler.Handle(cmd)
+
+		if err != nil {
+			log.Error("create webhook error", zap.Error(err))
+			FailWithError(c, actionCreate, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// WebhookListQueryHandler godoc
+// @Summary 查询Webhook列表
+// @Description 查询所有 Webhook 订阅
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {object} Response[[]webhook.WebhookDTO]
+// @Failure 500 {object} Response[any]
+// @Router /webhooks [get]
+func WebhookListQueryHandler(handler *webhook.WebhookListQueryHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		result, err := handler.Handle(userOf(c))
+		if err != nil {
+			log.Error("query webhooks error", zap.Error(err))
+			FailWithError(c, actionQuery, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// WebhookQueryHandler godoc
+// @Summary 查询Webhook
+// @Description 查询指定ID的 Webhook 订阅
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} Response[webhook.WebhookDTO]
+// @Failure 400 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /webhooks/{id} [get]
+func WebhookQueryHandler(handler *webhook.WebhookQueryHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var query webhook.WebhookQuery
+
+		if err := c.ShouldBindUri(&query); err != nil {
+			log.Error("uri bind error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		query.Principal = userOf(c)
+
+		result, err := handler.Handle(query)
+		if err != nil {
+			log.Error("query webhook error", zap.Error(err))
+			FailWithError(c, actionQuery, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// UpdateWebhookHandler godoc
+// @Summary 更新Webhook
+// @Description 修改 Webhook 的目标地址、订阅事件或启用状态
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param data body webhook.UpdateWebhookCommand true "请求参数"
+// @Success 200 {object} Response[webhook.WebhookDTO]
+// @Failure 400 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /webhooks/{id} [put]
+func UpdateWebhookHandler(handler *webhook.UpdateWebhookCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var cmd webhook.UpdateWebhookCommand
+
+		if err := shouldBindUriAndJSON(c, &cmd); err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.Principal = userOf(c)
+
+		result, err := handler.Handle(cmd)
+		if err != nil {
+			log.Error("update webhook error", zap.Error(err))
+			FailWithError(c, actionUpdate, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// DeleteWebhookHandler godoc
+// @Summary 删除Webhook
+// @Description 删除 Webhook 订阅及其投递记录
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} Response[bool]
+// @Failure 400 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /webhooks/{id} [delete]
+func DeleteWebhookHandler(handler *webhook.DeleteWebhookCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var cmd webhook.DeleteWebhookCommand
+
+		if err := c.ShouldBindUri(&cmd); err != nil {
+			log.Error("uri bind error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.Principal = userOf(c)
+
+		result, err := handler.Handle(cmd)
+		if err != nil {
+			log.Error("delete webhook error", zap.Error(err))
+			FailWithError(c, actionDelete, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// DeliveryListQueryHandler godoc
+// @Summary 查询投递记录
+// @Description 按创建时间倒序查询 Webhook 的投递记录
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param limit query int false "返回条数,默认20,最大100"
+// @Success 200 {object} Response[[]webhook.DeliveryDTO]
+// @Failure 400 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /webhooks/{id}/deliveries [get]
+func DeliveryListQueryHandler(handler *webhook.DeliveryListQueryHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var query webhook.DeliveryListQuery
+
+		if err := c.ShouldBindUri(&query); err != nil {
+			log.Error("uri bind error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		if err := c.ShouldBindQuery(&query); err != nil {
+			log.Error("params error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		query.Principal = userOf(c)
+
+		result, err := handler.Handle(query)
+		if err != nil {
+			log.Error("query deliveries error", zap.Error(err))
+			FailWithError(c, actionQuery, err)
+			return
+		}
+		Success(c, result)
+	}
+}
+
+// RedeliverHandler godoc
+// @Summary 重新投递
+// @Description 以原请求体创建新的投递,由后台按正常流程发送
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "投递记录ID"
+// @Success 200 {object} Response[webhook.DeliveryDTO]
+// @Failure 400 {object} Response[any]
+// @Failure 404 {object} Response[any]
+// @Failure 500 {object} Response[any]
+// @Router /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func RedeliverHandler(handler *webhook.RedeliverCommandHandler, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var cmd webhook.RedeliverCommand
+
+		if err := c.ShouldBindUri(&cmd); err != nil {
+			log.Error("uri bind error", zap.Error(err))
+			FailWithValidation(c, err)
+			return
+		}
+
+		cmd.Principal = userOf(c)
+
+		result, err := handler.Handle(cmd)
+		if err != nil {
+			log.Error("redeliver error", zap.Error(err))
+			FailWithError(c, actionRedeliver, err)
+			return
+		}
+		Success(c, result)
+	}
+}
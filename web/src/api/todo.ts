@@ -1,7 +1,16 @@
-import type { CreateTodoRequest, CreateTodoResponse, Todo } from '../types/todo';
+import { TODO_EVENT_NAMES } from '../types/todo';
+import type { CreateTodoRequest, CreateTodoResponse, MemberRole, PagedResult, Todo, TodoEvent, TodoEventName, TodoListParams, TodoMember } from '../types/todo';
 
 const API_BASE = 'http://localhost:8081'; // 动态化基础 URL
 
+// 登录后保存的访问令牌
+const ACCESS_TOKEN_KEY = 'access_token';
+
+function authHeaders(): Record<string, string> {
+  const token = localStorage.getItem(ACCESS_TOKEN_KEY);
+  return token ? { Authorization: `Bearer ${token}` } : {};
+}
+
 export const todoApi = {
   async create(data: CreateTodoRequest): Promise<CreateTodoResponse> {
     const response = await fetch(`${API_BASE}/todos`, {
@@ -18,8 +27,16 @@ export const todoApi = {
     return result.data;
   },
 
-  async list(): Promise<Todo[]> {
-    const response = await fetch(`${API_BASE}/todos`);
+  async list(params: TodoListParams = {}): Promise<PagedResult<Todo>> {
+    const query = new URLSearchParams();
+    if (params.title) query.set('title', params.title);
+    if (params.page) query.set('page', String(params.page));
+    if (params.size) query.set('size', String(params.size));
+    if (params.mode) query.set('mode', params.mode);
+    if (params.cursor) query.set('cursor', params.cursor);
+    if (params.due) query.set('due', params.due);
+    if (params.tz) query.set('tz', params.tz);
+    const response = await fetch(`${API_BASE}/todos?${query.toString()}`);
     const result = await response.json();
     if (result.code !== 0) {
       throw new Error(result.message || '获取待办事项列表失败');
@@ -81,4 +98,133 @@ export const todoApi = {
       throw new Error(result.message || '标记任务完成失败');
     }
   },
+
+  async members(id: string): Promise<TodoMember[]> {
+    const response = await fetch(`${API_BASE}/todos/${id}/members`);
+    const result = await response.json();
+    if (result.code !== 0) {
+      throw new Error(result.message || '获取成员失败');
+    }
+    return result.data;
+  },
+
+  async inviteMember(id: string, data: { userId: string; role: MemberRole }): Promise<void> {
+    const response = await fetch(`${API_BASE}/todos/${id}/members`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(data),
+    });
+    const result = await response.json();
+    if (result.code !== 0) {
+      throw new Error(result.message || '邀请成员失败');
+    }
+  },
+
+  async changeMemberRole(id: string, userId: string, role: MemberRole): Promise<void> {
+    const response = await fetch(`${API_BASE}/todos/${id}/members/${encodeURIComponent(userId)}`, {
+      method: 'PUT',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({ role }),
+    });
+    const result = await response.json();
+    if (result.code !== 0) {
+      throw new Error(result.message || '修改成员角色失败');
+    }
+  },
+
+  async revokeMember(id: string, userId: string): Promise<void> {
+    const response = await fetch(`${API_BASE}/todos/${id}/members/${encodeURIComponent(userId)}`, {
+      method: 'DELETE',
+    });
+    const result = await response.json();
+    if (result.code !== 0) {
+      throw new Error(result.message || '移除成员失败');
+    }
+  },
+
+  // 订阅变更事件，断线后携带 Last-Event-ID 续传；无法续传时收到 reset，需重新加载数据
+  // EventSource 无法设置 Authorization 请求头，因此用 fetch 读取事件流
+  subscribe(
+    handlers: { onEvent: (name: TodoEventName, event: TodoEvent) => void; onReset: () => void },
+    todoId?: string,
+  ): () => void {
+    const query = new URLSearchParams();
+    if (todoId) query.set('todoId', todoId);
+    const controller = new AbortController();
+    let lastEventId = '';
+    let retry = 3000;
+
+    const dispatch = (name: string, data: string) => {
+      if (name === 'reset') {
+        handlers.onReset();
+        return;
+      }
+      if ((TODO_EVENT_NAMES as readonly string[]).includes(name)) {
+        handlers.onEvent(name as TodoEventName, JSON.parse(data));
+      }
+    };
+
+    const connect = async () => {
+      const headers: Record<string, string> = { ...authHeaders(), Accept: 'text/event-stream' };
+      if (lastEventId) headers['Last-Event-ID'] = lastEventId;
+      const response = await fetch(`${API_BASE}/todos/events?${query.toString()}`, {
+        headers,
+        signal: controller.signal,
+      });
+      if (!response.ok || !response.body) {
+        throw new Error(`订阅失败: ${response.status}`);
+      }
+      const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+      let buffer = '';
+      for (;;) {
+        const { value, done } = await reader.read();
+        if (done) return;
+        buffer += value.replace(/\r\n?/g, '\n');
+        let end: number;
+        while ((end = buffer.indexOf('\n\n')) >= 0) {
+          const block = buffer.slice(0, end);
+          buffer = buffer.slice(end + 2);
+          let name = 'message';
+          const data: string[] = [];
+          for (const line of block.split('\n')) {
+            const colon = line.indexOf(':');
+            if (colon === 0) continue;
+            const field = colon < 0 ? line : line.slice(0, colon);
+            const text = colon < 0 ? '' : line.slice(colon + 1).replace(/^ /, '');
+            if (field === 'event') name = text;
+            else if (field === 'data') data.push(text);
+            else if (field === 'id') lastEventId = text;
+            else if (field === 'retry' && /^\d+$/.test(text)) retry = Number(text);
+          }
+          if (data.length > 0) dispatch(name, data.join('\n'));
+        }
+      }
+    };
+
+    // 连接结束或失败后按服务端的 retry 间隔重连，直到取消订阅
+    const run = async () => {
+      while (!controller.signal.aborted) {
+        try {
+          await connect();
+        } catch {
+          // 取消订阅或网络错误，由循环条件决定是否重连
+        }
+        if (controller.signal.aborted) return;
+        await new Promise((resolve) => setTimeout(resolve, retry));
+      }
+    };
+    void run();
+
+    return () => controller.abort();
+  },
+
+  // 加入协作房间，浏览器的 WebSocket 无法设置 Authorization 请求头，访问令牌通过 bearer 子协议传递
+  collaborate(id: string): WebSocket {
+    const token = localStorage.getItem(ACCESS_TOKEN_KEY);
+    return new WebSocket(`${API_BASE.replace(/^http/, 'ws')}/todos/${id}/collaborate`, token ? ['bearer', token] : []);
+  },
 };
@@ -1,5 +1,5 @@
-import { useState } from 'react';
-import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { useEffect, useState, type UIEvent } from 'react';
+import { useInfiniteQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import { Input, Button, Badge, Typography, List, Empty, Row, Col, Modal, Form, message as antMessage, Card, Checkbox } from 'antd';
 import { todoApi } from '../api/todo';
 import type { CreateTodoRequest, Todo } from '../types/todo';
@@ -14,11 +14,21 @@ export default function TodoList() {
   const [selectedTodo, setSelectedTodo] = useState<Todo | null>(null);
   const queryClient = useQueryClient();
 
-  const { data: todos = [], isLoading, error } = useQuery({
+  // 游标分页，滚动到底部时加载下一页，并发新增时翻页结果保持稳定
+  const { data, isLoading, error, fetchNextPage, hasNextPage, isFetchingNextPage } = useInfiniteQuery({
     queryKey: ['todos'],
-    queryFn: todoApi.list,
-    initialData: [],
+    queryFn: ({ pageParam }) => todoApi.list({ mode: 'cursor', cursor: pageParam, size: 20 }),
+    initialPageParam: '',
+    getNextPageParam: (lastPage) => lastPage.nextCursor,
   });
+  const todos = data?.pages.flatMap((page) => page.items) ?? [];
+
+  const handleListScroll = (e: UIEvent<HTMLDivElement>) => {
+    const { scrollTop, scrollHeight, clientHeight } = e.currentTarget;
+    if (scrollHeight - scrollTop - clientHeight < 50 && hasNextPage && !isFetchingNextPage) {
+      fetchNextPage();
+    }
+  };
 
   const createTodoMutation = useMutation({
     mutationFn: (data: CreateTodoRequest) => todoApi.create(data),
@@ -100,6 +110,33 @@ export default function TodoList() {
     });
   };
 
+  // 订阅全部变更，其他人的修改实时刷新列表
+  useEffect(() => {
+    const refresh = () => queryClient.invalidateQueries({ queryKey: ['todos'] });
+    return todoApi.subscribe({ onEvent: refresh, onReset: refresh });
+  }, [queryClient]);
+
+  // 订阅选中待办事项的变更，刷新详情
+  const selectedTodoId = selectedTodo?.id;
+  useEffect(() => {
+    if (!selectedTodoId) return;
+    return todoApi.subscribe(
+      {
+        onEvent: (name) => {
+          if (name === 'todo.deleted') {
+            setSelectedTodo(null);
+            return;
+          }
+          fetchTodoMutation.mutate(selectedTodoId);
+        },
+        onReset: () => fetchTodoMutation.mutate(selectedTodoId),
+      },
+      selectedTodoId,
+    );
+    // fetchTodoMutation 每次渲染都会变化，只在切换待办事项时重新订阅
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [selectedTodoId]);
+
   // 修改点击待办事项的逻辑
   const handleSelectTodo = (todo: Todo) => {
     fetchTodoMutation.mutate(todo.id);
@@ -122,35 +159,36 @@ export default function TodoList() {
         >
           添加待办事项
         </Button>
-        <List
-          style={{ height: 'calc(100vh - 180px)', overflowY: 'auto' }}
-          loading={isLoading}
-          dataSource={todos}
-          renderItem={(todo) => (
-            <List.Item
-              onClick={() => handleSelectTodo(todo)} // 调用新的点击逻辑
-              style={{
-                padding: '12px 16px',
-                cursor: 'pointer',
-                borderRadius: 6,
-                backgroundColor: selectedTodo?.id === todo.id ? '#e6f4ff' : 'transparent',
-                border: selectedTodo?.id === todo.id ? '1px solid #1677ff' : '1px solid transparent',
-                marginBottom: 8,
-              }}
-            >
-              <div style={{ width: '100%', display: 'flex', justifyContent: 'space-between', alignItems: 'center' }}>
-                <span style={{ fontWeight: 'bold', color: todo.completed ? '#52c41a' : '#000' }}>
-                  {todo.title}
-                </span>
-                <Badge
-                  status={todo.completed ? 'success' : 'default'} // 使用 Badge 显示完成状态
-                  text={todo.completed ? '已完成' : '未完成'}
-                />
-              </div>
-            </List.Item>
-          )}
-          locale={{ emptyText: error ? '加载失败' : '暂无待办事项' }}
-        />
+        <div style={{ height: 'calc(100vh - 180px)', overflowY: 'auto' }} onScroll={handleListScroll}>
+          <List
+            loading={isLoading || isFetchingNextPage}
+            dataSource={todos}
+            renderItem={(todo) => (
+              <List.Item
+                onClick={() => handleSelectTodo(todo)} // 调用新的点击逻辑
+                style={{
+                  padding: '12px 16px',
+                  cursor: 'pointer',
+                  borderRadius: 6,
+                  backgroundColor: selectedTodo?.id === todo.id ? '#e6f4ff' : 'transparent',
+                  border: selectedTodo?.id === todo.id ? '1px solid #1677ff' : '1px solid transparent',
+                  marginBottom: 8,
+                }}
+              >
+                <div style={{ width: '100%', display: 'flex', justifyContent: 'space-between', alignItems: 'center' }}>
+                  <span style={{ fontWeight: 'bold', color: todo.completed ? '#52c41a' : '#000' }}>
+                    {todo.title}
+                  </span>
+                  <Badge
+                    status={todo.completed ? 'success' : 'default'} // 使用 Badge 显示完成状态
+                    text={todo.completed ? '已完成' : '未完成'}
+                  />
+                </div>
+              </List.Item>
+            )}
+            locale={{ emptyText: error ? '加载失败' : '暂无待办事项' }}
+          />
+        </div>
       </Col>
 
       {/* 右侧内容区域 */}
@@ -3,22 +3,90 @@ export interface Todo {
   title: string;
   description?: string;
   completed: boolean;
+  version: number;
+  ownerId: string;
+  role?: MemberRole; // 当前用户的角色
+  dueAt?: string | null; // 截止时间，ISO 8601，按 dueTimeZone 表示
+  dueTimeZone?: string;
+  overdue: boolean;
+  recurrence?: string; // 重复规则(RRULE)
+  seriesId?: string;
+  occurrence?: number;
+  nextOccurrenceId?: string;
   tasks: TodoTask[];
 }
 
+export type MemberRole = 'viewer' | 'editor' | 'owner';
+
+export interface TodoMember {
+  userId: string;
+  role: MemberRole;
+  creator: boolean; // 创建者的角色不能修改
+  createdAt: string;
+}
+
 export interface TodoTask {
   id: string;
   todoId: string;
   title: string;
   description: string;
   completed: boolean;
+  dueAt?: string | null;
+  dueTimeZone?: string;
+  overdue: boolean;
 }
 
 export interface CreateTodoRequest {
   title: string;
   description?: string;
+  dueAt?: string;
+  timeZone?: string;
+  recurrence?: string; // daily、weekly、monthly、yearly 或 RRULE
 }
 
 export interface CreateTodoResponse {
   success: boolean;
 }
+
+export interface PagedResult<T> {
+  items: T[];
+  total: number;
+  page?: number;
+  size: number;
+  nextCursor?: string;
+  prevCursor?: string;
+}
+
+export interface TodoListParams {
+  title?: string;
+  page?: number;
+  size?: number;
+  mode?: 'offset' | 'cursor';
+  cursor?: string;
+  due?: 'overdue' | 'today' | 'week';
+  tz?: string;
+}
+
+// 服务端推送的事件名称，与后端领域事件一致
+export const TODO_EVENT_NAMES = [
+  'todo.created',
+  'todo.updated',
+  'todo.task_added',
+  'todo.task_completed',
+  'todo.task_removed',
+  'todo.completed',
+  'todo.deleted',
+  'todo.recurred',
+  'todo.member_invited',
+  'todo.member_role_changed',
+  'todo.member_revoked',
+  'todo.due_reminder',
+  'todo.task_due_reminder',
+] as const;
+
+export type TodoEventName = (typeof TODO_EVENT_NAMES)[number];
+
+export interface TodoEvent {
+  todoId: string;
+  [key: string]: unknown;
+}